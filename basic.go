@@ -0,0 +1,181 @@
+package lg
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Basic is a minimal logging interface. A third-party logger that
+// implements Basic can be used as a Log via Upgrade.
+type Basic interface {
+	// Debugf logs at DEBUG level.
+	Debugf(format string, a ...any)
+
+	// Warnf logs at WARN level.
+	Warnf(format string, a ...any)
+
+	// Errorf logs at ERROR level.
+	Errorf(format string, a ...any)
+}
+
+// Upgrade returns a Log that logs to b, implementing the remaining
+// Log methods generically. If b is already a Log, it is returned
+// unchanged. Fields added via With are appended to each message, in
+// key=val format; adding a field with the same key as an existing
+// field replaces its value. If b has method Enabled(level Level) bool, the
+// returned Log reports it via Enabled. If b has method
+// AddCallerSkip(skip int) Basic, it is used to account for the
+// returned Log's frame, so that the caller is reported correctly.
+func Upgrade(b Basic) Log {
+	if b == nil {
+		return nil
+	}
+
+	if log, ok := b.(Log); ok {
+		return log
+	}
+
+	u := &upgraded{orig: b, b: b}
+	if skipper, ok := b.(interface{ AddCallerSkip(skip int) Basic }); ok {
+		u.b = skipper.AddCallerSkip(1)
+	}
+
+	return u
+}
+
+// upgraded implements Log for a Basic. The methods must invoke
+// b directly, for the caller to be correct.
+type upgraded struct {
+	// orig is the Basic passed to Upgrade.
+	orig Basic
+
+	// b is orig with caller skip applied.
+	b Basic
+
+	// fields holds the fields added via With. They are rendered
+	// for each entry, so that LazyValue and PII values are
+	// evaluated at emission time.
+	fields []Field
+
+	// skip is the additional caller skip.
+	skip int
+}
+
+// Enabled reports whether level is enabled for the underlying Basic.
+func (u *upgraded) Enabled(level Level) bool {
+	if e, ok := u.orig.(enabler); ok {
+		return e.Enabled(level)
+	}
+
+	return true
+}
+
+// AddCallerSkip implements the optional interface used by AddCallerSkip.
+func (u *upgraded) AddCallerSkip(skip int) Log {
+	child := *u
+	child.skip += skip
+	if skipper, ok := u.orig.(interface{ AddCallerSkip(skip int) Basic }); ok {
+		child.b = skipper.AddCallerSkip(1 + child.skip)
+	}
+
+	return &child
+}
+
+func (u *upgraded) Debug(a ...any) {
+	u.b.Debugf("%s%s", fmt.Sprint(a...), u.suffix())
+}
+
+func (u *upgraded) Debugf(format string, a ...any) {
+	u.b.Debugf("%s%s", fmt.Sprintf(format, a...), u.suffix())
+}
+
+func (u *upgraded) Warn(a ...any) {
+	u.b.Warnf("%s%s", fmt.Sprint(a...), u.suffix())
+}
+
+func (u *upgraded) Warnf(format string, a ...any) {
+	u.b.Warnf("%s%s", fmt.Sprintf(format, a...), u.suffix())
+}
+
+func (u *upgraded) WarnIfError(err error) {
+	if err != nil {
+		u.b.Warnf("%s%s", err, fieldsSuffix(u.suffix(), ErrorFields(err)))
+	}
+}
+
+func (u *upgraded) WarnIfFuncError(fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	if err := fn(); err != nil {
+		u.b.Warnf("%s%s", err, fieldsSuffix(u.suffix(), ErrorFields(err)))
+	}
+}
+
+func (u *upgraded) WarnIfCloseError(c io.Closer) {
+	if c == nil {
+		return
+	}
+
+	if err := c.Close(); err != nil {
+		u.b.Warnf("%s%s", err, fieldsSuffix(u.suffix(), ErrorFields(err)))
+	}
+}
+
+func (u *upgraded) Error(a ...any) {
+	u.b.Errorf("%s%s", fmt.Sprint(a...), u.suffix())
+}
+
+func (u *upgraded) Errorf(format string, a ...any) {
+	u.b.Errorf("%s%s", fmt.Sprintf(format, a...), u.suffix())
+}
+
+func (u *upgraded) With(key string, val any) Log {
+	key = ValidKey(u, key)
+
+	child := *u
+	child.fields = append(make([]Field, 0, len(u.fields)+1), u.fields...)
+	replaced := false
+	for i := range child.fields {
+		if child.fields[i].Key == key {
+			child.fields[i].Val = val
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		child.fields = append(child.fields, Field{Key: key, Val: val})
+	}
+	return &child
+}
+
+// suffix returns u's fields rendered via fieldsSuffix.
+func (u *upgraded) suffix() string {
+	return fieldsSuffix("", u.fields)
+}
+
+// fieldsSuffix returns suffix with fields appended in " key=val"
+// format. Values that are empty or contain a space or '"' are quoted.
+func fieldsSuffix(suffix string, fields []Field) string {
+	if len(fields) == 0 {
+		return suffix
+	}
+
+	var sb strings.Builder
+	sb.WriteString(suffix)
+	for _, f := range fields {
+		v := fmt.Sprint(f.Val)
+		if v == "" || strings.ContainsAny(v, ` "`) {
+			v = fmt.Sprintf("%q", v)
+		}
+
+		sb.WriteByte(' ')
+		sb.WriteString(f.Key)
+		sb.WriteByte('=')
+		sb.WriteString(v)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,121 @@
+package lg_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// basicLog is a lg.Basic that records each entry,
+// prefixed with its level.
+type basicLog struct {
+	entries []string
+}
+
+func (b *basicLog) Debugf(format string, a ...any) {
+	b.entries = append(b.entries, "DEBUG "+fmt.Sprintf(format, a...))
+}
+
+func (b *basicLog) Warnf(format string, a ...any) {
+	b.entries = append(b.entries, "WARN "+fmt.Sprintf(format, a...))
+}
+
+func (b *basicLog) Errorf(format string, a ...any) {
+	b.entries = append(b.entries, "ERROR "+fmt.Sprintf(format, a...))
+}
+
+type closerFunc func() error
+
+func (fn closerFunc) Close() error { return fn() }
+
+func TestUpgrade(t *testing.T) {
+	require.Nil(t, lg.Upgrade(nil))
+
+	zlog := zaplg.New()
+	require.Same(t, zlog, lg.Upgrade(zlog))
+
+	b := &basicLog{}
+	log := lg.Upgrade(b)
+	require.True(t, lg.Enabled(log, lg.LevelDebug))
+
+	log.Debug("debug", 1)
+	log.With("user", "alice").With("note", "a b").Debugf("debugf %d", 2)
+	log.With("user", "alice").With("note", "a b").With("user", "bob").Debug("replaced")
+	log.Warn("warn")
+	log.Warnf("warnf %%d")
+	log.WarnIfError(nil)
+	log.WarnIfError(errors.New("err"))
+	log.WarnIfFuncError(nil)
+	log.WarnIfFuncError(func() error { return errors.New("func err") })
+	log.WarnIfCloseError(nil)
+	log.With("k", "v").WarnIfCloseError(closerFunc(func() error { return errors.New("close err") }))
+	log.Error("error")
+	lg.AddCallerSkip(log, 1).Errorf("errorf")
+
+	require.Equal(t, []string{
+		"DEBUG debug1",
+		`DEBUG debugf 2 user=alice note="a b"`,
+		`DEBUG replaced user=bob note="a b"`,
+		"WARN warn",
+		"WARN warnf %d",
+		"WARN err",
+		"WARN func err",
+		"WARN close err k=v",
+		"ERROR error",
+		"ERROR errorf",
+	}, b.entries)
+}
+
+// skipBasicLog is a lg.Basic that supports caller skip and
+// Enabled, recording the skip with each entry.
+type skipBasicLog struct {
+	entries *[]string
+	skip    int
+}
+
+func (b *skipBasicLog) record(format string, a ...any) {
+	*b.entries = append(*b.entries, fmt.Sprintf("skip=%d ", b.skip)+fmt.Sprintf(format, a...))
+}
+
+func (b *skipBasicLog) Debugf(format string, a ...any) { b.record(format, a...) }
+func (b *skipBasicLog) Warnf(format string, a ...any)  { b.record(format, a...) }
+func (b *skipBasicLog) Errorf(format string, a ...any) { b.record(format, a...) }
+
+func (b *skipBasicLog) AddCallerSkip(skip int) lg.Basic {
+	return &skipBasicLog{entries: b.entries, skip: b.skip + skip}
+}
+
+func (b *skipBasicLog) Enabled(level lg.Level) bool {
+	return level >= lg.LevelWarn
+}
+
+func TestUpgrade_Optional(t *testing.T) {
+	var entries []string
+	log := lg.Upgrade(&skipBasicLog{entries: &entries})
+	require.False(t, lg.Enabled(log, lg.LevelDebug))
+	require.True(t, lg.Enabled(log, lg.LevelWarn))
+
+	log.Warn("a")
+	lg.AddCallerSkip(lg.AddCallerSkip(log, 1), 2).With("k", "v").Error("b")
+	require.Equal(t, []string{"skip=1 a", "skip=4 b k=v"}, entries)
+}
+
+func TestUpgrade_Lazy(t *testing.T) {
+	var calls int
+	b := &basicLog{}
+	log := lg.Upgrade(b).With("n", lg.Lazy(func() any {
+		calls++
+		return calls
+	}))
+	require.Equal(t, 0, calls)
+
+	log.Debug("a")
+	log.Warn("b")
+	require.Equal(t, 2, calls)
+	require.Equal(t, []string{"DEBUG a n=1", "WARN b n=2"}, b.entries)
+}
@@ -0,0 +1,354 @@
+package lg
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBudgetWindow is the default window of a Budgeted log.
+const DefaultBudgetWindow = time.Minute
+
+// BudgetOption is a functional option for NewBudgeted.
+type BudgetOption func(b *budget)
+
+// WithBudgetEntries returns a BudgetOption that sets the maximum
+// number of entries per window. If n is zero (the default), the
+// number of entries is not limited.
+func WithBudgetEntries(n int) BudgetOption {
+	return func(b *budget) {
+		if n >= 0 {
+			b.maxEntries = n
+		}
+	}
+}
+
+// WithBudgetBytes returns a BudgetOption that sets the maximum number
+// of bytes per window. The size of an entry is approximated as the
+// length of its message, plus the lengths of its fields' keys and
+// values (as per fmt.Sprint). The values of Lazy fields are not
+// counted, so that they are evaluated only when the entry is emitted.
+// If n is zero (the default), the number of bytes is not limited.
+func WithBudgetBytes(n int) BudgetOption {
+	return func(b *budget) {
+		if n >= 0 {
+			b.maxBytes = n
+		}
+	}
+}
+
+// WithBudgetWindow returns a BudgetOption that sets the window
+// over which the budget applies. The default is DefaultBudgetWindow.
+func WithBudgetWindow(d time.Duration) BudgetOption {
+	return func(b *budget) {
+		if d > 0 {
+			b.window = d
+		}
+	}
+}
+
+// Budgeted is a Log that enforces a log volume budget, protecting
+// against cost blowups in hosted log platforms. It tracks the entries
+// and bytes emitted per window; when the budget is exceeded, DEBUG
+// entries are dropped until the end of the window, and a single WARN
+// notice is logged. WARN and ERROR entries are always logged, and
+// count toward the budget.
+//
+//	log = lg.NewBudgeted(log, lg.WithBudgetEntries(10000), lg.WithBudgetBytes(4<<20))
+//
+// The child logs returned by With share the budget of their
+// parent. Budgeted is safe for concurrent use.
+type Budgeted struct {
+	log Log
+	b   *budget
+
+	// fields are the fields added via With, retained only
+	// if the size of entries is budgeted.
+	fields []Field
+}
+
+// budget is the state shared by a Budgeted and its children.
+type budget struct {
+	maxEntries, maxBytes int
+	window               time.Duration
+
+	mu          sync.Mutex
+	windowStart time.Time
+	entries     int
+	bytes       int
+	exceeded    bool
+
+	dropped atomic.Uint64
+}
+
+// NewBudgeted returns a Budgeted that writes to log.
+func NewBudgeted(log Log, opts ...BudgetOption) *Budgeted {
+	b := &budget{window: DefaultBudgetWindow}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	// The caller skip accounts for the frame of Budgeted's methods.
+	return &Budgeted{log: AddCallerSkip(OrDiscard(log), 1), b: b}
+}
+
+// Dropped returns the number of DEBUG entries dropped
+// because the budget was exceeded.
+func (l *Budgeted) Dropped() uint64 {
+	return l.b.dropped.Load()
+}
+
+// Enabled reports whether level is enabled for the underlying
+// log. DEBUG is not enabled while the budget is exceeded.
+func (l *Budgeted) Enabled(level Level) bool {
+	if level == LevelDebug && l.b.isExceeded() {
+		return false
+	}
+
+	return Enabled(l.log, level)
+}
+
+// AddCallerSkip implements the optional interface used by AddCallerSkip.
+func (l *Budgeted) AddCallerSkip(skip int) Log {
+	return &Budgeted{log: AddCallerSkip(l.log, skip), b: l.b, fields: l.fields}
+}
+
+// isExceeded reports whether the budget is exceeded
+// in the current window.
+func (b *budget) isExceeded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.roll(time.Now())
+	return b.exceeded
+}
+
+// roll starts a new window, if the current window has ended.
+func (b *budget) roll(now time.Time) {
+	if now.Sub(b.windowStart) >= b.window {
+		b.windowStart = now
+		b.entries, b.bytes = 0, 0
+		b.exceeded = false
+	}
+}
+
+// add accounts for an entry of size bytes at level. It returns false
+// if the entry should be dropped, and true for notice if the entry
+// exceeded the budget, and thus the notice should be logged.
+func (b *budget) add(level Level, size int) (ok, notice bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.roll(time.Now())
+	if b.exceeded && level == LevelDebug {
+		b.dropped.Add(1)
+		return false, false
+	}
+
+	b.entries++
+	b.bytes += size
+	if !b.exceeded && ((b.maxEntries > 0 && b.entries > b.maxEntries) ||
+		(b.maxBytes > 0 && b.bytes > b.maxBytes)) {
+		b.exceeded = true
+		notice = true
+		if level == LevelDebug {
+			b.dropped.Add(1)
+			return false, notice
+		}
+	}
+
+	return true, notice
+}
+
+// admit accounts for an entry at level with message msg, returning
+// false if the entry should be dropped. Entries at a level that is
+// not enabled for the underlying log are dropped, and don't count
+// toward the budget. If the entry exceeded the budget, the notice is
+// logged. It must be invoked directly by the methods of Budgeted,
+// for the notice's caller to be correct.
+func (l *Budgeted) admit(level Level, msg string) bool {
+	if !Enabled(l.log, level) {
+		return false
+	}
+
+	size := 0
+	if l.needMsg() {
+		// Lazy values are not rendered here, as the underlying
+		// log evaluates them (again) when the entry is emitted.
+		size = len(msg)
+		for _, f := range l.fields {
+			size += len(f.Key)
+			if _, ok := f.Val.(LazyValue); !ok {
+				size += len(fmt.Sprint(f.Val))
+			}
+		}
+	}
+
+	ok, notice := l.b.add(level, size)
+	if notice {
+		AddCallerSkip(l.log, 1).With("budget.entries", l.b.maxEntries).
+			With("budget.bytes", l.b.maxBytes).With("budget.window", l.b.window).
+			Warn("lg: log budget exceeded: DEBUG entries dropped until the end of the window")
+	}
+
+	return ok
+}
+
+// needMsg reports whether messages must be formatted
+// to account for their size.
+func (l *Budgeted) needMsg() bool {
+	return l.b.maxBytes > 0
+}
+
+// Debug implements Log.Debug.
+func (l *Budgeted) Debug(a ...any) {
+	if !Enabled(l.log, LevelDebug) {
+		return
+	}
+
+	if !l.needMsg() {
+		if l.admit(LevelDebug, "") {
+			l.log.Debug(a...)
+		}
+		return
+	}
+
+	msg := fmt.Sprint(a...)
+	if l.admit(LevelDebug, msg) {
+		l.log.Debug(msg)
+	}
+}
+
+// Debugf implements Log.Debugf.
+func (l *Budgeted) Debugf(format string, a ...any) {
+	if !Enabled(l.log, LevelDebug) {
+		return
+	}
+
+	if !l.needMsg() {
+		if l.admit(LevelDebug, "") {
+			l.log.Debugf(format, a...)
+		}
+		return
+	}
+
+	msg := fmt.Sprintf(format, a...)
+	if l.admit(LevelDebug, msg) {
+		l.log.Debug(msg)
+	}
+}
+
+// Warn implements Log.Warn.
+func (l *Budgeted) Warn(a ...any) {
+	if !Enabled(l.log, LevelWarn) {
+		return
+	}
+
+	if !l.needMsg() {
+		l.admit(LevelWarn, "")
+		l.log.Warn(a...)
+		return
+	}
+
+	msg := fmt.Sprint(a...)
+	l.admit(LevelWarn, msg)
+	l.log.Warn(msg)
+}
+
+// Warnf implements Log.Warnf.
+func (l *Budgeted) Warnf(format string, a ...any) {
+	if !Enabled(l.log, LevelWarn) {
+		return
+	}
+
+	if !l.needMsg() {
+		l.admit(LevelWarn, "")
+		l.log.Warnf(format, a...)
+		return
+	}
+
+	msg := fmt.Sprintf(format, a...)
+	l.admit(LevelWarn, msg)
+	l.log.Warn(msg)
+}
+
+// WarnIfError implements Log.WarnIfError.
+func (l *Budgeted) WarnIfError(err error) {
+	if err == nil {
+		return
+	}
+
+	l.admit(LevelWarn, err.Error())
+	l.log.WarnIfError(err)
+}
+
+// WarnIfFuncError implements Log.WarnIfFuncError.
+func (l *Budgeted) WarnIfFuncError(fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	if err := fn(); err != nil {
+		l.admit(LevelWarn, err.Error())
+		l.log.WarnIfError(err)
+	}
+}
+
+// WarnIfCloseError implements Log.WarnIfCloseError.
+func (l *Budgeted) WarnIfCloseError(c io.Closer) {
+	if c == nil {
+		return
+	}
+
+	if err := c.Close(); err != nil {
+		l.admit(LevelWarn, err.Error())
+		l.log.WarnIfError(err)
+	}
+}
+
+// Error implements Log.Error.
+func (l *Budgeted) Error(a ...any) {
+	if !Enabled(l.log, LevelError) {
+		return
+	}
+
+	if !l.needMsg() {
+		l.admit(LevelError, "")
+		l.log.Error(a...)
+		return
+	}
+
+	msg := fmt.Sprint(a...)
+	l.admit(LevelError, msg)
+	l.log.Error(msg)
+}
+
+// Errorf implements Log.Errorf.
+func (l *Budgeted) Errorf(format string, a ...any) {
+	if !Enabled(l.log, LevelError) {
+		return
+	}
+
+	if !l.needMsg() {
+		l.admit(LevelError, "")
+		l.log.Errorf(format, a...)
+		return
+	}
+
+	msg := fmt.Sprintf(format, a...)
+	l.admit(LevelError, msg)
+	l.log.Error(msg)
+}
+
+// With implements Log.With. The returned
+// log shares the budget of l.
+func (l *Budgeted) With(key string, val any) Log {
+	child := &Budgeted{log: l.log.With(key, val), b: l.b, fields: l.fields}
+	if l.needMsg() {
+		child.fields = append(l.fields[:len(l.fields):len(l.fields)], Field{Key: key, Val: val})
+	}
+
+	return child
+}
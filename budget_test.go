@@ -0,0 +1,102 @@
+package lg_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestBudgeted_Entries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+	log := lg.NewBudgeted(zlog, lg.WithBudgetEntries(3), lg.WithBudgetWindow(time.Hour))
+
+	for i := 0; i < 3; i++ {
+		log.Debugf("debug %d", i)
+	}
+	require.True(t, lg.Enabled(log, lg.LevelDebug))
+
+	log.With("k", "v").Debug("over budget")
+	log.Debug("dropped")
+	log.Warn("warn")
+	log.Error("error")
+	require.False(t, lg.Enabled(log, lg.LevelDebug))
+	require.True(t, lg.Enabled(log, lg.LevelWarn))
+	require.EqualValues(t, 2, log.Dropped())
+
+	ents := testlg.DecodeJSON(t, buf)
+	require.Len(t, ents, 6)
+	require.Equal(t, "debug 2", ents[2]["message"])
+	require.Equal(t, "warn", ents[3]["level"])
+	require.Contains(t, ents[3]["message"], "log budget exceeded")
+	require.EqualValues(t, 3, ents[3]["budget.entries"])
+	require.Equal(t, "1h0m0s", ents[3]["budget.window"])
+	require.Contains(t, ents[3]["caller"], "budget_test.go")
+	require.Equal(t, "warn", ents[4]["message"])
+	require.Equal(t, "error", ents[5]["message"])
+}
+
+func TestBudgeted_Bytes(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+	log := lg.NewBudgeted(zlog, lg.WithBudgetBytes(20), lg.WithBudgetWindow(time.Hour)).With("key", "val")
+
+	log.Debug("0123456789") // 10 + 6 bytes
+	log.Debug("0123456789") // exceeds
+	ents := testlg.DecodeJSON(t, buf)
+	require.Len(t, ents, 2)
+	require.Equal(t, "0123456789", ents[0]["message"])
+	require.Contains(t, ents[1]["message"], "log budget exceeded")
+	require.Equal(t, "val", ents[1]["key"])
+}
+
+func TestBudgeted_Window(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log := lg.NewBudgeted(zlog, lg.WithBudgetEntries(1), lg.WithBudgetWindow(50*time.Millisecond))
+
+	log.Debug("1")
+	log.Debug("2")
+	log.Debug("3")
+	require.Len(t, testlg.DecodeJSON(t, buf), 2) // "1", and the notice
+
+	time.Sleep(100 * time.Millisecond)
+	log.Debug("new window")
+	ents := testlg.DecodeJSON(t, buf)
+	require.Len(t, ents, 1)
+	require.Equal(t, "new window", ents[0]["message"])
+}
+
+func TestBudgeted_DisabledLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, false, 0, zaplg.WithLevel(lg.LevelWarn))
+	log := lg.NewBudgeted(zlog, lg.WithBudgetEntries(2), lg.WithBudgetBytes(100),
+		lg.WithBudgetWindow(time.Hour))
+
+	var evaluated int
+	child := log.With("lazy", lg.Lazy(func() any {
+		evaluated++
+		return "value"
+	}))
+	require.Zero(t, evaluated)
+
+	// Disabled DEBUG entries don't count toward the budget.
+	for i := 0; i < 10; i++ {
+		child.Debug("disabled")
+	}
+	require.Zero(t, evaluated)
+	require.Zero(t, log.Dropped())
+
+	child.Warn("warn")
+	require.Equal(t, 1, evaluated) // Encoded, but not sized.
+
+	ents := testlg.DecodeJSON(t, buf)
+	require.Len(t, ents, 1)
+	require.Equal(t, "warn", ents[0]["message"])
+}
@@ -0,0 +1,117 @@
+package lg
+
+import (
+	"context"
+	"sync"
+)
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx that carries log. This is
+// typically used to propagate a request-scoped Log.
+func NewContext(ctx context.Context, log Log) context.Context {
+	return context.WithValue(ctx, ctxKey{}, log)
+}
+
+// FromContext returns the Log carried by ctx, or nil
+// if ctx does not carry a Log. See NewContext.
+func FromContext(ctx context.Context) Log {
+	if ctx == nil {
+		return nil
+	}
+
+	log, _ := ctx.Value(ctxKey{}).(Log)
+	return log
+}
+
+// Ctx returns the Log carried by ctx, or Default if ctx does not
+// carry a Log, with the fields returned by ContextFields. It is
+// never nil.
+func Ctx(ctx context.Context) Log {
+	log := FromContext(ctx)
+	if log == nil {
+		log = Default()
+	}
+
+	return WithContext(ctx, log)
+}
+
+// ContextFieldsFunc returns fields extracted from ctx, such
+// as tenant ID or request ID, or nil if there are none.
+type ContextFieldsFunc func(ctx context.Context) []Field
+
+// ctxFieldsEntry wraps a registered ContextFieldsFunc so that
+// it can be found again by unregister; funcs are not comparable.
+type ctxFieldsEntry struct {
+	fn ContextFieldsFunc
+}
+
+var (
+	ctxFieldsMu      sync.RWMutex
+	ctxFieldsEntries []*ctxFieldsEntry
+)
+
+// RegisterContextFields registers fn to be invoked by ContextFields,
+// so that metadata carried by a context flows into log entries:
+//
+//	lg.RegisterContextFields(func(ctx context.Context) []lg.Field {
+//	  if id, ok := tenant.FromContext(ctx); ok {
+//	    return []lg.Field{{Key: "tenant_id", Val: id}}
+//	  }
+//	  return nil
+//	})
+//
+// The returned func unregisters fn; it is safe to invoke more than once.
+func RegisterContextFields(fn ContextFieldsFunc) (unregister func()) {
+	if fn == nil {
+		return func() {}
+	}
+
+	e := &ctxFieldsEntry{fn: fn}
+	ctxFieldsMu.Lock()
+	defer ctxFieldsMu.Unlock()
+	ctxFieldsEntries = append(ctxFieldsEntries, e)
+
+	return func() {
+		ctxFieldsMu.Lock()
+		defer ctxFieldsMu.Unlock()
+		for i := range ctxFieldsEntries {
+			if ctxFieldsEntries[i] == e {
+				ctxFieldsEntries = append(ctxFieldsEntries[:i], ctxFieldsEntries[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// ContextFields returns the request ID carried by ctx (see
+// ContextWithRequestID), if any, and the fields extracted from ctx
+// by each registered ContextFieldsFunc. See RegisterContextFields.
+func ContextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+
+	// Invoke the funcs without holding the lock, so that
+	// a func may itself register or unregister.
+	ctxFieldsMu.RLock()
+	entries := make([]*ctxFieldsEntry, len(ctxFieldsEntries))
+	copy(entries, ctxFieldsEntries)
+	ctxFieldsMu.RUnlock()
+
+	var fields []Field
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, Field{Key: RequestIDKey, Val: id})
+	}
+	for _, e := range entries {
+		fields = append(fields, e.fn(ctx)...)
+	}
+
+	return fields
+}
+
+// WithContext returns a child of log that has the
+// fields returned by ContextFields.
+func WithContext(ctx context.Context, log Log) Log {
+	return WithFields(log, ContextFields(ctx)...)
+}
@@ -0,0 +1,53 @@
+package lg_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	require.Nil(t, lg.FromContext(ctx))
+	require.Nil(t, lg.FromContext(nil)) //nolint:staticcheck // testing nil ctx
+	require.Equal(t, lg.Default(), lg.Ctx(ctx))
+
+	log := zaplg.NewWith(&bytes.Buffer{}, "text", false, false, true, false, 0)
+	ctx = lg.NewContext(ctx, log)
+	require.Equal(t, log, lg.FromContext(ctx))
+	require.Equal(t, log, lg.Ctx(ctx))
+}
+
+type tenantKey struct{}
+
+func TestRegisterContextFields(t *testing.T) {
+	lg.RegisterContextFields(nil)()
+	unregister := lg.RegisterContextFields(func(ctx context.Context) []lg.Field {
+		if id, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []lg.Field{{Key: "tenant_id", Val: id}}
+		}
+		return nil
+	})
+	t.Cleanup(unregister)
+
+	ctx := context.Background()
+	require.Empty(t, lg.ContextFields(ctx))
+	require.Empty(t, lg.ContextFields(nil)) //nolint:staticcheck // testing nil ctx
+
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+	require.Equal(t, []lg.Field{{Key: "tenant_id", Val: "acme"}}, lg.ContextFields(ctx))
+
+	buf := &bytes.Buffer{}
+	ctx = lg.NewContext(ctx, zaplg.NewWith(buf, "json", false, false, true, false, 0))
+	lg.Ctx(ctx).Debug("hello")
+	require.Contains(t, buf.String(), `"tenant_id":"acme"`)
+
+	unregister()
+	unregister()
+	require.Empty(t, lg.ContextFields(ctx))
+}
@@ -0,0 +1,98 @@
+package lg
+
+import "sync"
+
+// Field is a structured key-value pair, as would
+// be passed to Log.With.
+type Field struct {
+	Key string
+	Val any
+}
+
+// CheckedEntry is a log entry that has been checked via Check,
+// and thus is known to be enabled. Fields can be attached to the
+// entry before it is emitted via Write. The methods of CheckedEntry
+// are no-op on a nil receiver.
+//
+// A CheckedEntry must not be used after Write has been invoked.
+type CheckedEntry struct {
+	log    Log
+	level  Level
+	msg    string
+	fields []Field
+}
+
+var entryPool = sync.Pool{
+	New: func() any {
+		return &CheckedEntry{}
+	},
+}
+
+// Check returns nil if log is not enabled for level (see Enabled);
+// otherwise it returns a CheckedEntry that writes msg to log at
+// level. This combines the Enabled guard and emission in a single
+// call, and avoids constructing fields for disabled levels:
+//
+//	if ce := lg.Check(log, lg.LevelDebug, "cache miss"); ce != nil {
+//	  ce.With("stats", cache.Stats()).Write()
+//	}
+//
+// If level is disabled, Check does not allocate. By contrast, a call
+// such as log.Debugf("n=%d", n) via the Log interface allocates the
+// variadic args, even if DEBUG level is disabled: use Check (or
+// NewEvent) on hot paths.
+func Check(log Log, level Level, msg string) *CheckedEntry {
+	if !Enabled(log, level) {
+		return nil
+	}
+
+	ce, _ := entryPool.Get().(*CheckedEntry)
+	ce.log = log
+	ce.level = level
+	ce.msg = msg
+	return ce
+}
+
+// With adds a field to the entry.
+func (ce *CheckedEntry) With(key string, val any) *CheckedEntry {
+	if ce == nil {
+		return nil
+	}
+
+	ce.fields = append(ce.fields, Field{Key: key, Val: val})
+	return ce
+}
+
+// Write emits the entry. The entry must not be
+// used after Write is invoked.
+func (ce *CheckedEntry) Write() {
+	if ce == nil {
+		return
+	}
+
+	ce.write(2)
+}
+
+// write emits the entry and releases it to the pool. Arg skip
+// is the caller skip applied to ce.log, relative to write.
+func (ce *CheckedEntry) write(skip int) {
+	log := WithFields(AddCallerSkip(ce.log, skip), ce.fields...)
+
+	switch ce.level {
+	case LevelWarn:
+		log.Warn(ce.msg)
+	case LevelError:
+		log.Error(ce.msg)
+	default:
+		log.Debug(ce.msg)
+	}
+
+	// Release the entry back to the pool.
+	for i := range ce.fields {
+		ce.fields[i] = Field{}
+	}
+	ce.fields = ce.fields[:0]
+	ce.log = nil
+	ce.msg = ""
+	entryPool.Put(ce)
+}
@@ -0,0 +1,81 @@
+package lg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestCheck(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0, zaplg.WithLevel(lg.LevelWarn))
+
+	ce := lg.Check(log, lg.LevelDebug, "not enabled")
+	require.Nil(t, ce)
+	ce.With("k", "v").Write() // nil receiver is no-op
+	require.Zero(t, buf.Len())
+
+	ce = lg.Check(log, lg.LevelWarn, "enabled")
+	require.NotNil(t, ce)
+	ce.With("k", "v").With("attempts", 3).Write()
+
+	m := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "warn", m["level"])
+	require.Equal(t, "enabled", m["message"])
+	require.Equal(t, "v", m["k"])
+	require.Equal(t, float64(3), m["attempts"])
+	require.Contains(t, m["caller"], "entry_test.go")
+
+	// Verify that pooled entries don't retain fields.
+	buf.Reset()
+	lg.Check(log, lg.LevelError, "again").Write()
+	m = map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "error", m["level"])
+	require.NotContains(t, m, "k")
+
+	require.Nil(t, lg.Check(lg.Discard(), lg.LevelError, "discard"))
+	require.Nil(t, lg.Check(nil, lg.LevelError, "nil"))
+}
+
+func TestCheck_PII(t *testing.T) {
+	defer lg.SetPIIPolicy(lg.PIIHash)
+	lg.SetPIIPolicy(lg.PIIDrop)
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, false, false, 0)
+	lg.Check(log, lg.LevelDebug, "signup").With("email", lg.PII("email", "alice@example.com").Val).Write()
+
+	m := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "signup", m["message"])
+	require.NotContains(t, m, "email")
+}
+
+func TestCheck_Disabled_NoAllocs(t *testing.T) {
+	log := zaplg.NewWith(io.Discard, "json", true, false, true, true, 0, zaplg.WithLevel(lg.LevelError))
+
+	require.Zero(t, testing.AllocsPerRun(100, func() {
+		lg.Check(log, lg.LevelDebug, "msg").With("k", "v").Write()
+		lg.NewEvent(log, lg.LevelWarn).Str("k", "v").Int("n", 1).Msg("msg")
+		_ = lg.Enabled(log, lg.LevelDebug)
+	}))
+}
+
+func BenchmarkCheck_Disabled(b *testing.B) {
+	log := zaplg.NewWith(io.Discard, "json", true, false, true, true, 0, zaplg.WithLevel(lg.LevelError))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if ce := lg.Check(log, lg.LevelDebug, "msg"); ce != nil {
+			ce.With("i", i).Write()
+		}
+	}
+}
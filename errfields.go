@@ -0,0 +1,84 @@
+package lg
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrorFieldsFunc returns fields extracted from err, or nil
+// if err doesn't have any relevant information.
+type ErrorFieldsFunc func(err error) []Field
+
+var (
+	errFieldsMu    sync.RWMutex
+	errFieldsFuncs = []ErrorFieldsFunc{errorCodeFields, errorTemporaryFields}
+)
+
+// RegisterErrorFields registers fn to be invoked by ErrorFields.
+// By default, ErrorFields extracts field "error.code" from errors
+// that implement interface{ Code() string }, and "error.temporary"
+// from errors that implement interface{ Temporary() bool }.
+func RegisterErrorFields(fn ErrorFieldsFunc) {
+	if fn == nil {
+		return
+	}
+
+	errFieldsMu.Lock()
+	defer errFieldsMu.Unlock()
+	errFieldsFuncs = append(errFieldsFuncs, fn)
+}
+
+// ErrorFields returns the fields extracted from err by each
+// registered ErrorFieldsFunc. See RegisterErrorFields. Log impls
+// should add these fields to entries logged by the WarnIf methods.
+func ErrorFields(err error) []Field {
+	if err == nil {
+		return nil
+	}
+
+	errFieldsMu.RLock()
+	defer errFieldsMu.RUnlock()
+
+	var fields []Field
+	for _, fn := range errFieldsFuncs {
+		fields = append(fields, fn(err)...)
+	}
+
+	return fields
+}
+
+// WithError returns a child of log that has err as field "error",
+// along with the fields returned by ErrorFields. If err is
+// nil, log is returned unchanged.
+func WithError(log Log, err error) Log {
+	if err == nil {
+		return log
+	}
+
+	return WithFields(log, errorFields(err)...)
+}
+
+// errorFields returns field "error" for err, followed by the
+// fields returned by ErrorFields. It is used by WithError and
+// Event.Err. Arg err must not be nil.
+func errorFields(err error) []Field {
+	return append([]Field{{Key: "error", Val: err.Error()}}, ErrorFields(err)...)
+}
+
+func errorCodeFields(err error) []Field {
+	var coder interface{ Code() string }
+	if errors.As(err, &coder) {
+		return []Field{{Key: "error.code", Val: coder.Code()}}
+	}
+
+	return nil
+}
+
+func errorTemporaryFields(err error) []Field {
+	var temp interface{ Temporary() bool }
+	if errors.As(err, &temp) {
+		return []Field{{Key: "error.temporary", Val: temp.Temporary()}}
+	}
+
+	return nil
+}
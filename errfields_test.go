@@ -0,0 +1,82 @@
+package lg_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+type codeErr struct {
+	code string
+	temp bool
+}
+
+func (e codeErr) Error() string   { return "code error" }
+func (e codeErr) Code() string    { return e.code }
+func (e codeErr) Temporary() bool { return e.temp }
+
+func TestErrorFields(t *testing.T) {
+	require.Nil(t, lg.ErrorFields(nil))
+	require.Nil(t, lg.ErrorFields(errors.New("plain")))
+
+	err := fmt.Errorf("wrapped: %w", codeErr{code: "E42", temp: true})
+	fields := lg.ErrorFields(err)
+	require.Equal(t, []lg.Field{
+		{Key: "error.code", Val: "E42"},
+		{Key: "error.temporary", Val: true},
+	}, fields)
+}
+
+func TestWarnIfError_ErrorFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+
+	err := codeErr{code: "E42"}
+	log.WarnIfError(err)
+	log.WarnIfFuncError(func() error { return err })
+	lg.WithError(log, err).Error("failed")
+	require.Equal(t, log, lg.WithError(log, nil))
+
+	sc := bufio.NewScanner(buf)
+	var count int
+	for sc.Scan() {
+		count++
+		m := map[string]any{}
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &m))
+		require.Equal(t, "E42", m["error.code"])
+		require.Equal(t, false, m["error.temporary"])
+		require.Contains(t, m["caller"], "errfields_test.go")
+	}
+	require.NoError(t, sc.Err())
+	require.Equal(t, 3, count)
+
+	tlog := testlg.NewWith(t, func(w io.Writer) lg.Log {
+		return zaplg.NewWith(io.MultiWriter(w, buf), "json", false, false, true, false, 0)
+	})
+	buf.Reset()
+	tlog.WarnIfError(err)
+	require.Contains(t, buf.String(), `"error.code":"E42"`)
+}
+
+func TestRegisterErrorFields(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	lg.RegisterErrorFields(func(err error) []lg.Field {
+		if errors.Is(err, sentinel) {
+			return []lg.Field{{Key: "error.sentinel", Val: true}}
+		}
+		return nil
+	})
+
+	fields := lg.ErrorFields(fmt.Errorf("wrap: %w", sentinel))
+	require.Equal(t, []lg.Field{{Key: "error.sentinel", Val: true}}, fields)
+}
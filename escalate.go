@@ -0,0 +1,222 @@
+package lg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEscalationMax is the default maximum number of
+// DEBUG entries buffered by an Escalating log.
+const DefaultEscalationMax = 1000
+
+// Escalating is a Log that gives "debug-on-failure" semantics:
+// DEBUG entries are buffered, and are only written to the
+// underlying Log if an ERROR entry is logged before End is invoked.
+// Otherwise, End discards them. WARN and ERROR entries are written
+// immediately. Once escalated, DEBUG entries are written immediately.
+//
+// Because buffered entries are written after the fact, they have
+// fields "buffered.time" and "buffered.caller", which hold the
+// time and caller of the original DEBUG call.
+//
+// The child logs returned by With share the buffer of their parent,
+// so that entries logged anywhere for a request are escalated
+// together. Escalating is safe for concurrent use.
+type Escalating struct {
+	log        Log
+	callerSkip int
+	st         *escalation
+}
+
+// escalation is the state shared by an Escalating and its children.
+type escalation struct {
+	mu        sync.Mutex
+	entries   []bufferedEntry
+	max       int
+	dropped   int
+	escalated bool
+	ended     bool
+}
+
+type bufferedEntry struct {
+	log    Log
+	msg    string
+	time   time.Time
+	caller string
+}
+
+// NewEscalating returns an Escalating that writes to log, buffering
+// at most max DEBUG entries; entries beyond max are dropped. If max
+// is not positive, DefaultEscalationMax is used.
+func NewEscalating(log Log, max int) *Escalating {
+	if max <= 0 {
+		max = DefaultEscalationMax
+	}
+
+	// The caller skip accounts for the frame of Escalating's methods.
+	return &Escalating{log: AddCallerSkip(OrDiscard(log), 1), st: &escalation{max: max}}
+}
+
+// EscalationContext returns a copy of ctx that carries an Escalating
+// that writes to log (see NewContext), and a func that ends it:
+//
+//	ctx, end := lg.EscalationContext(r.Context(), log)
+//	defer end()
+//	lg.Ctx(ctx).Debug("cache miss") // Only written if an error is logged.
+func EscalationContext(ctx context.Context, log Log) (context.Context, func()) {
+	e := NewEscalating(log, 0)
+	return NewContext(ctx, e), e.End
+}
+
+// End discards any buffered entries. After End, DEBUG entries
+// are discarded, unless the log has already escalated.
+func (e *Escalating) End() {
+	e.st.mu.Lock()
+	defer e.st.mu.Unlock()
+
+	e.st.ended = true
+	e.st.entries = nil
+}
+
+// Enabled reports whether level is enabled for the underlying log.
+func (e *Escalating) Enabled(level Level) bool {
+	return Enabled(e.log, level)
+}
+
+// AddCallerSkip implements the optional interface used by AddCallerSkip.
+func (e *Escalating) AddCallerSkip(skip int) Log {
+	return &Escalating{log: AddCallerSkip(e.log, skip), callerSkip: e.callerSkip + skip, st: e.st}
+}
+
+// Debug implements Log.Debug.
+func (e *Escalating) Debug(a ...any) {
+	if e.buffer(a, "", false) {
+		return
+	}
+
+	e.log.Debug(a...)
+}
+
+// Debugf implements Log.Debugf.
+func (e *Escalating) Debugf(format string, a ...any) {
+	if e.buffer(a, format, true) {
+		return
+	}
+
+	e.log.Debugf(format, a...)
+}
+
+// buffer buffers the DEBUG entry, returning false if the entry
+// should instead be written to the underlying log. It must be
+// invoked directly by Debug or Debugf, for the caller to be correct.
+func (e *Escalating) buffer(a []any, format string, isFormat bool) bool {
+	e.st.mu.Lock()
+	defer e.st.mu.Unlock()
+
+	switch {
+	case e.st.escalated:
+		return false
+	case e.st.ended, !Enabled(e.log, LevelDebug):
+		return true
+	case len(e.st.entries) >= e.st.max:
+		e.st.dropped++
+		return true
+	}
+
+	ent := bufferedEntry{log: e.log, time: time.Now()}
+	if isFormat {
+		ent.msg = fmt.Sprintf(format, a...)
+	} else {
+		ent.msg = fmt.Sprint(a...)
+	}
+
+	if _, file, line, ok := runtime.Caller(2 + e.callerSkip); ok {
+		ent.caller = shortCaller(file, line)
+	}
+
+	e.st.entries = append(e.st.entries, ent)
+	return true
+}
+
+// escalate writes the buffered entries to the underlying log.
+func (e *Escalating) escalate() {
+	e.st.mu.Lock()
+	defer e.st.mu.Unlock()
+
+	if e.st.escalated || e.st.ended {
+		return
+	}
+
+	e.st.escalated = true
+	for _, ent := range e.st.entries {
+		ent.log.With("buffered.time", ent.time).With("buffered.caller", ent.caller).Debug(ent.msg)
+	}
+
+	if e.st.dropped > 0 {
+		e.log.Debugf("lg: %d buffered debug entries dropped", e.st.dropped)
+	}
+
+	e.st.entries = nil
+}
+
+// Warn implements Log.Warn.
+func (e *Escalating) Warn(a ...any) {
+	e.log.Warn(a...)
+}
+
+// Warnf implements Log.Warnf.
+func (e *Escalating) Warnf(format string, a ...any) {
+	e.log.Warnf(format, a...)
+}
+
+// WarnIfError implements Log.WarnIfError.
+func (e *Escalating) WarnIfError(err error) {
+	e.log.WarnIfError(err)
+}
+
+// WarnIfFuncError implements Log.WarnIfFuncError.
+func (e *Escalating) WarnIfFuncError(fn func() error) {
+	e.log.WarnIfFuncError(fn)
+}
+
+// WarnIfCloseError implements Log.WarnIfCloseError.
+func (e *Escalating) WarnIfCloseError(c io.Closer) {
+	e.log.WarnIfCloseError(c)
+}
+
+// Error implements Log.Error. The buffered
+// entries are written before the ERROR entry.
+func (e *Escalating) Error(a ...any) {
+	e.escalate()
+	e.log.Error(a...)
+}
+
+// Errorf implements Log.Errorf. The buffered
+// entries are written before the ERROR entry.
+func (e *Escalating) Errorf(format string, a ...any) {
+	e.escalate()
+	e.log.Errorf(format, a...)
+}
+
+// With implements Log.With. The returned
+// log shares the buffer of e.
+func (e *Escalating) With(key string, val any) Log {
+	return &Escalating{log: e.log.With(key, val), callerSkip: e.callerSkip, st: e.st}
+}
+
+// shortCaller returns the "dir/file.go:line" form of a caller.
+func shortCaller(file string, line int) string {
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+
+	return file + ":" + strconv.Itoa(line)
+}
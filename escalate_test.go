@@ -0,0 +1,82 @@
+package lg_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestEscalationContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := testlg.NewJSON(buf)
+
+	// No error: the debug entries are discarded.
+	ctx, end := lg.EscalationContext(context.Background(), log)
+	lg.Ctx(ctx).Debug("cache miss")
+	lg.Ctx(ctx).Warn("slow")
+	end()
+	lg.Ctx(ctx).Debug("after end")
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "slow", ms[0]["message"])
+
+	// Error: the debug entries are written first.
+	ctx, end = lg.EscalationContext(context.Background(), log)
+	defer end()
+	lg.Ctx(ctx).With("user", "alice").Debugf("cache miss: %d", 42)
+	lg.Ctx(ctx).Debug("querying db")
+	require.Zero(t, buf.Len())
+
+	lg.Ctx(ctx).Error("db failed")
+	lg.Ctx(ctx).Debug("after error")
+
+	ms = testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 4)
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "cache miss: 42", ms[0]["message"])
+	require.Equal(t, "alice", ms[0]["user"])
+	require.Contains(t, ms[0]["buffered.caller"], "escalate_test.go:")
+	require.Contains(t, ms[0], "buffered.time")
+	require.Equal(t, "querying db", ms[1]["message"])
+	require.Equal(t, "error", ms[2]["level"])
+	require.Contains(t, ms[2]["caller"], "escalate_test.go")
+	require.Equal(t, "after error", ms[3]["message"])
+	require.NotContains(t, ms[3], "buffered.time")
+}
+
+func TestEscalating_Max(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := lg.NewEscalating(testlg.NewJSON(buf), 2)
+
+	for i := 0; i < 5; i++ {
+		e.Debugf("msg %d", i)
+	}
+	e.Errorf("failed")
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 4)
+	require.Equal(t, "msg 0", ms[0]["message"])
+	require.Equal(t, "msg 1", ms[1]["message"])
+	require.Equal(t, "lg: 3 buffered debug entries dropped", ms[2]["message"])
+	require.Equal(t, "failed", ms[3]["message"])
+}
+
+func TestEscalating_DebugDisabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := testlg.NewJSON(buf, zaplg.WithLevel(lg.LevelWarn))
+	e := lg.NewEscalating(log, 0)
+
+	require.False(t, lg.Enabled(e, lg.LevelDebug))
+	e.Debug("not buffered")
+	e.Error("failed")
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+}
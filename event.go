@@ -0,0 +1,103 @@
+package lg
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event is a builder for a log entry, for those who prefer
+// a fluent style:
+//
+//	lg.NewEvent(log, lg.LevelWarn).
+//	  Str("user", id).
+//	  Int("attempts", n).
+//	  Err(err).
+//	  Msg("login failed")
+//
+// NewEvent returns nil if the level is not enabled. The
+// methods of Event are no-op on a nil receiver. An Event
+// must not be used after Msg or Msgf has been invoked.
+type Event CheckedEntry
+
+// NewEvent returns a new Event for log at level, or
+// nil if log is not enabled for level.
+func NewEvent(log Log, level Level) *Event {
+	return (*Event)(Check(log, level, ""))
+}
+
+// Str adds a string field.
+func (e *Event) Str(key, val string) *Event {
+	return e.Any(key, val)
+}
+
+// Int adds an int field.
+func (e *Event) Int(key string, val int) *Event {
+	return e.Any(key, val)
+}
+
+// Int64 adds an int64 field.
+func (e *Event) Int64(key string, val int64) *Event {
+	return e.Any(key, val)
+}
+
+// Float64 adds a float64 field.
+func (e *Event) Float64(key string, val float64) *Event {
+	return e.Any(key, val)
+}
+
+// Bool adds a bool field.
+func (e *Event) Bool(key string, val bool) *Event {
+	return e.Any(key, val)
+}
+
+// Dur adds a time.Duration field.
+func (e *Event) Dur(key string, val time.Duration) *Event {
+	return e.Any(key, val)
+}
+
+// Time adds a time.Time field.
+func (e *Event) Time(key string, val time.Time) *Event {
+	return e.Any(key, val)
+}
+
+// Err adds err as field "error", along with the fields returned by
+// ErrorFields, as does WithError. If err is nil, no field is added.
+func (e *Event) Err(err error) *Event {
+	if e == nil || err == nil {
+		return e
+	}
+
+	e.fields = append(e.fields, errorFields(err)...)
+	return e
+}
+
+// Any adds a field of arbitrary type.
+func (e *Event) Any(key string, val any) *Event {
+	if e == nil {
+		return nil
+	}
+
+	e.fields = append(e.fields, Field{Key: key, Val: val})
+	return e
+}
+
+// Msg emits the event with msg.
+func (e *Event) Msg(msg string) {
+	if e == nil {
+		return
+	}
+
+	e.msg = msg
+	(*CheckedEntry)(e).write(2)
+}
+
+// Msgf emits the event with a message formatted
+// per fmt.Sprintf.
+func (e *Event) Msgf(format string, a ...any) {
+	if e == nil {
+		return
+	}
+
+	e.msg = fmt.Sprintf(format, a...)
+	(*CheckedEntry)(e).write(2)
+}
@@ -0,0 +1,66 @@
+package lg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestEvent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0, zaplg.WithLevel(lg.LevelWarn))
+
+	ev := lg.NewEvent(log, lg.LevelDebug)
+	require.Nil(t, ev)
+	ev.Str("user", "alice").Int("attempts", 3).Msg("not enabled")
+	require.Zero(t, buf.Len())
+
+	lg.NewEvent(log, lg.LevelWarn).
+		Str("user", "alice").
+		Int("attempts", 3).
+		Int64("id", 7).
+		Float64("ratio", 0.5).
+		Bool("admin", false).
+		Dur("elapsed", time.Second).
+		Err(errors.New("bad password")).
+		Err(nil).
+		Any("tags", []string{"a"}).
+		Msgf("login %s", "failed")
+
+	m := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "warn", m["level"])
+	require.Equal(t, "login failed", m["message"])
+	require.Equal(t, "alice", m["user"])
+	require.Equal(t, float64(3), m["attempts"])
+	require.Equal(t, float64(7), m["id"])
+	require.Equal(t, 0.5, m["ratio"])
+	require.Equal(t, false, m["admin"])
+	require.Equal(t, "1s", m["elapsed"])
+	require.Equal(t, "bad password", m["error"])
+	require.Equal(t, []any{"a"}, m["tags"])
+	require.Contains(t, m["caller"], "event_test.go")
+
+	buf.Reset()
+	lg.NewEvent(log, lg.LevelError).Msg("msg")
+	m = map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "msg", m["message"])
+	require.Contains(t, m["caller"], "event_test.go")
+	require.NotContains(t, m, "user")
+
+	buf.Reset()
+	lg.NewEvent(log, lg.LevelWarn).Err(codeErr{code: "E42"}).Msg("failed")
+	m = map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "code error", m["error"])
+	require.Equal(t, "E42", m["error.code"])
+	require.Equal(t, false, m["error.temporary"])
+}
@@ -0,0 +1,125 @@
+package lg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+)
+
+// exitHooks holds the flush funcs registered via FlushOnExit.
+var exitHooks struct {
+	mu    sync.Mutex
+	next  int
+	funcs map[int]func()
+}
+
+// FlushOnExit arranges for log, and then closers (in reverse order),
+// to be flushed before the process exits, so that the final entries,
+// which are usually the most important, are not lost in buffered or
+// async sinks such as those of package lgsink. Log is flushed via its
+// Sync method, if it has one (e.g. zaplg.Log).
+//
+// The flush happens when the process receives SIGINT or SIGTERM, after
+// which the process exits with status 128 plus the signal number; or
+// when Exit or FlushAll is invoked. Go has no atexit mechanism, so
+// main should invoke Exit instead of os.Exit, or defer FlushAll:
+//
+//	func main() {
+//	  sink := lgsink.NewAsync(f)
+//	  log := zaplg.NewWith(sink, "json", true, true, true, true, 0)
+//	  lg.FlushOnExit(log, sink)
+//	  defer lg.FlushAll()
+//	  if err := run(log); err != nil {
+//	    log.Error(err)
+//	    lg.Exit(1)
+//	  }
+//	}
+//
+// The returned func unregisters the flush, and stops the handling
+// of signals. Errors from Sync or Close are printed to os.Stderr.
+func FlushOnExit(log Log, closers ...io.Closer) (stop func()) {
+	var once sync.Once
+	flush := func() {
+		once.Do(func() {
+			if s, ok := log.(interface{ Sync() error }); ok {
+				if err := s.Sync(); err != nil {
+					fmt.Fprintf(os.Stderr, "lg: flush on exit: sync log: %v\n", err)
+				}
+			}
+
+			for i := len(closers) - 1; i >= 0; i-- {
+				if err := closers[i].Close(); err != nil {
+					fmt.Fprintf(os.Stderr, "lg: flush on exit: close: %v\n", err)
+				}
+			}
+		})
+	}
+
+	exitHooks.mu.Lock()
+	if exitHooks.funcs == nil {
+		exitHooks.funcs = map[int]func(){}
+	}
+	id := exitHooks.next
+	exitHooks.next++
+	exitHooks.funcs[id] = flush
+	exitHooks.mu.Unlock()
+
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-done:
+		case sig := <-sigs:
+			FlushAll()
+			code := 1
+			if s, ok := sig.(syscall.Signal); ok {
+				code = 128 + int(s)
+			}
+			os.Exit(code)
+		}
+	}()
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+
+			exitHooks.mu.Lock()
+			delete(exitHooks.funcs, id)
+			exitHooks.mu.Unlock()
+		})
+	}
+}
+
+// FlushAll performs the flushes registered via FlushOnExit, most
+// recently registered first. Each flush is performed at most once.
+func FlushAll() {
+	exitHooks.mu.Lock()
+	ids := make([]int, 0, len(exitHooks.funcs))
+	for id := range exitHooks.funcs {
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+
+	funcs := make([]func(), len(ids))
+	for i, id := range ids {
+		funcs[i] = exitHooks.funcs[id]
+	}
+	exitHooks.mu.Unlock()
+
+	for _, fn := range funcs {
+		fn()
+	}
+}
+
+// Exit invokes FlushAll, and then os.Exit with code.
+func Exit(code int) {
+	FlushAll()
+	os.Exit(code)
+}
@@ -0,0 +1,93 @@
+package lg_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgsink"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestFlushOnExit_FlushAll(t *testing.T) {
+	var order []string
+	buf := &bytes.Buffer{}
+	sink := lgsink.NewBuffered(buf, lgsink.WithFlushInterval(0))
+	log := zaplg.NewWith(sink, "json", false, false, true, false, 0)
+
+	stop1 := lg.FlushOnExit(log, closerFunc(func() error {
+		order = append(order, "first")
+		return nil
+	}))
+	defer stop1()
+
+	stop2 := lg.FlushOnExit(lg.Discard(), closerFunc(func() error {
+		order = append(order, "second")
+		return nil
+	}))
+	stop2()
+
+	stop3 := lg.FlushOnExit(lg.Discard(), closerFunc(func() error {
+		order = append(order, "third")
+		return errors.New("close failed")
+	}))
+	defer stop3()
+
+	log.Debug("final entry")
+	require.Empty(t, buf.String())
+
+	lg.FlushAll()
+	require.Contains(t, buf.String(), "final entry")
+	require.Equal(t, []string{"third", "first"}, order)
+
+	// Each flush is performed at most once.
+	lg.FlushAll()
+	require.Equal(t, []string{"third", "first"}, order)
+}
+
+// envFlushChild is set when the test binary is run as the
+// child process of TestFlushOnExit_Signal.
+const envFlushChild = "LG_TEST_FLUSH_CHILD"
+
+func TestFlushOnExit_Signal(t *testing.T) {
+	if path := os.Getenv(envFlushChild); path != "" {
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		sink := lgsink.NewAsync(lgsink.NewBuffered(f, lgsink.WithFlushInterval(0)))
+		log := zaplg.NewWith(sink, "json", false, false, true, false, 0)
+		lg.FlushOnExit(log, sink)
+
+		log.Debug("final entry")
+		p, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		require.NoError(t, p.Signal(syscall.SIGTERM))
+		time.Sleep(10 * time.Second)
+		t.Fatal("process should have exited")
+	}
+
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFlushOnExit_Signal$")
+	cmd.Env = append(os.Environ(), envFlushChild+"="+path)
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr), "got %v", err)
+	require.Equal(t, 128+int(syscall.SIGTERM), exitErr.ExitCode())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "final entry")
+}
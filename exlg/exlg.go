@@ -0,0 +1,60 @@
+// Package exlg implements a lg.Log for Example functions. Its output
+// is deterministic (no timestamp or caller), and is written to
+// os.Stdout, so that it can be verified by an "// Output:" comment:
+//
+//	func ExampleServer() {
+//	  log := exlg.New()
+//	  srv := NewServer(log.With("port", 8080))
+//	  srv.Start()
+//
+//	  // Output:
+//	  // DEBUG	server started	port=8080
+//	}
+//
+// Each entry is written as the level, the message, and then each
+// field as key=value, separated by tabs. All levels are logged.
+package exlg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+// New returns a Log that writes to os.Stdout.
+func New() lg.Log {
+	return NewWith(stdout{})
+}
+
+// NewWith returns a Log that writes to w.
+func NewWith(w io.Writer) lg.Log {
+	return lgcore.New(w, lgcore.EncoderFunc(encode), lgcore.WithTime(false), lgcore.WithCaller(false))
+}
+
+// stdout writes to the current value of os.Stdout, which
+// is replaced by the testing framework when running examples.
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+// encode writes ent as level, message and fields, separated by tabs.
+func encode(buf *bytes.Buffer, ent lgcore.Entry) error {
+	buf.WriteString(strings.ToUpper(ent.Level.String()))
+	buf.WriteByte('\t')
+	buf.WriteString(ent.Msg)
+	for _, f := range ent.Fields {
+		buf.WriteByte('\t')
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		fmt.Fprint(buf, f.Val)
+	}
+	buf.WriteByte('\n')
+	return nil
+}
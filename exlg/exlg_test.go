@@ -0,0 +1,34 @@
+package exlg_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/exlg"
+)
+
+func TestNewWith(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := exlg.NewWith(buf).With("k", "v")
+	log.Debugf("hello %s", "world")
+	log.With("n", 1).Warn("warning")
+	log.Error("failed")
+
+	want := "DEBUG\thello world\tk=v\n" +
+		"WARN\twarning\tk=v\tn=1\n" +
+		"ERROR\tfailed\tk=v\n"
+	require.Equal(t, want, buf.String())
+}
+
+func ExampleNew() {
+	log := exlg.New().With("port", 8080)
+	log.Debug("server started")
+	log.WarnIfError(errors.New("connection reset"))
+
+	// Output:
+	// DEBUG	server started	port=8080
+	// WARN	connection reset	port=8080
+}
@@ -0,0 +1,111 @@
+package lg
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// global holds the default Log used by the package-level
+// logging functions.
+type global struct {
+	// log is the Log as passed to SetDefault.
+	log Log
+
+	// skipped is log with additional caller skip, to account
+	// for the package-level function's frame.
+	skipped Log
+}
+
+var defaultLog atomic.Pointer[global]
+
+// loadGlobal returns the current global, which is
+// Discard if SetDefault has not been invoked.
+func loadGlobal() *global {
+	if g := defaultLog.Load(); g != nil {
+		return g
+	}
+
+	return &global{log: Discard(), skipped: Discard()}
+}
+
+// SetDefault sets the Log used by the package-level logging
+// functions such as lg.Debugf. If log is nil, Discard is used.
+// SetDefault is safe for concurrent use.
+func SetDefault(log Log) {
+	if log == nil {
+		log = Discard()
+	}
+
+	defaultLog.Store(&global{log: log, skipped: AddCallerSkip(log, 1)})
+}
+
+// Default returns the Log used by the package-level logging
+// functions. Unless SetDefault has been invoked, Default
+// returns Discard.
+func Default() Log {
+	return loadGlobal().log
+}
+
+// Debug logs at DEBUG level to the Default log.
+func Debug(a ...any) {
+	loadGlobal().skipped.Debug(a...)
+}
+
+// Debugf logs at DEBUG level to the Default log.
+func Debugf(format string, a ...any) {
+	loadGlobal().skipped.Debugf(format, a...)
+}
+
+// Warn logs at WARN level to the Default log.
+func Warn(a ...any) {
+	loadGlobal().skipped.Warn(a...)
+}
+
+// Warnf logs at WARN level to the Default log.
+func Warnf(format string, a ...any) {
+	loadGlobal().skipped.Warnf(format, a...)
+}
+
+// WarnIfError invokes WarnIfError on the Default log.
+func WarnIfError(err error) {
+	loadGlobal().skipped.WarnIfError(err)
+}
+
+// WarnIfFuncError invokes WarnIfFuncError on the Default log.
+func WarnIfFuncError(fn func() error) {
+	loadGlobal().skipped.WarnIfFuncError(fn)
+}
+
+// WarnIfCloseError invokes WarnIfCloseError on the Default log.
+func WarnIfCloseError(c io.Closer) {
+	loadGlobal().skipped.WarnIfCloseError(c)
+}
+
+// Error logs at ERROR level to the Default log.
+func Error(a ...any) {
+	loadGlobal().skipped.Error(a...)
+}
+
+// Errorf logs at ERROR level to the Default log.
+func Errorf(format string, a ...any) {
+	loadGlobal().skipped.Errorf(format, a...)
+}
+
+// With returns a child of the Default log that has a
+// structured field key with val.
+func With(key string, val any) Log {
+	return loadGlobal().log.With(key, val)
+}
+
+// ReplaceGlobals replaces the Default log with log, and returns
+// a function that restores the previous Default. This is useful
+// in tests:
+//
+//	defer lg.ReplaceGlobals(testlg.New(t))()
+func ReplaceGlobals(log Log) (restore func()) {
+	prev := defaultLog.Load()
+	SetDefault(log)
+	return func() {
+		defaultLog.Store(prev)
+	}
+}
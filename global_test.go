@@ -0,0 +1,70 @@
+package lg_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestDefault(t *testing.T) {
+	require.Equal(t, lg.Discard(), lg.Default())
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "text", false, true, true, true, 0)
+	lg.SetDefault(log)
+	t.Cleanup(func() { lg.SetDefault(nil) })
+	require.Equal(t, log, lg.Default())
+
+	lg.Debug("Debug msg")
+	lg.Debugf("Debugf msg")
+	lg.Warn("Warn msg")
+	lg.Warnf("Warnf msg")
+	lg.Error("Error msg")
+	lg.Errorf("Errorf msg")
+	lg.WarnIfError(nil)
+	lg.WarnIfError(errors.New("error: WarnIfError msg"))
+	lg.WarnIfFuncError(func() error { return errors.New("error: WarnIfFuncError msg") })
+	lg.WarnIfCloseError(errCloser{})
+	lg.With("k", "v").Debug("With msg")
+
+	sc := bufio.NewScanner(buf)
+	var gotLines []string
+	for sc.Scan() {
+		gotLines = append(gotLines, sc.Text())
+	}
+	require.NoError(t, sc.Err())
+	require.Len(t, gotLines, 10)
+
+	for _, line := range gotLines {
+		require.Contains(t, line, "global_test.go", "caller should be the test file")
+	}
+
+	lg.SetDefault(nil)
+	require.Equal(t, lg.Discard(), lg.Default())
+}
+
+func TestReplaceGlobals(t *testing.T) {
+	require.Equal(t, lg.Discard(), lg.Default())
+
+	log1 := zaplg.NewWith(&bytes.Buffer{}, "text", false, true, true, true, 0)
+	restore1 := lg.ReplaceGlobals(log1)
+	require.Equal(t, log1, lg.Default())
+
+	buf := &bytes.Buffer{}
+	log2 := zaplg.NewWith(buf, "text", false, true, true, true, 0)
+	restore2 := lg.ReplaceGlobals(log2)
+	require.Equal(t, log2, lg.Default())
+	lg.Debug("hello")
+	require.Contains(t, buf.String(), "global_test.go")
+
+	restore2()
+	require.Equal(t, log1, lg.Default())
+	restore1()
+	require.Equal(t, lg.Discard(), lg.Default())
+}
@@ -0,0 +1,39 @@
+package lg
+
+import (
+	"net/http"
+	"time"
+)
+
+// HTTPRequestFields returns a consistent set of fields describing
+// r: "http.method", "http.path", "http.remote_addr" and, if
+// present, "http.user_agent". If r is nil, nil is returned.
+func HTTPRequestFields(r *http.Request) []Field {
+	if r == nil {
+		return nil
+	}
+
+	fields := make([]Field, 0, 4)
+	fields = append(fields,
+		Field{Key: "http.method", Val: r.Method},
+		Field{Key: "http.path", Val: r.URL.Path},
+		Field{Key: "http.remote_addr", Val: r.RemoteAddr},
+	)
+
+	if ua := r.UserAgent(); ua != "" {
+		fields = append(fields, Field{Key: "http.user_agent", Val: ua})
+	}
+
+	return fields
+}
+
+// HTTPResponseFields returns a consistent set of fields describing
+// an HTTP response: "http.status", "http.size" (the number of bytes
+// written), and "http.latency".
+func HTTPResponseFields(status, size int, latency time.Duration) []Field {
+	return []Field{
+		{Key: "http.status", Val: status},
+		{Key: "http.size", Val: size},
+		{Key: "http.latency", Val: latency},
+	}
+}
@@ -0,0 +1,42 @@
+package lg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestHTTPFields(t *testing.T) {
+	require.Nil(t, lg.HTTPRequestFields(nil))
+
+	r := httptest.NewRequest(http.MethodGet, "/users/1?verbose=true", nil)
+	r.Header.Set("User-Agent", "test-agent")
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log2 := lg.WithFields(log, lg.HTTPRequestFields(r)...)
+	log2 = lg.WithFields(log2, lg.HTTPResponseFields(200, 512, 1500*time.Millisecond)...)
+	log2.Debug("request")
+
+	m := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "GET", m["http.method"])
+	require.Equal(t, "/users/1", m["http.path"])
+	require.Equal(t, "192.0.2.1:1234", m["http.remote_addr"])
+	require.Equal(t, "test-agent", m["http.user_agent"])
+	require.Equal(t, float64(200), m["http.status"])
+	require.Equal(t, float64(512), m["http.size"])
+	require.Equal(t, "1.5s", m["http.latency"])
+
+	r.Header.Del("User-Agent")
+	fields := lg.HTTPRequestFields(r)
+	require.Len(t, fields, 3)
+}
@@ -0,0 +1,134 @@
+package lg
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteSize is a number of bytes that is rendered in humanized form,
+// e.g. "1.2 MiB". Use Bytes to create a ByteSize field.
+type ByteSize int64
+
+// Bytes returns a Field whose value, n bytes, is rendered in
+// humanized form, for use with WithFields:
+//
+//	log = lg.WithFields(log, lg.Bytes("size", n))
+func Bytes(key string, n int64) Field {
+	return Field{Key: key, Val: ByteSize(n)}
+}
+
+// String implements fmt.Stringer, returning b in IEC units,
+// e.g. "512 B" or "1.2 MiB".
+func (b ByteSize) String() string {
+	const units = "KMGTPE"
+	n, sign := abs(int64(b))
+	if n < 1024 {
+		return sign + strconv.FormatUint(n, 10) + " B"
+	}
+
+	f := float64(n)
+	i := -1
+	for f >= 1024 && i < len(units)-1 {
+		f /= 1024
+		i++
+	}
+
+	return sign + formatDecimal(f) + " " + units[i:i+1] + "iB"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// Quantity is a count that is rendered in humanized form, e.g.
+// "3.4M". Use Count to create a Quantity field.
+type Quantity int64
+
+// Count returns a Field whose value, the count n, is rendered in
+// humanized form, for use with WithFields:
+//
+//	log = lg.WithFields(log, lg.Count("rows", n))
+func Count(key string, n int64) Field {
+	return Field{Key: key, Val: Quantity(n)}
+}
+
+// String implements fmt.Stringer, returning q with an SI suffix,
+// e.g. "999", "1.2k" or "3.4M".
+func (q Quantity) String() string {
+	const units = "kMGTPE"
+	n, sign := abs(int64(q))
+	if n < 1000 {
+		return sign + strconv.FormatUint(n, 10)
+	}
+
+	f := float64(n)
+	i := -1
+	for f >= 1000 && i < len(units)-1 {
+		f /= 1000
+		i++
+	}
+
+	return sign + formatDecimal(f) + units[i:i+1]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (q Quantity) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// HumanDuration is a time.Duration that is rendered in humanized form,
+// with precision appropriate to its magnitude, e.g. "1m32s" rather
+// than "1m32.004187s" or "92004ms". Use Duration to create a
+// HumanDuration field.
+type HumanDuration time.Duration
+
+// Duration returns a Field whose value, d, is rendered in
+// humanized form, for use with WithFields:
+//
+//	log = lg.WithFields(log, lg.Duration("elapsed", time.Since(start)))
+func Duration(key string, d time.Duration) Field {
+	return Field{Key: key, Val: HumanDuration(d)}
+}
+
+// String implements fmt.Stringer. Durations of a minute or more
+// are rounded to the second; of a second or more, to the
+// millisecond; and of a millisecond or more, to the microsecond.
+func (d HumanDuration) String() string {
+	td := time.Duration(d)
+	n := td
+	if n < 0 {
+		n = -n
+	}
+
+	switch {
+	case n >= time.Minute:
+		td = td.Round(time.Second)
+	case n >= time.Second:
+		td = td.Round(time.Millisecond)
+	case n >= time.Millisecond:
+		td = td.Round(time.Microsecond)
+	}
+
+	return td.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d HumanDuration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// abs returns the absolute value of n, and "-" if n is negative.
+func abs(n int64) (uint64, string) {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1, "-"
+	}
+	return uint64(n), ""
+}
+
+// formatDecimal formats f with one decimal place,
+// omitting a zero decimal, e.g. "1.2" or "3".
+func formatDecimal(f float64) string {
+	return strings.TrimSuffix(strconv.FormatFloat(f, 'f', 1, 64), ".0")
+}
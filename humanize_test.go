@@ -0,0 +1,78 @@
+package lg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestByteSize(t *testing.T) {
+	testCases := map[int64]string{
+		0:             "0 B",
+		512:           "512 B",
+		1024:          "1 KiB",
+		1536:          "1.5 KiB",
+		1258291:       "1.2 MiB",
+		5 << 30:       "5 GiB",
+		-2048:         "-2 KiB",
+		math.MaxInt64: "8 EiB",
+		math.MinInt64: "-8 EiB",
+	}
+
+	for n, want := range testCases {
+		require.Equal(t, want, lg.ByteSize(n).String(), "n=%d", n)
+	}
+}
+
+func TestQuantity(t *testing.T) {
+	testCases := map[int64]string{
+		0:              "0",
+		999:            "999",
+		1000:           "1k",
+		1234:           "1.2k",
+		3_400_000:      "3.4M",
+		-5_000_000_000: "-5G",
+	}
+
+	for n, want := range testCases {
+		require.Equal(t, want, lg.Quantity(n).String(), "n=%d", n)
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	testCases := map[time.Duration]string{
+		0:                                      "0s",
+		850 * time.Nanosecond:                  "850ns",
+		42*time.Millisecond + 123456:           "42.123ms",
+		1234567 * time.Microsecond:             "1.235s",
+		92*time.Second + 4187*time.Microsecond: "1m32s",
+		-(90 * time.Minute):                    "-1h30m0s",
+	}
+
+	for d, want := range testCases {
+		require.Equal(t, want, lg.HumanDuration(d).String(), "d=%d", int64(d))
+	}
+}
+
+func TestHumanize_Fields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, false, false, 0)
+	lg.WithFields(log,
+		lg.Bytes("size", 1258291),
+		lg.Count("rows", 3_400_000),
+		lg.Duration("elapsed", 92*time.Second+4187*time.Microsecond),
+	).Debug("done")
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "1.2 MiB", m["size"])
+	require.Equal(t, "3.4M", m["rows"])
+	require.Equal(t, "1m32s", m["elapsed"])
+}
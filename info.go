@@ -0,0 +1,75 @@
+package lg
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+)
+
+// ProcessInfo returns fields describing the current process:
+// "hostname", "pid", and "exe" (the base name of the executable).
+// The hostname and exe fields are omitted if they can't be determined.
+func ProcessInfo() []Field {
+	var fields []Field
+	if host, err := os.Hostname(); err == nil {
+		fields = append(fields, Field{Key: "hostname", Val: host})
+	}
+
+	fields = append(fields, Field{Key: "pid", Val: os.Getpid()})
+
+	if exe, err := os.Executable(); err == nil {
+		fields = append(fields, Field{Key: "exe", Val: filepath.Base(exe)})
+	}
+
+	return fields
+}
+
+// WithProcessInfo returns a child of log that has the fields
+// returned by ProcessInfo. This is typically invoked once,
+// when the application's root Log is constructed:
+//
+//	log := lg.WithProcessInfo(zaplg.New())
+//
+// This works with any Log impl.
+func WithProcessInfo(log Log) Log {
+	return WithFields(log, ProcessInfo()...)
+}
+
+// BuildInfo returns fields describing the build of the running
+// binary, as reported by debug.ReadBuildInfo: "version" (the main
+// module version), "vcs_revision", and "vcs_modified" (true if the
+// working tree had uncommitted changes). Fields that aren't
+// available are omitted; notably, VCS info is only stamped
+// into binaries built via "go build".
+func BuildInfo() []Field {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	var fields []Field
+	if bi.Main.Version != "" {
+		fields = append(fields, Field{Key: "version", Val: bi.Main.Version})
+	}
+
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			fields = append(fields, Field{Key: "vcs_revision", Val: setting.Value})
+		case "vcs.modified":
+			modified, err := strconv.ParseBool(setting.Value)
+			if err == nil {
+				fields = append(fields, Field{Key: "vcs_modified", Val: modified})
+			}
+		}
+	}
+
+	return fields
+}
+
+// WithBuildInfo returns a child of log that has the fields
+// returned by BuildInfo. This works with any Log impl.
+func WithBuildInfo(log Log) Log {
+	return WithFields(log, BuildInfo()...)
+}
@@ -0,0 +1,46 @@
+package lg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestWithProcessInfo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := lg.WithProcessInfo(zaplg.NewWith(buf, "json", false, false, true, false, 0))
+	log.Debug("msg")
+
+	m := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, float64(os.Getpid()), m["pid"])
+
+	host, err := os.Hostname()
+	require.NoError(t, err)
+	require.Equal(t, host, m["hostname"])
+	require.NotEmpty(t, m["exe"])
+
+	logItAll(lg.WithProcessInfo(testlg.New(t)))
+	logItAll(lg.WithProcessInfo(lg.Discard()))
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	fields := lg.BuildInfo()
+	require.NotEmpty(t, fields)
+	require.Equal(t, "version", fields[0].Key)
+
+	buf := &bytes.Buffer{}
+	log := lg.WithBuildInfo(zaplg.NewWith(buf, "json", false, false, true, false, 0))
+	log.Debug("msg")
+
+	m := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, fields[0].Val, m["version"])
+}
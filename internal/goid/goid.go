@@ -0,0 +1,23 @@
+// Package goid reports goroutine IDs, for use in diagnostics.
+package goid
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// ID returns the ID of the current goroutine, as parsed from the
+// first line of runtime.Stack output, which looks like
+// "goroutine 18 [running]:". Zero is returned on failure.
+func ID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
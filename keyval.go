@@ -0,0 +1,110 @@
+package lg
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// BadKey is the key used for a value whose key is invalid, e.g.
+// an empty key, or the dangling value of an odd number of
+// key-value args.
+const BadKey = "!BADKEY"
+
+var strict atomic.Bool
+
+// SetStrict sets whether misuse of structured field args, such as
+// an empty key or an odd number of key-value args, causes a panic.
+// By default, misuse is reported via a WARN entry, and the offending
+// values are logged under BadKey. Strict mode is intended for
+// development and testing.
+func SetStrict(b bool) {
+	strict.Store(b)
+}
+
+// ValidKey returns key if it is valid. If key is invalid (empty), the
+// misuse is reported (see SetStrict) to log, and BadKey is returned.
+// ValidKey is intended for use by Log impls' With methods, and
+// reports the caller of With as the caller.
+func ValidKey(log Log, key string) string {
+	if key != "" {
+		return key
+	}
+
+	reportInvalid(log, 3, "empty key")
+	return BadKey
+}
+
+// WithFields returns a child of log that has each of fields.
+// PII fields are omitted under PIIDrop (see SetPIIPolicy).
+func WithFields(log Log, fields ...Field) Log {
+	for _, f := range fields {
+		if isDroppedPII(f.Val) {
+			continue
+		}
+		log = log.With(f.Key, f.Val)
+	}
+
+	return log
+}
+
+// WithKeyVals returns a child of log that has fields built
+// from kvs, which must be alternating string keys and values:
+//
+//	log = lg.WithKeyVals(log, "user", id, "attempts", n)
+//
+// Misuse, such as an odd number of args, or a non-string or nil
+// key, is reported (see SetStrict) to log, and the offending
+// values are added under BadKey.
+func WithKeyVals(log Log, kvs ...any) Log {
+	fields, problems := keyValFields(kvs)
+	if len(problems) > 0 {
+		reportInvalid(log, 2, strings.Join(problems, "; "))
+	}
+
+	return WithFields(log, fields...)
+}
+
+// keyValFields converts kvs to fields, returning a description
+// of each misuse encountered.
+func keyValFields(kvs []any) (fields []Field, problems []string) {
+	fields = make([]Field, 0, (len(kvs)+1)/2)
+	for i := 0; i < len(kvs); i += 2 {
+		if i == len(kvs)-1 {
+			problems = append(problems, fmt.Sprintf("odd number of key-value args: dangling value {%v}", kvs[i]))
+			fields = append(fields, Field{Key: BadKey, Val: kvs[i]})
+			break
+		}
+
+		switch key := kvs[i].(type) {
+		case nil:
+			problems = append(problems, fmt.Sprintf("nil key at arg %d", i))
+			fields = append(fields, Field{Key: BadKey, Val: kvs[i+1]})
+		case string:
+			if key == "" {
+				problems = append(problems, fmt.Sprintf("empty key at arg %d", i))
+				key = BadKey
+			}
+			fields = append(fields, Field{Key: key, Val: kvs[i+1]})
+		default:
+			problems = append(problems, fmt.Sprintf("non-string key {%v} of type %T at arg %d", key, key, i))
+			fields = append(fields, Field{Key: BadKey, Val: kvs[i+1]})
+		}
+	}
+
+	return fields, problems
+}
+
+// reportInvalid panics if strict mode is set; otherwise it logs
+// msg at WARN level to log. Arg skip is the caller skip applied
+// to log, relative to reportInvalid.
+func reportInvalid(log Log, skip int, msg string) {
+	msg = "lg: invalid structured field args: " + msg
+	if strict.Load() {
+		panic(msg)
+	}
+
+	if log != nil {
+		AddCallerSkip(log, skip).Warn(msg)
+	}
+}
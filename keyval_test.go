@@ -0,0 +1,86 @@
+package lg_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestWithKeyVals(t *testing.T) {
+	testCases := []struct {
+		name      string
+		kvs       []any
+		wantWarn  bool
+		wantField map[string]any
+	}{
+		{name: "empty", kvs: nil, wantField: map[string]any{}},
+		{name: "valid", kvs: []any{"a", 1, "b", "x"}, wantField: map[string]any{"a": float64(1), "b": "x"}},
+		{name: "odd", kvs: []any{"a", 1, "dangling"}, wantWarn: true,
+			wantField: map[string]any{"a": float64(1), lg.BadKey: "dangling"}},
+		{name: "nil_key", kvs: []any{nil, 1}, wantWarn: true, wantField: map[string]any{lg.BadKey: float64(1)}},
+		{name: "empty_key", kvs: []any{"", 1}, wantWarn: true, wantField: map[string]any{lg.BadKey: float64(1)}},
+		{name: "int_key", kvs: []any{7, 1}, wantWarn: true, wantField: map[string]any{lg.BadKey: float64(1)}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+
+			lg.WithKeyVals(log, tc.kvs...).Debug("msg")
+
+			var entries []map[string]any
+			sc := bufio.NewScanner(buf)
+			for sc.Scan() {
+				m := map[string]any{}
+				require.NoError(t, json.Unmarshal(sc.Bytes(), &m))
+				entries = append(entries, m)
+			}
+
+			if tc.wantWarn {
+				require.Len(t, entries, 2)
+				require.Equal(t, "warn", entries[0]["level"])
+				require.Contains(t, entries[0]["message"], "invalid structured field args")
+				require.Contains(t, entries[0]["caller"], "keyval_test.go")
+				entries = entries[1:]
+			}
+
+			require.Len(t, entries, 1)
+			for k, v := range tc.wantField {
+				require.Equal(t, v, entries[0][k])
+			}
+		})
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+
+	log.With("", 1).Debug("msg")
+	sc := bufio.NewScanner(buf)
+	require.True(t, sc.Scan())
+	require.Contains(t, sc.Text(), "empty key")
+	require.Contains(t, sc.Text(), "keyval_test.go")
+	require.True(t, sc.Scan())
+	require.Contains(t, sc.Text(), `"`+lg.BadKey+`":1`)
+}
+
+func TestSetStrict(t *testing.T) {
+	lg.SetStrict(true)
+	t.Cleanup(func() { lg.SetStrict(false) })
+
+	log := zaplg.NewWith(&bytes.Buffer{}, "json", false, false, true, true, 0)
+	require.NotPanics(t, func() { lg.WithKeyVals(log, "a", 1) })
+	require.Panics(t, func() { lg.WithKeyVals(log, "a") })
+	require.Panics(t, func() { lg.WithKeyVals(log, 1, 1) })
+	require.Panics(t, func() { log.With("", 1) })
+}
@@ -0,0 +1,43 @@
+package lg
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LazyValue is a field value that is computed when an entry is
+// emitted, rather than when the field is added. Use Lazy to create
+// a LazyValue.
+type LazyValue func() any
+
+// Lazy returns a LazyValue for fn, for use with With:
+//
+//	log = log.With("queue_depth", lg.Lazy(func() any { return q.Len() }))
+//
+// Log impls that support LazyValue (such as zaplg) invoke fn for each
+// emitted entry, so that gauges such as queue depth or memory usage
+// are fresh on every entry. For other impls, LazyValue implements
+// fmt.Stringer and json.Marshaler, which invoke fn when the value is
+// rendered.
+func Lazy(fn func() any) LazyValue {
+	return fn
+}
+
+// Value invokes the LazyValue's func, and returns its value.
+func (v LazyValue) Value() any {
+	if v == nil {
+		return nil
+	}
+
+	return v()
+}
+
+// String implements fmt.Stringer.
+func (v LazyValue) String() string {
+	return fmt.Sprint(v.Value())
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v LazyValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Value())
+}
@@ -0,0 +1,28 @@
+package lg_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+func TestLazy(t *testing.T) {
+	n := 0
+	v := lg.Lazy(func() any {
+		n++
+		return n
+	})
+
+	require.Equal(t, 1, v.Value())
+	require.Equal(t, "2", fmt.Sprint(v))
+
+	b, err := json.Marshal(map[string]any{"n": v})
+	require.NoError(t, err)
+	require.Equal(t, `{"n":3}`, string(b))
+
+	require.Nil(t, lg.LazyValue(nil).Value())
+}
@@ -0,0 +1,106 @@
+package lg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Level is a log level. The zero value is LevelDebug.
+type Level int8
+
+const (
+	// LevelDebug is the DEBUG level: for developers, for
+	// after-the-fact diagnosis of problems.
+	LevelDebug Level = iota
+
+	// LevelWarn is the WARN level: the business operation
+	// didn't fail, but something fishy happened.
+	LevelWarn
+
+	// LevelError is the ERROR level: a business
+	// operation failed.
+	LevelError
+)
+
+// String returns the lowercase name of the level,
+// e.g. "debug".
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "Level(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Level) MarshalText() ([]byte, error) {
+	if !l.valid() {
+		return nil, fmt.Errorf("lg: invalid level: %d", int(l))
+	}
+
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = level
+	return nil
+}
+
+func (l Level) valid() bool {
+	return l >= LevelDebug && l <= LevelError
+}
+
+// ParseLevel parses a level name, e.g. "debug". Parsing is
+// case-insensitive, and "warning" is accepted as an alias
+// for "warn".
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelDebug, fmt.Errorf("lg: invalid level: %q", s)
+	}
+}
+
+// enabler is an optional interface that Log impls can
+// implement to report whether a level is enabled.
+type enabler interface {
+	Enabled(level Level) bool
+}
+
+// Enabled reports whether log is enabled for level. It is
+// typically used to guard expensive argument construction:
+//
+//	if lg.Enabled(log, lg.LevelDebug) {
+//	  log.Debugf("state: %s", expensiveDump())
+//	}
+//
+// If the log impl does not report its enabled levels, Enabled
+// returns true. If log is nil, Enabled returns false.
+func Enabled(log Log, level Level) bool {
+	if log == nil {
+		return false
+	}
+
+	if e, ok := log.(enabler); ok {
+		return e.Enabled(level)
+	}
+
+	return true
+}
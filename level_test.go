@@ -0,0 +1,91 @@
+package lg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestParseLevel(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    lg.Level
+		wantErr bool
+	}{
+		{in: "debug", want: lg.LevelDebug},
+		{in: "DEBUG", want: lg.LevelDebug},
+		{in: "warn", want: lg.LevelWarn},
+		{in: "Warning", want: lg.LevelWarn},
+		{in: " error ", want: lg.LevelError},
+		{in: "", wantErr: true},
+		{in: "info", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := lg.ParseLevel(tc.in)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	require.Equal(t, "debug", lg.LevelDebug.String())
+	require.Equal(t, "warn", lg.LevelWarn.String())
+	require.Equal(t, "error", lg.LevelError.String())
+	require.Equal(t, "Level(7)", lg.Level(7).String())
+}
+
+func TestLevel_Text(t *testing.T) {
+	type config struct {
+		Level lg.Level `json:"level"`
+	}
+
+	b, err := json.Marshal(config{Level: lg.LevelWarn})
+	require.NoError(t, err)
+	require.Equal(t, `{"level":"warn"}`, string(b))
+
+	var cfg config
+	require.NoError(t, json.Unmarshal([]byte(`{"level":"ERROR"}`), &cfg))
+	require.Equal(t, lg.LevelError, cfg.Level)
+
+	require.Error(t, json.Unmarshal([]byte(`{"level":"bogus"}`), &cfg))
+
+	_, err = lg.Level(7).MarshalText()
+	require.Error(t, err)
+}
+
+func TestEnabled(t *testing.T) {
+	require.False(t, lg.Enabled(nil, lg.LevelError))
+	require.False(t, lg.Enabled(lg.Discard(), lg.LevelError))
+	require.False(t, lg.Enabled(lg.DiscardStrict(), lg.LevelError))
+
+	log := zaplg.NewWith(&bytes.Buffer{}, "text", false, false, true, false, 0, zaplg.WithLevel(lg.LevelWarn))
+	require.False(t, lg.Enabled(log, lg.LevelDebug))
+	require.True(t, lg.Enabled(log, lg.LevelWarn))
+	require.True(t, lg.Enabled(log, lg.LevelError))
+	require.False(t, lg.Enabled(log.With("k", "v"), lg.LevelDebug))
+	require.False(t, lg.Enabled(lg.AddCallerSkip(log, 1), lg.LevelDebug))
+
+	tlog := testlg.NewWith(t, func(w io.Writer) lg.Log {
+		return zaplg.NewWith(w, "text", false, false, true, false, 0, zaplg.WithLevel(lg.LevelError))
+	})
+	require.False(t, lg.Enabled(tlog, lg.LevelWarn))
+	require.True(t, lg.Enabled(tlog, lg.LevelError))
+	require.True(t, lg.Enabled(testlg.New(t), lg.LevelDebug))
+}
@@ -83,7 +83,25 @@ func AddCallerSkip(log Log, skip int) Log {
 	return log
 }
 
-// Discard returns a Log whose methods are no-op.
+// OrDiscard returns log if non-nil; otherwise it returns Discard.
+// This is useful for libraries that accept an optional Log:
+//
+//	func NewClient(log lg.Log) *Client {
+//	  return &Client{log: lg.OrDiscard(log)}
+//	}
+func OrDiscard(log Log) Log {
+	if log == nil {
+		return Discard()
+	}
+
+	return log
+}
+
+// Discard returns a Log whose methods are no-op. Note that
+// WarnIfFuncError and WarnIfCloseError still execute fn and
+// c.Close respectively (discarding any error), so that resources
+// are released as the caller intended. Use DiscardStrict if
+// fn and c.Close should not be executed.
 func Discard() Log {
 	return discardLog{}
 }
@@ -127,3 +145,29 @@ func (discardLog) Errorf(format string, a ...any) {
 func (discardLog) With(key string, val any) Log {
 	return discardLog{}
 }
+
+// Enabled always returns false.
+func (discardLog) Enabled(level Level) bool {
+	return false
+}
+
+// DiscardStrict returns a Log whose methods are no-op. Unlike
+// Discard, the WarnIfFuncError and WarnIfCloseError methods of
+// the returned Log do not execute fn or c.Close.
+func DiscardStrict() Log {
+	return discardStrictLog{}
+}
+
+type discardStrictLog struct {
+	discardLog
+}
+
+func (discardStrictLog) WarnIfFuncError(fn func() error) {
+}
+
+func (discardStrictLog) WarnIfCloseError(c io.Closer) {
+}
+
+func (discardStrictLog) With(key string, val any) Log {
+	return discardStrictLog{}
+}
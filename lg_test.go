@@ -20,6 +20,57 @@ func TestDiscard(t *testing.T) {
 	logItAll(log)
 }
 
+func TestOrDiscard(t *testing.T) {
+	log := lg.OrDiscard(nil)
+	require.Equal(t, lg.Discard(), log)
+	logItAll(log)
+
+	zlog := zaplg.NewWith(&bytes.Buffer{}, "text", false, true, true, true, 0)
+	require.Equal(t, zlog, lg.OrDiscard(zlog))
+}
+
+func TestAddCallerSkip_Nil(t *testing.T) {
+	require.Nil(t, lg.AddCallerSkip(nil, 1))
+	require.Equal(t, lg.Discard(), lg.AddCallerSkip(lg.Discard(), 1))
+}
+
+func TestDiscardStrict(t *testing.T) {
+	log := lg.DiscardStrict()
+	logItAll(log)
+}
+
+// TestDiscard_Exec verifies that Discard executes the funcs passed
+// to the WarnIf methods, while DiscardStrict does not.
+func TestDiscard_Exec(t *testing.T) {
+	testCases := []struct {
+		name     string
+		log      lg.Log
+		wantExec bool
+	}{
+		{name: "discard", log: lg.Discard(), wantExec: true},
+		{name: "discard_with", log: lg.Discard().With("k", "v"), wantExec: true},
+		{name: "discard_strict", log: lg.DiscardStrict(), wantExec: false},
+		{name: "discard_strict_with", log: lg.DiscardStrict().With("k", "v"), wantExec: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			var fnCalled bool
+			tc.log.WarnIfFuncError(func() error {
+				fnCalled = true
+				return nil
+			})
+			require.Equal(t, tc.wantExec, fnCalled)
+
+			c := &countCloser{}
+			tc.log.WarnIfCloseError(c)
+			require.Equal(t, tc.wantExec, c.count == 1)
+		})
+	}
+}
+
 // TestLog is a smoke test of Log impls. Basically
 // the test exists to verify that nothing explodes. The
 // test does not verify that the output is correct.
@@ -145,3 +196,13 @@ type errCloser struct {
 func (errCloser) Close() error {
 	return errors.New("error: WarnIfCloseError msg")
 }
+
+// countCloser counts invocations of Close.
+type countCloser struct {
+	count int
+}
+
+func (c *countCloser) Close() error {
+	c.count++
+	return nil
+}
@@ -0,0 +1,324 @@
+// Package lgaudit provides a tamper-evident audit trail: an
+// append-only stream of JSON records, in which each record
+// includes the hash of the previous record.
+//
+//	trail, err := lgaudit.Open("audit.log", lgaudit.WithLog(log))
+//	// handle err
+//	defer trail.Close()
+//
+//	err = trail.Audit("user.delete", lg.Field{Key: "user", Val: id})
+//
+// Use Verify to check that a trail has not been modified. Note that
+// by default the hashes are unkeyed: anyone who can write the trail
+// can also rewrite it with a valid chain. Use WithKey for the hashes
+// to be HMACs, so that a valid chain can't be produced without the key.
+package lgaudit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// ErrTampered is returned by Verify and Open if the
+// trail has been modified or truncated.
+var ErrTampered = errors.New("lgaudit: trail has been tampered with")
+
+// Record is an audit record. Each record is written as a
+// line of JSON.
+type Record struct {
+	// Seq is the record's sequence number, starting at zero.
+	Seq uint64 `json:"seq"`
+
+	// Time is the time the record was written.
+	Time time.Time `json:"time"`
+
+	// Event is the name of the audited event, e.g. "user.delete".
+	Event string `json:"event"`
+
+	// Fields holds the event's fields.
+	Fields map[string]any `json:"fields,omitempty"`
+
+	// Prev is the hash of the previous record, or
+	// empty for the first record.
+	Prev string `json:"prev"`
+
+	// Hash is the hex-encoded SHA-256 hash (or HMAC-SHA256, if
+	// the trail is keyed) of Prev followed by the record's JSON
+	// encoding excluding Hash.
+	Hash string `json:"hash,omitempty"`
+}
+
+// hashKey precedes the hash at the end of each line.
+const hashKey = `,"hash":"`
+
+// ErrFailed is returned by Trail.Audit if a previous record
+// could not be written.
+var ErrFailed = errors.New("lgaudit: trail failed")
+
+// ErrClosed is returned by Trail.Audit after Close has been invoked.
+var ErrClosed = errors.New("lgaudit: trail is closed")
+
+// Option is a functional option for New, Open and Verify.
+type Option func(t *Trail)
+
+// WithLog returns an Option that also logs each audited
+// event to log, at DEBUG level, with field "audit.event".
+func WithLog(log lg.Log) Option {
+	return func(t *Trail) {
+		t.log = lg.AddCallerSkip(lg.OrDiscard(log), 1)
+	}
+}
+
+// WithKey returns an Option that sets the key used to compute
+// the records' hashes as HMAC-SHA256. The trail must be verified
+// with the same key.
+func WithKey(key []byte) Option {
+	return func(t *Trail) {
+		t.key = key
+	}
+}
+
+// Trail writes hash-chained audit records. Trail is
+// safe for concurrent use.
+type Trail struct {
+	mu   sync.Mutex
+	w    io.Writer
+	f    *os.File
+	log  lg.Log
+	key  []byte
+	seq  uint64
+	prev string
+
+	// err is the error that failed the trail, if any.
+	err error
+
+	// closed is set by Close.
+	closed bool
+}
+
+// New returns a Trail that writes a new trail to w.
+func New(w io.Writer, opts ...Option) *Trail {
+	t := &Trail{w: w, log: lg.Discard()}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Open returns a Trail that appends to the file at path, which is
+// created if it doesn't exist. If the file already contains records,
+// they are verified (see Verify), and the trail continues from the
+// last record. Invoke Close to close the file.
+func Open(path string, opts ...Option) (*Trail, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	t := New(f, opts...)
+	last, n, err := verify(f, t.key)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	t.f = f
+	if n > 0 {
+		t.seq = last.Seq + 1
+		t.prev = last.Hash
+	}
+
+	return t, nil
+}
+
+// Audit writes a record for event with fields. If the underlying
+// writer has a Sync method (e.g. *os.File), it is invoked after
+// the record is written. Field values are encoded via encoding/json;
+// errors are encoded as their message. If the record can't be
+// written, the trail fails: the record may have been partially
+// written, so subsequent calls return an error wrapping ErrFailed.
+// After Close, Audit returns ErrClosed.
+func (t *Trail) Audit(event string, fields ...lg.Field) error {
+	rec := Record{Time: time.Now().UTC(), Event: event}
+	if len(fields) > 0 {
+		rec.Fields = make(map[string]any, len(fields))
+		for _, f := range fields {
+			if err, ok := f.Val.(error); ok {
+				f.Val = err.Error()
+			}
+			rec.Fields[f.Key] = f.Val
+		}
+	}
+
+	if err := t.write(rec); err != nil {
+		return err
+	}
+
+	lg.WithFields(t.log.With("audit.event", event), fields...).Debugf("audit: %s", event)
+	return nil
+}
+
+// write writes rec, chaining it to the previous record.
+func (t *Trail) write(rec Record) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return ErrClosed
+	}
+
+	if t.err != nil {
+		return t.err
+	}
+
+	rec.Seq, rec.Prev = t.seq, t.prev
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	hash := hashRecord(t.key, rec.Prev, body)
+	line := make([]byte, 0, len(body)+len(hashKey)+len(hash)+3)
+	line = append(line, body[:len(body)-1]...)
+	line = append(line, hashKey...)
+	line = append(line, hash...)
+	line = append(line, "\"}\n"...)
+
+	if _, err = t.w.Write(line); err != nil {
+		t.err = fmt.Errorf("%w: %v", ErrFailed, err)
+		return err
+	}
+
+	// The record is written: the chain continues from it,
+	// even if it can't be synced.
+	t.seq++
+	t.prev = hash
+
+	if s, ok := t.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Close closes the file opened by Open, if any, and
+// marks the trail as closed, so that subsequent calls
+// to Audit return ErrClosed. It is safe to invoke Close
+// more than once.
+func (t *Trail) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil
+	}
+
+	t.closed = true
+	if t.f == nil {
+		return nil
+	}
+
+	return t.f.Close()
+}
+
+// hashRecord returns the hex-encoded SHA-256 hash of prev followed
+// by body, or the HMAC-SHA256 if key is non-empty.
+func hashRecord(key []byte, prev string, body []byte) string {
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+
+	_, _ = io.WriteString(h, prev)
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify reads the trail from r, and checks that each record's hash is
+// correct, and that the records are correctly chained and sequenced.
+// If not, an error wrapping ErrTampered is returned, which reports the
+// line of the first invalid record. Note that Verify can't detect the
+// removal of records from the end of a trail: compare the last
+// record's Seq with an external source if that is a concern. If the
+// trail was written with WithKey, pass the same option.
+func Verify(r io.Reader, opts ...Option) error {
+	t := &Trail{}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	_, _, err := verify(r, t.key)
+	return err
+}
+
+// verify implements Verify, returning the last record,
+// and the number of records.
+func verify(r io.Reader, key []byte) (last Record, n int, err error) {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return last, n, err
+		}
+
+		if len(line) == 0 {
+			return last, n, nil
+		}
+
+		if line[len(line)-1] != '\n' {
+			return last, n, fmt.Errorf("%w: line %d: incomplete record", ErrTampered, n+1)
+		}
+
+		rec, err := checkLine(key, line[:len(line)-1], last, n)
+		if err != nil {
+			return last, n, fmt.Errorf("%w: line %d: %v", ErrTampered, n+1, err)
+		}
+
+		last = rec
+		n++
+	}
+}
+
+// checkLine checks the record in line, which must follow prev,
+// the n-th record.
+func checkLine(key, line []byte, prev Record, n int) (Record, error) {
+	var rec Record
+	i := bytes.LastIndex(line, []byte(hashKey))
+	if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return rec, errors.New("missing hash")
+	}
+
+	body := append(line[:i:i], '}')
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return rec, err
+	}
+
+	switch {
+	case rec.Hash != hashRecord(key, rec.Prev, body):
+		return rec, errors.New("hash mismatch")
+	case n == 0 && (rec.Seq != 0 || rec.Prev != ""):
+		return rec, errors.New("invalid first record")
+	case n > 0 && rec.Seq != prev.Seq+1:
+		return rec, fmt.Errorf("expected seq %d, got %d", prev.Seq+1, rec.Seq)
+	case n > 0 && rec.Prev != prev.Hash:
+		return rec, errors.New("broken chain")
+	}
+
+	return rec, nil
+}
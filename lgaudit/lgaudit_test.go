@@ -0,0 +1,115 @@
+package lgaudit_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgaudit"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+func TestTrail(t *testing.T) {
+	buf := &bytes.Buffer{}
+	trail := lgaudit.New(buf, lgaudit.WithLog(testlg.New(t)))
+	require.NoError(t, trail.Audit("user.create", lg.Field{Key: "user", Val: "alice"}))
+	require.NoError(t, trail.Audit("user.delete", lg.Field{Key: "user", Val: "alice"},
+		lg.Field{Key: "error", Val: errors.New("boom")}))
+	require.NoError(t, trail.Audit("login"))
+	require.NoError(t, trail.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Regexp(t, `^\{"seq":0,"time":"[^"]+","event":"user.create","fields":\{"user":"alice"\},`+
+		`"prev":"","hash":"[0-9a-f]{64}"\}$`, lines[0])
+	require.Contains(t, lines[1], `"fields":{"error":"boom","user":"alice"}`)
+	require.NoError(t, lgaudit.Verify(strings.NewReader(buf.String())))
+
+	testCases := map[string]string{
+		"modified":  strings.Replace(buf.String(), "alice", "bob", 1),
+		"removed":   lines[0] + "\n" + lines[2] + "\n",
+		"reordered": lines[1] + "\n" + lines[0] + "\n",
+		"truncated": buf.String()[:buf.Len()-1],
+		"garbage":   buf.String() + "hello\n",
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, lgaudit.Verify(strings.NewReader(tc)), lgaudit.ErrTampered)
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	trail, err := lgaudit.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, trail.Audit("one"))
+	require.NoError(t, trail.Close())
+
+	// The chain continues across Open.
+	trail, err = lgaudit.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, trail.Audit("two"))
+	require.NoError(t, trail.Close())
+	require.NoError(t, trail.Close())
+	require.ErrorIs(t, trail.Audit("three"), lgaudit.ErrClosed)
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"seq":1,`)
+	require.NoError(t, lgaudit.Verify(bytes.NewReader(b)))
+
+	require.NoError(t, os.WriteFile(path, bytes.Replace(b, []byte("two"), []byte("TWO"), 1), 0o600))
+	_, err = lgaudit.Open(path)
+	require.ErrorIs(t, err, lgaudit.ErrTampered)
+}
+
+func TestTrail_Key(t *testing.T) {
+	buf := &bytes.Buffer{}
+	trail := lgaudit.New(buf, lgaudit.WithKey([]byte("secret")))
+	require.NoError(t, trail.Audit("one"))
+	require.NoError(t, trail.Audit("two"))
+
+	require.NoError(t, lgaudit.Verify(bytes.NewReader(buf.Bytes()), lgaudit.WithKey([]byte("secret"))))
+	require.ErrorIs(t, lgaudit.Verify(bytes.NewReader(buf.Bytes())), lgaudit.ErrTampered)
+	require.ErrorIs(t, lgaudit.Verify(bytes.NewReader(buf.Bytes()), lgaudit.WithKey([]byte("other"))),
+		lgaudit.ErrTampered)
+}
+
+// failWriter writes half of each write and then fails, if fail is set.
+type failWriter struct {
+	bytes.Buffer
+	fail bool
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		n, _ := w.Buffer.Write(p[:len(p)/2])
+		return n, errors.New("disk full")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestTrail_WriteFailure(t *testing.T) {
+	w := &failWriter{}
+	trail := lgaudit.New(w)
+	require.NoError(t, trail.Audit("one"))
+
+	w.fail = true
+	require.Error(t, trail.Audit("two"))
+
+	// The partially written record can't be chained
+	// to, so the trail fails.
+	w.fail = false
+	require.ErrorIs(t, trail.Audit("three"), lgaudit.ErrFailed)
+	require.NotContains(t, w.String(), "three")
+}
@@ -0,0 +1,52 @@
+// Package lgbadger adapts a lg.Log for use as the logger of
+// the Badger key-value store.
+//
+//	opts := badger.DefaultOptions(dir).WithLogger(lgbadger.New(log))
+//
+// The badger.Logger interface is satisfied structurally,
+// so this package does not depend on Badger.
+package lgbadger
+
+import (
+	"strings"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Logger implements badger.Logger. lg has no INFO level: Infof
+// messages, which report routine activity such as compactions,
+// are logged at DEBUG level.
+type Logger struct {
+	log lg.Log
+}
+
+// New returns a Logger that logs to log.
+func New(log lg.Log) *Logger {
+	return &Logger{log: lg.AddCallerSkip(lg.OrDiscard(log), 1)}
+}
+
+// Errorf implements badger.Logger.
+func (l *Logger) Errorf(format string, args ...any) {
+	l.log.Errorf(trimNewline(format), args...)
+}
+
+// Warningf implements badger.Logger.
+func (l *Logger) Warningf(format string, args ...any) {
+	l.log.Warnf(trimNewline(format), args...)
+}
+
+// Infof implements badger.Logger.
+func (l *Logger) Infof(format string, args ...any) {
+	l.log.Debugf(trimNewline(format), args...)
+}
+
+// Debugf implements badger.Logger.
+func (l *Logger) Debugf(format string, args ...any) {
+	l.log.Debugf(trimNewline(format), args...)
+}
+
+// trimNewline trims the trailing newline that Badger
+// includes in many of its format strings.
+func trimNewline(format string) string {
+	return strings.TrimSuffix(format, "\n")
+}
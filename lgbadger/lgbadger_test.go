@@ -0,0 +1,50 @@
+package lgbadger_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgbadger"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// logger is Badger's badger.Logger interface.
+type logger interface {
+	Errorf(string, ...any)
+	Warningf(string, ...any)
+	Infof(string, ...any)
+	Debugf(string, ...any)
+}
+
+var _ logger = (*lgbadger.Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := lgbadger.New(zaplg.NewWith(buf, "json", false, false, true, true, 0))
+
+	l.Errorf("Failure while flushing memtable to disk: %v\n", "EOF")
+	l.Warningf("Compaction took %s\n", "2s")
+	l.Infof("All %d tables opened in %s\n", 3, "1ms")
+	l.Debugf("debug %d", 1)
+
+	var ms []map[string]any
+	sc := bufio.NewScanner(buf)
+	for sc.Scan() {
+		m := map[string]any{}
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &m))
+		ms = append(ms, m)
+	}
+	require.Len(t, ms, 4)
+
+	require.Equal(t, "error", ms[0]["level"])
+	require.Equal(t, "Failure while flushing memtable to disk: EOF", ms[0]["message"])
+	require.Contains(t, ms[0]["caller"], "lgbadger_test.go")
+	require.Equal(t, "warn", ms[1]["level"])
+	require.Equal(t, "debug", ms[2]["level"])
+	require.Equal(t, "All 3 tables opened in 1ms", ms[2]["message"])
+	require.Equal(t, "debug", ms[3]["level"])
+}
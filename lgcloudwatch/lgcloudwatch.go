@@ -0,0 +1,385 @@
+// Package lgcloudwatch provides an io.Writer sink that ships entries
+// to AWS CloudWatch Logs, batching them into PutLogEvents calls, so
+// that services can ship logs without an external agent:
+//
+//	sink := lgcloudwatch.New(client, "/app/prod", instanceID)
+//	defer sink.Close()
+//	log := zaplg.NewWith(sink, "json", true, true, true, true, 0)
+//
+// To avoid a dependency on the AWS SDK, the sink uses the Client
+// interface, which is implemented by a small adapter of the SDK's
+// CloudWatch Logs client:
+//
+//	type cwClient struct{ *cloudwatchlogs.Client }
+//
+//	func (c cwClient) PutLogEvents(ctx context.Context, in *lgcloudwatch.PutLogEventsInput) (*string, error) {
+//	  events := make([]types.InputLogEvent, len(in.Events))
+//	  for i, e := range in.Events {
+//	    events[i] = types.InputLogEvent{Message: aws.String(e.Message), Timestamp: aws.Int64(e.Timestamp.UnixMilli())}
+//	  }
+//	  out, err := c.Client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+//	    LogGroupName: aws.String(in.Group), LogStreamName: aws.String(in.Stream),
+//	    LogEvents: events, SequenceToken: in.SequenceToken,
+//	  })
+//	  var tokenErr *types.InvalidSequenceTokenException
+//	  if errors.As(err, &tokenErr) {
+//	    return nil, &lgcloudwatch.InvalidSequenceTokenError{ExpectedSequenceToken: tokenErr.ExpectedSequenceToken}
+//	  }
+//	  if err != nil {
+//	    return nil, err
+//	  }
+//	  return out.NextSequenceToken, nil
+//	}
+package lgcloudwatch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limits of the PutLogEvents API.
+const (
+	// MaxBatchEvents is the maximum number of events in a batch.
+	MaxBatchEvents = 10000
+
+	// MaxBatchSize is the maximum size of a batch, where the size of
+	// each event is the length of its message plus EventOverhead.
+	MaxBatchSize = 1048576
+
+	// EventOverhead is the number of bytes added to the
+	// size of each event's message.
+	EventOverhead = 26
+
+	// MaxEventSize is the maximum length of an event's message.
+	// Longer messages are truncated.
+	MaxEventSize = 262144 - EventOverhead
+)
+
+// Default values for Sink.
+const (
+	DefaultFlushInterval = 5 * time.Second
+	DefaultMaxPending    = 100000
+	DefaultMaxRetries    = 5
+	DefaultMinBackoff    = 100 * time.Millisecond
+	DefaultMaxBackoff    = 5 * time.Second
+)
+
+// Event is a log event.
+type Event struct {
+	Message   string
+	Timestamp time.Time
+}
+
+// PutLogEventsInput is the input to Client.PutLogEvents.
+type PutLogEventsInput struct {
+	Group         string
+	Stream        string
+	Events        []Event
+	SequenceToken *string
+}
+
+// Client is the subset of the CloudWatch Logs API used by Sink.
+type Client interface {
+	// PutLogEvents uploads a batch of events, returning the next
+	// sequence token, which may be nil. If the sequence token is
+	// invalid, PutLogEvents should return *InvalidSequenceTokenError.
+	PutLogEvents(ctx context.Context, in *PutLogEventsInput) (nextSequenceToken *string, err error)
+}
+
+// InvalidSequenceTokenError is returned by Client.PutLogEvents if
+// the sequence token is invalid. The sink retries with the expected
+// sequence token.
+type InvalidSequenceTokenError struct {
+	ExpectedSequenceToken *string
+}
+
+// Error implements error.
+func (e *InvalidSequenceTokenError) Error() string {
+	return "lgcloudwatch: invalid sequence token"
+}
+
+// Option is a functional option for New.
+type Option func(s *Sink)
+
+// WithFlushInterval returns an Option that sets the interval at which
+// pending events are uploaded. Events are also uploaded when a full
+// batch is pending, and when Sync or Close is invoked. The default
+// is DefaultFlushInterval.
+func WithFlushInterval(d time.Duration) Option {
+	return func(s *Sink) {
+		if d > 0 {
+			s.interval = d
+		}
+	}
+}
+
+// WithMaxPending returns an Option that sets the maximum number of
+// events awaiting upload. If the maximum is reached (e.g. because
+// CloudWatch is unreachable), events are dropped. The default is
+// DefaultMaxPending.
+func WithMaxPending(n int) Option {
+	return func(s *Sink) {
+		if n > 0 {
+			s.maxPending = n
+		}
+	}
+}
+
+// WithRetries returns an Option that sets the number of times a failed
+// upload is retried, and the minimum and maximum backoff between
+// attempts. The backoff doubles after each attempt. The defaults are
+// DefaultMaxRetries, DefaultMinBackoff and DefaultMaxBackoff.
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(s *Sink) {
+		if maxRetries >= 0 {
+			s.maxRetries = maxRetries
+		}
+		if minBackoff > 0 {
+			s.minBackoff = minBackoff
+		}
+		if maxBackoff >= s.minBackoff {
+			s.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// WithOnError returns an Option that sets a func that is invoked
+// when a batch is dropped because its upload failed after retries.
+// The func must not log to the sink.
+func WithOnError(fn func(err error)) Option {
+	return func(s *Sink) {
+		s.onError = fn
+	}
+}
+
+// Sink is an io.Writer that uploads each entry as a CloudWatch
+// Logs event. Entries are uploaded in batches by a background
+// goroutine, so Write doesn't block on the network. Sink is
+// safe for concurrent use.
+type Sink struct {
+	client     Client
+	group      string
+	stream     string
+	interval   time.Duration
+	maxPending int
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	onError    func(err error)
+
+	mu          sync.Mutex
+	pending     []Event
+	pendingSize int
+	closed      bool
+
+	// flushMu serializes uploads, and guards token.
+	flushMu sync.Mutex
+	token   *string
+
+	kick chan struct{}
+	stop chan struct{}
+	done chan struct{}
+
+	uploaded, dropped atomic.Uint64
+}
+
+// New returns a Sink that uploads entries to the log stream in group.
+// The group and stream must already exist. Invoke Close to upload
+// pending events and stop the background goroutine.
+func New(client Client, group, stream string, opts ...Option) *Sink {
+	s := &Sink{
+		client:     client,
+		group:      group,
+		stream:     stream,
+		interval:   DefaultFlushInterval,
+		maxPending: DefaultMaxPending,
+		maxRetries: DefaultMaxRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		kick:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	go s.run()
+	return s
+}
+
+func (s *Sink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.kick:
+		}
+
+		// An error is reported via onError.
+		_ = s.flush(context.Background())
+	}
+}
+
+// Write implements io.Writer. Each Write is an event: the trailing
+// newline is trimmed, and messages longer than MaxEventSize are
+// truncated. If the maximum number of events are pending, the
+// entry is dropped, and Write returns a nil error. After Close,
+// Write uploads the event synchronously.
+func (s *Sink) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimRight(p, "\n"))
+	if len(msg) > MaxEventSize {
+		msg = msg[:MaxEventSize]
+	}
+
+	s.mu.Lock()
+	if len(s.pending) >= s.maxPending {
+		s.mu.Unlock()
+		s.dropped.Add(1)
+		return len(p), nil
+	}
+
+	s.pending = append(s.pending, Event{Message: msg, Timestamp: time.Now()})
+	s.pendingSize += len(msg) + EventOverhead
+	full := len(s.pending) >= MaxBatchEvents || s.pendingSize >= MaxBatchSize
+	closed := s.closed
+	s.mu.Unlock()
+
+	if closed {
+		return len(p), s.flush(context.Background())
+	}
+
+	if full {
+		select {
+		case s.kick <- struct{}{}:
+		default:
+		}
+	}
+
+	return len(p), nil
+}
+
+// Sync uploads pending events, returning an
+// error if an upload failed after retries.
+func (s *Sink) Sync() error {
+	return s.flush(context.Background())
+}
+
+// Stats returns the number of events uploaded, and the number
+// dropped because the maximum pending was reached, or because
+// their upload failed.
+func (s *Sink) Stats() (uploaded, dropped uint64) {
+	return s.uploaded.Load(), s.dropped.Load()
+}
+
+// Close uploads pending events, and stops the background goroutine.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+	return s.flush(context.Background())
+}
+
+// flush uploads the pending events, returning the first error.
+func (s *Sink) flush(ctx context.Context) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	events := s.pending
+	s.pending, s.pendingSize = nil, 0
+	s.mu.Unlock()
+
+	// Events in a batch must be in chronological order, and the
+	// wall clock may have been adjusted between writes.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	var firstErr error
+	for len(events) > 0 {
+		n, size := 0, 0
+		for n < len(events) && n < MaxBatchEvents {
+			size += len(events[n].Message) + EventOverhead
+			if size > MaxBatchSize {
+				break
+			}
+			n++
+		}
+
+		if err := s.put(ctx, events[:n]); err != nil {
+			s.dropped.Add(uint64(n))
+			if s.onError != nil {
+				s.onError(err)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			s.uploaded.Add(uint64(n))
+		}
+
+		events = events[n:]
+	}
+
+	return firstErr
+}
+
+// put uploads batch, with retries. The caller must hold flushMu.
+func (s *Sink) put(ctx context.Context, batch []Event) error {
+	in := &PutLogEventsInput{Group: s.group, Stream: s.stream, Events: batch}
+	backoff := s.minBackoff
+
+	var err error
+	var tokenRetried bool
+	for attempt := 0; ; {
+		in.SequenceToken = s.token
+
+		var next *string
+		if next, err = s.client.PutLogEvents(ctx, in); err == nil {
+			s.token = next
+			return nil
+		}
+
+		var tokenErr *InvalidSequenceTokenError
+		if errors.As(err, &tokenErr) && !tokenRetried {
+			// Retry immediately with the expected token.
+			s.token = tokenErr.ExpectedSequenceToken
+			tokenRetried = true
+			continue
+		}
+
+		if attempt >= s.maxRetries {
+			break
+		}
+
+		attempt++
+		tokenRetried = false
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+
+	return fmt.Errorf("lgcloudwatch: put %d events: %w", len(batch), err)
+}
@@ -0,0 +1,166 @@
+package lgcloudwatch_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgcloudwatch"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// fakeClient is a lgcloudwatch.Client that records batches,
+// and enforces sequence tokens.
+type fakeClient struct {
+	mu      sync.Mutex
+	batches [][]lgcloudwatch.Event
+	seq     int
+	fails   int // Number of calls to fail.
+}
+
+func (c *fakeClient) PutLogEvents(_ context.Context, in *lgcloudwatch.PutLogEventsInput) (*string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fails > 0 {
+		c.fails--
+		return nil, errors.New("throttled")
+	}
+
+	if c.seq > 0 && (in.SequenceToken == nil || *in.SequenceToken != strconv.Itoa(c.seq)) {
+		expected := strconv.Itoa(c.seq)
+		return nil, &lgcloudwatch.InvalidSequenceTokenError{ExpectedSequenceToken: &expected}
+	}
+
+	c.batches = append(c.batches, append([]lgcloudwatch.Event(nil), in.Events...))
+	c.seq++
+	next := strconv.Itoa(c.seq)
+	return &next, nil
+}
+
+func (c *fakeClient) messages() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var msgs [][]string
+	for _, batch := range c.batches {
+		var b []string
+		for _, e := range batch {
+			b = append(b, e.Message)
+		}
+		msgs = append(msgs, b)
+	}
+	return msgs
+}
+
+func TestSink(t *testing.T) {
+	client := &fakeClient{}
+	sink := lgcloudwatch.New(client, "group", "stream", lgcloudwatch.WithFlushInterval(time.Hour))
+	log := zaplg.NewWith(sink, "text", false, false, false, false, 0)
+
+	log.Debug("one")
+	log.Debug("two")
+	require.NoError(t, sink.Sync())
+	log.Debug("three")
+	require.NoError(t, sink.Close())
+	require.NoError(t, sink.Close())
+
+	// Logged after Close: uploaded synchronously.
+	log.Debug("four")
+
+	require.Equal(t, [][]string{{"one", "two"}, {"three"}, {"four"}}, client.messages())
+	uploaded, dropped := sink.Stats()
+	require.Equal(t, uint64(4), uploaded)
+	require.Zero(t, dropped)
+}
+
+func TestSink_SequenceToken(t *testing.T) {
+	// Another process has already uploaded to the stream.
+	client := &fakeClient{seq: 5}
+	sink := lgcloudwatch.New(client, "group", "stream", lgcloudwatch.WithRetries(0, 0, 0))
+	defer sink.Close()
+
+	_, err := sink.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	require.NoError(t, sink.Sync())
+	require.Equal(t, [][]string{{"hello"}}, client.messages())
+}
+
+func TestSink_Retries(t *testing.T) {
+	client := &fakeClient{fails: 2}
+	var errs []error
+	sink := lgcloudwatch.New(client, "group", "stream",
+		lgcloudwatch.WithRetries(2, time.Millisecond, 2*time.Millisecond),
+		lgcloudwatch.WithOnError(func(err error) { errs = append(errs, err) }))
+	defer sink.Close()
+
+	_, _ = sink.Write([]byte("one"))
+	require.NoError(t, sink.Sync())
+	require.Equal(t, [][]string{{"one"}}, client.messages())
+
+	client.mu.Lock()
+	client.fails = 3
+	client.mu.Unlock()
+
+	_, _ = sink.Write([]byte("two"))
+	require.Error(t, sink.Sync())
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), "throttled")
+
+	uploaded, dropped := sink.Stats()
+	require.Equal(t, uint64(1), uploaded)
+	require.Equal(t, uint64(1), dropped)
+}
+
+func TestSink_Limits(t *testing.T) {
+	client := &fakeClient{}
+	sink := lgcloudwatch.New(client, "group", "stream",
+		lgcloudwatch.WithFlushInterval(time.Hour))
+	defer sink.Close()
+
+	// A full batch is uploaded in the background.
+	for i := 0; i < lgcloudwatch.MaxBatchEvents; i++ {
+		_, _ = sink.Write([]byte("x"))
+	}
+	require.Eventually(t, func() bool {
+		return len(client.messages()) == 1
+	}, time.Second, time.Millisecond)
+	require.Len(t, client.messages()[0], lgcloudwatch.MaxBatchEvents)
+
+	// Messages are truncated, and batches are split by size.
+	big := strings.Repeat("x", lgcloudwatch.MaxEventSize+10)
+	for i := 0; i < 5; i++ {
+		_, _ = sink.Write([]byte(big))
+	}
+	require.NoError(t, sink.Sync())
+	msgs := client.messages()
+	require.Len(t, msgs, 3)
+	require.Len(t, msgs[1], 4)
+	require.Len(t, msgs[1][0], lgcloudwatch.MaxEventSize)
+	require.Len(t, msgs[2], 1)
+
+}
+
+func TestSink_MaxPending(t *testing.T) {
+	client := &fakeClient{}
+	sink := lgcloudwatch.New(client, "group", "stream",
+		lgcloudwatch.WithFlushInterval(time.Hour), lgcloudwatch.WithMaxPending(3))
+	defer sink.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := sink.Write([]byte(strconv.Itoa(i)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, sink.Sync())
+
+	require.Equal(t, [][]string{{"0", "1", "2"}}, client.messages())
+	uploaded, dropped := sink.Stats()
+	require.Equal(t, uint64(3), uploaded)
+	require.Equal(t, uint64(2), dropped)
+}
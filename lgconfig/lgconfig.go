@@ -0,0 +1,135 @@
+// Package lgconfig parses log configuration strings.
+//
+// A level spec, in the style of RUST_LOG, sets the default level,
+// and overrides the level for named loggers:
+//
+//	levels, err := lgconfig.ParseLevels("warn,db=debug,http.client=error")
+//	if err != nil {
+//	  return err
+//	}
+//
+//	dbLog := levels.Named(log, "db")                 // debug
+//	poolLog := levels.Named(log, "http.client.pool") // error
+//	apiLog := levels.Named(log, "api")               // warn
+package lgconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// NameKey is the key of the field added by Levels.Named.
+const NameKey = "logger"
+
+// Levels holds the levels parsed from a level spec.
+type Levels struct {
+	// Default is the level for names that
+	// don't match any key of Names.
+	Default lg.Level
+
+	// Names maps logger names to levels. Names are dotted, and
+	// hierarchical: "http" also matches "http.client", and the
+	// longest matching name wins.
+	Names map[string]lg.Level
+}
+
+// ParseLevels parses a level spec, such as "warn,db=debug,http.client=error".
+// The spec is a comma-separated list of items; an item of the form
+// "name=level" sets the level for the named logger, and an item
+// that is only a level sets the default level. Levels are parsed
+// via lg.ParseLevel, with the addition that "info" and "trace",
+// which lg does not have, are treated as "debug". The default
+// level, if not specified, is lg.LevelDebug. The empty spec is valid.
+func ParseLevels(spec string) (Levels, error) {
+	var levels Levels
+	var haveDefault bool
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, lvl, hasName := strings.Cut(item, "=")
+		if !hasName {
+			lvl, name = name, ""
+		}
+
+		level, err := parseLevel(lvl)
+		if err != nil {
+			return Levels{}, fmt.Errorf("lgconfig: invalid level spec item %q: %w", item, err)
+		}
+
+		if !hasName {
+			if haveDefault {
+				return Levels{}, fmt.Errorf("lgconfig: invalid level spec %q: multiple default levels", spec)
+			}
+			haveDefault = true
+			levels.Default = level
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return Levels{}, fmt.Errorf("lgconfig: invalid level spec item %q: empty name", item)
+		}
+
+		if levels.Names == nil {
+			levels.Names = map[string]lg.Level{}
+		}
+		levels.Names[name] = level
+	}
+
+	return levels, nil
+}
+
+// parseLevel is lg.ParseLevel, but also accepts "info" and "trace".
+func parseLevel(s string) (lg.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "info", "trace":
+		return lg.LevelDebug, nil
+	default:
+		return lg.ParseLevel(s)
+	}
+}
+
+// Level returns the level for the named logger.
+func (l Levels) Level(name string) lg.Level {
+	for {
+		if level, ok := l.Names[name]; ok {
+			return level
+		}
+
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return l.Default
+		}
+		name = name[:i]
+	}
+}
+
+// Named returns a Log that logs to log the entries that meet the
+// level for the named logger, adding a field with key NameKey and
+// value name.
+func (l Levels) Named(log lg.Log, name string) lg.Log {
+	return lg.Multi(lg.Dest{Log: lg.OrDiscard(log).With(NameKey, name), Level: l.Level(name)})
+}
+
+// String returns the level spec for l, with names sorted.
+func (l Levels) String() string {
+	items := []string{l.Default.String()}
+	names := make([]string, 0, len(l.Names))
+	for name := range l.Names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		items = append(items, name+"="+l.Names[name].String())
+	}
+
+	return strings.Join(items, ",")
+}
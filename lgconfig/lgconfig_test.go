@@ -0,0 +1,73 @@
+package lgconfig_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgconfig"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestParseLevels(t *testing.T) {
+	testCases := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{spec: "", want: "debug"},
+		{spec: "warn", want: "warn"},
+		{spec: "info,db=debug,http.client=warn", want: "debug,db=debug,http.client=warn"},
+		{spec: " error , db = WARNING ,", want: "error,db=warn"},
+		{spec: "db=error", want: "debug,db=error"},
+		{spec: "warn,error", wantErr: true},
+		{spec: "db=loud", wantErr: true},
+		{spec: "=warn", wantErr: true},
+		{spec: "loud", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.spec, func(t *testing.T) {
+			levels, err := lgconfig.ParseLevels(tc.spec)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, levels.String())
+		})
+	}
+}
+
+func TestLevels_Level(t *testing.T) {
+	levels, err := lgconfig.ParseLevels("warn,db=debug,http=error,http.client=debug")
+	require.NoError(t, err)
+
+	require.Equal(t, lg.LevelWarn, levels.Level("api"))
+	require.Equal(t, lg.LevelWarn, levels.Level(""))
+	require.Equal(t, lg.LevelDebug, levels.Level("db"))
+	require.Equal(t, lg.LevelDebug, levels.Level("db.pool"))
+	require.Equal(t, lg.LevelWarn, levels.Level("dbx"))
+	require.Equal(t, lg.LevelError, levels.Level("http.server"))
+	require.Equal(t, lg.LevelDebug, levels.Level("http.client.pool"))
+}
+
+func TestLevels_Named(t *testing.T) {
+	levels, err := lgconfig.ParseLevels("error,db=debug")
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+
+	levels.Named(log, "db").Debug("db debug")
+	levels.Named(log, "api").Warn("api warn")
+	levels.Named(log, "api").Error("api error")
+
+	require.Equal(t, `{"level":"debug","message":"db debug","logger":"db"}
+{"level":"error","message":"api error","logger":"api"}
+`, buf.String())
+}
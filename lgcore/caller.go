@@ -0,0 +1,114 @@
+package lgcore
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// CallerFormat determines how Caller.String renders a caller.
+type CallerFormat int
+
+const (
+	// CallerShort renders the caller's final directory, file
+	// name and line, e.g. "server/http.go:42". This is the default.
+	CallerShort CallerFormat = iota
+
+	// CallerModule renders the caller's path relative to the root
+	// of the main module, and line, e.g. "internal/server/http.go:42".
+	// See ModulePath.
+	CallerModule
+
+	// CallerFunc renders only the calling function, without the
+	// path or line, e.g. "server.(*Server).handle". This suits output
+	// viewed via tools that already show file:line, such as IDEs.
+	CallerFunc
+)
+
+// WithCallerFormat returns an Option that sets the format
+// of the reported caller. The default is CallerShort.
+func WithCallerFormat(f CallerFormat) Option {
+	return func(l *Log) {
+		l.callerFormat = f
+	}
+}
+
+// ModulePath returns the path of file relative to the root of the
+// main module (as reported by debug.ReadBuildInfo), e.g.
+// "internal/server/http.go", where function is the fully qualified
+// name of a function in file. Files outside the main module are
+// rendered relative to the package path, e.g.
+// "go.uber.org/zap/logger.go". Files in package main are rendered
+// as the final directory and file name, e.g. "app/main.go".
+func ModulePath(file, function string) string {
+	mod := mainModulePath()
+	if mod != "" && strings.HasPrefix(file, mod+"/") {
+		// Built with -trimpath: file is already module-qualified.
+		return file[len(mod)+1:]
+	}
+
+	pkg := FuncPackage(function)
+	if pkg == "main" || pkg == "" {
+		return TrimDirs(file, 1)
+	}
+
+	// External test packages have a "_test" suffix, but their
+	// files live in the directory of the package under test.
+	pkg = strings.TrimSuffix(pkg, "_test")
+	file = TrimDirs(file, 0)
+
+	switch {
+	case mod == "":
+	case pkg == mod:
+		return file
+	case strings.HasPrefix(pkg, mod+"/"):
+		return pkg[len(mod)+1:] + "/" + file
+	}
+
+	return pkg + "/" + file
+}
+
+var (
+	mainModuleOnce sync.Once
+	mainModule     string
+)
+
+// mainModulePath returns the path of the main module,
+// or empty string if not available.
+func mainModulePath() string {
+	mainModuleOnce.Do(func() {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			mainModule = bi.Main.Path
+		}
+	})
+	return mainModule
+}
+
+// FuncPackage returns the package path of fn, which is a fully
+// qualified func name, e.g. "github.com/me/app/db.(*DB).Get".
+func FuncPackage(fn string) string {
+	slash := strings.LastIndexByte(fn, '/')
+	if dot := strings.IndexByte(fn[slash+1:], '.'); dot >= 0 {
+		return fn[:slash+1+dot]
+	}
+
+	return fn
+}
+
+// TrimDirs returns file with all but the final n directories
+// trimmed. If n < 1, only the file's base name is returned.
+func TrimDirs(file string, n int) string {
+	if n < 0 {
+		n = 0
+	}
+
+	idx := len(file)
+	for i := 0; i <= n; i++ {
+		idx = strings.LastIndexByte(file[:idx], '/')
+		if idx == -1 {
+			return file
+		}
+	}
+
+	return file[idx+1:]
+}
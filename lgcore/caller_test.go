@@ -0,0 +1,78 @@
+package lgcore_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgcore"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+func TestModulePath(t *testing.T) {
+	testCases := []struct {
+		file, function string
+		want           string
+	}{
+		{
+			file:     "/home/me/lg/lgcore/log.go",
+			function: "github.com/neilotoole/lg/v2/lgcore.(*Log).Debug",
+			want:     "lgcore/log.go",
+		},
+		{
+			file:     "github.com/neilotoole/lg/v2/lgcore/log.go", // -trimpath
+			function: "github.com/neilotoole/lg/v2/lgcore.(*Log).Debug",
+			want:     "lgcore/log.go",
+		},
+		{
+			file:     "/home/me/lg/lgcore/log_test.go",
+			function: "github.com/neilotoole/lg/v2/lgcore_test.TestLog",
+			want:     "lgcore/log_test.go",
+		},
+		{
+			file:     "/home/me/go/pkg/mod/go.uber.org/zap@v1.27.0/logger.go",
+			function: "go.uber.org/zap.(*Logger).Info",
+			want:     "go.uber.org/zap/logger.go",
+		},
+		{
+			file:     "/home/me/app/cmd/app/main.go",
+			function: "main.main",
+			want:     "app/main.go",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.want, func(t *testing.T) {
+			require.Equal(t, tc.want, lgcore.ModulePath(tc.file, tc.function))
+		})
+	}
+}
+
+func TestWithCallerFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lgcore.New(buf, nil, lgcore.WithCallerFormat(lgcore.CallerModule)).Debug("module")
+	lgcore.New(buf, nil).Debug("short")
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	require.Regexp(t, `^lgcore/caller_test\.go:\d+$`, ms[0]["caller"])
+	require.Regexp(t, `^lgcore/caller_test\.go:\d+$`, ms[1]["caller"])
+
+	c := lgcore.Caller{
+		Defined: true, File: "/go/pkg/mod/example.com/a/b/c.go", Line: 7,
+		Function: "example.com/a/b.F", Format: lgcore.CallerModule,
+	}
+	require.Equal(t, "example.com/a/b/c.go:7", c.String())
+	c.Format = lgcore.CallerShort
+	require.Equal(t, "b/c.go:7", c.String())
+	c.Format = lgcore.CallerFunc
+	require.Equal(t, "b.F", c.String())
+
+	buf.Reset()
+	lgcore.New(buf, nil, lgcore.WithCallerFormat(lgcore.CallerFunc)).Debug("func")
+	ms = testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "lgcore_test.TestWithCallerFormat", ms[0]["caller"])
+}
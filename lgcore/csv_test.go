@@ -0,0 +1,59 @@
+package lgcore_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+func TestCSVEncoder(t *testing.T) {
+	ent := lgcore.Entry{
+		Time:   time.Date(2022, 11, 10, 10, 0, 0, 0, time.UTC),
+		Level:  lg.LevelWarn,
+		Caller: lgcore.Caller{Defined: true, File: "/app/main.go", Line: 7},
+		Msg:    `said "hi", then left`,
+		Fields: []lg.Field{
+			{Key: "error", Val: errors.New("boom")},
+			{Key: "n", Val: 1},
+			{Key: "ignored", Val: true},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	enc := lgcore.CSVEncoder{}
+	require.NoError(t, enc.WriteHeader(buf))
+	require.NoError(t, enc.Encode(buf, ent))
+	require.Equal(t, "time,level,caller,msg\n"+
+		`2022-11-10T10:00:00.000Z,warn,app/main.go:7,"said ""hi"", then left"`+"\n", buf.String())
+
+	buf.Reset()
+	enc = lgcore.CSVEncoder{
+		Columns:    []string{lgcore.ColumnLevel, "n", "missing", "error", lgcore.ColumnTime},
+		Comma:      '\t',
+		TimeLayout: time.Kitchen,
+	}
+	require.NoError(t, enc.WriteHeader(buf))
+	require.NoError(t, enc.Encode(buf, ent))
+	require.Equal(t, "level\tn\tmissing\terror\ttime\nwarn\t1\t\tboom\t10:00AM\n", buf.String())
+}
+
+func TestCSVEncoder_Log(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := lgcore.CSVEncoder{Columns: []string{lgcore.ColumnLevel, lgcore.ColumnMsg, "k"}}
+	log := lgcore.New(buf, enc)
+
+	log.With("k", "v").Debug("hello")
+	log.Error("world")
+	require.Equal(t, "debug,hello,v\nerror,world,\n", buf.String())
+}
+
+func TestCSVEncoder_InvalidComma(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.Error(t, lgcore.CSVEncoder{Comma: '"'}.Encode(buf, lgcore.Entry{}))
+}
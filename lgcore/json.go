@@ -0,0 +1,72 @@
+package lgcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// rfc3339Milli is an RFC3339 format with millisecond precision.
+const rfc3339Milli = "2006-01-02T15:04:05.000Z07:00"
+
+// JSONEncoder is an Encoder that encodes each entry as a JSON object
+// on a single line, with keys "time", "level", "caller" and "message",
+// followed by the entry's fields. Time and caller are omitted if
+// they are not populated. Field values are encoded via encoding/json;
+// if a value can't be encoded, its fmt %v representation is used.
+type JSONEncoder struct {
+	// TimeLayout is the layout used to format the time. If empty,
+	// RFC3339 with millisecond precision is used.
+	TimeLayout string
+}
+
+// Encode implements Encoder.
+func (e JSONEncoder) Encode(buf *bytes.Buffer, ent Entry) error {
+	buf.WriteByte('{')
+	first := true
+	add := func(key string, val any) {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		writeJSON(buf, key)
+		buf.WriteByte(':')
+		writeJSON(buf, val)
+	}
+
+	if !ent.Time.IsZero() {
+		layout := e.TimeLayout
+		if layout == "" {
+			layout = rfc3339Milli
+		}
+		add("time", ent.Time.Format(layout))
+	}
+
+	add("level", ent.Level.String())
+	if ent.Caller.Defined {
+		add("caller", ent.Caller.String())
+	}
+
+	add("message", ent.Msg)
+	for _, f := range ent.Fields {
+		add(f.Key, f.Val)
+	}
+
+	buf.WriteString("}\n")
+	return nil
+}
+
+// writeJSON writes the JSON encoding of v to buf.
+func writeJSON(buf *bytes.Buffer, v any) {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%v", v))
+	}
+
+	buf.Write(b)
+}
@@ -0,0 +1,95 @@
+// Package lgcore defines the Entry and Encoder types, which allow
+// custom output formats (such as CSV or msgpack) to be implemented
+// once, and used with any log impl that supports them:
+//
+//	// Use the encoder with lgcore's own Log impl, writing to any
+//	// io.Writer, such as the sinks in package lgsink.
+//	log := lgcore.New(sink, myEncoder)
+//
+//	// Or use the encoder with zaplg.
+//	log := zaplg.NewWith(w, "json", true, false, true, true, 0, zaplg.WithEncoder(myEncoder))
+package lgcore
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Entry is a log entry.
+type Entry struct {
+	// Time is the time of the entry. It may be zero.
+	Time time.Time
+
+	// Level is the entry's level.
+	Level lg.Level
+
+	// Caller is the entry's caller. It is only
+	// populated if caller reporting is enabled.
+	Caller Caller
+
+	// Msg is the entry's message.
+	Msg string
+
+	// Fields holds the entry's fields, in the order they were
+	// added. Keys are unique.
+	Fields []lg.Field
+}
+
+// Caller is the location of the code that logged an entry.
+type Caller struct {
+	// Defined is true if the caller is populated.
+	Defined bool
+
+	// File is the full path of the caller's file.
+	File string
+
+	// Line is the line number in File.
+	Line int
+
+	// Function is the fully qualified function name,
+	// e.g. "github.com/me/app/db.(*DB).Get".
+	Function string
+
+	// Format is the format in which String renders the caller.
+	Format CallerFormat
+}
+
+// String returns the caller in the format specified by c.Format,
+// by default "dir/file.go:line", or the empty string if c is
+// not defined.
+func (c Caller) String() string {
+	if !c.Defined {
+		return ""
+	}
+
+	var file string
+	switch c.Format {
+	case CallerFunc:
+		return c.Function[strings.LastIndexByte(c.Function, '/')+1:]
+	case CallerModule:
+		file = ModulePath(c.File, c.Function)
+	default:
+		file = TrimDirs(c.File, 1)
+	}
+
+	return file + ":" + strconv.Itoa(c.Line)
+}
+
+// Encoder encodes entries.
+type Encoder interface {
+	// Encode appends the encoding of ent to buf. For line-oriented
+	// formats, the encoding should end with a newline.
+	Encode(buf *bytes.Buffer, ent Entry) error
+}
+
+// EncoderFunc adapts a func to the Encoder interface.
+type EncoderFunc func(buf *bytes.Buffer, ent Entry) error
+
+// Encode implements Encoder.
+func (fn EncoderFunc) Encode(buf *bytes.Buffer, ent Entry) error {
+	return fn(buf, ent)
+}
@@ -0,0 +1,43 @@
+package lgcore_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+func TestCaller_String(t *testing.T) {
+	require.Empty(t, lgcore.Caller{}.String())
+	require.Equal(t, "app/main.go:42",
+		lgcore.Caller{Defined: true, File: "/home/me/app/main.go", Line: 42}.String())
+	require.Equal(t, "main.go:7", lgcore.Caller{Defined: true, File: "main.go", Line: 7}.String())
+}
+
+func TestJSONEncoder(t *testing.T) {
+	ent := lgcore.Entry{
+		Time:   time.Date(2022, 11, 10, 10, 0, 0, 0, time.UTC),
+		Level:  lg.LevelError,
+		Caller: lgcore.Caller{Defined: true, File: "/app/main.go", Line: 7},
+		Msg:    "failed",
+		Fields: []lg.Field{
+			{Key: "error", Val: errors.New("boom")},
+			{Key: "fn", Val: func() {}},
+			{Key: "n", Val: 1},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, lgcore.JSONEncoder{}.Encode(buf, ent))
+	require.Regexp(t, `^\{"time":"2022-11-10T10:00:00.000Z","level":"error","caller":"app/main.go:7",`+
+		`"message":"failed","error":"boom","fn":"0x[0-9a-f]+","n":1\}\n$`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, lgcore.JSONEncoder{TimeLayout: time.Kitchen}.Encode(buf, lgcore.Entry{Time: ent.Time}))
+	require.Equal(t, `{"time":"10:00AM","level":"debug","message":""}`+"\n", buf.String())
+}
@@ -0,0 +1,225 @@
+package lgcore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Option is a functional option for New.
+type Option func(l *Log)
+
+// WithLevel returns an Option that sets the minimum level
+// of entries that are logged. The default is lg.LevelDebug.
+func WithLevel(level lg.Level) Option {
+	return func(l *Log) {
+		l.level = level
+	}
+}
+
+// WithCaller returns an Option that sets whether the caller
+// is reported. The default is true.
+func WithCaller(caller bool) Option {
+	return func(l *Log) {
+		l.caller = caller
+	}
+}
+
+// WithTime returns an Option that sets whether the time
+// is reported. The default is true.
+func WithTime(timestamp bool) Option {
+	return func(l *Log) {
+		l.timestamp = timestamp
+	}
+}
+
+// Log is a lg.Log that encodes entries with an Encoder,
+// and writes them to an io.Writer. Each entry is written
+// via a single call to Write. Log is safe for concurrent use.
+type Log struct {
+	w         io.Writer
+	enc       Encoder
+	mu        *sync.Mutex
+	level     lg.Level
+	caller    bool
+	timestamp bool
+	skip      int
+	fields    []lg.Field
+
+	callerFormat CallerFormat
+}
+
+// New returns a Log that writes entries encoded by enc to w.
+// If enc is nil, JSONEncoder is used.
+func New(w io.Writer, enc Encoder, opts ...Option) *Log {
+	if enc == nil {
+		enc = JSONEncoder{}
+	}
+
+	l := &Log{w: w, enc: enc, mu: &sync.Mutex{}, caller: true, timestamp: true}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+var bufPool = sync.Pool{New: func() any { return &bytes.Buffer{} }}
+
+// log writes an entry. It must be invoked directly by
+// the exported methods, for the caller to be correct.
+func (l *Log) log(level lg.Level, msg string, fields []lg.Field) {
+	ent := Entry{Level: level, Msg: msg, Fields: l.fields}
+	if len(fields) > 0 {
+		ent.Fields = mergeFields(l.fields, fields)
+	}
+
+	if l.timestamp {
+		ent.Time = time.Now()
+	}
+
+	if l.caller {
+		var pcs [1]uintptr
+		if runtime.Callers(3+l.skip, pcs[:]) > 0 {
+			frame, _ := runtime.CallersFrames(pcs[:]).Next()
+			ent.Caller = Caller{
+				Defined: true, File: frame.File, Line: frame.Line, Function: frame.Function,
+				Format: l.callerFormat,
+			}
+		}
+	}
+
+	buf := bufPool.Get().(*bytes.Buffer) //nolint:errcheck // pool only holds *bytes.Buffer
+	defer func() {
+		buf.Reset()
+		bufPool.Put(buf)
+	}()
+
+	if err := l.enc.Encode(buf, ent); err != nil {
+		buf.Reset()
+		fmt.Fprintf(buf, "lgcore: failed to encode entry: %v: %s\n", err, msg)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(buf.Bytes())
+}
+
+// Enabled reports whether level is enabled.
+func (l *Log) Enabled(level lg.Level) bool {
+	return level >= l.level
+}
+
+// AddCallerSkip implements the optional interface used by lg.AddCallerSkip.
+func (l *Log) AddCallerSkip(skip int) lg.Log {
+	clone := *l
+	clone.skip += skip
+	return &clone
+}
+
+// Debug implements lg.Log.
+func (l *Log) Debug(a ...any) {
+	if l.Enabled(lg.LevelDebug) {
+		l.log(lg.LevelDebug, fmt.Sprint(a...), nil)
+	}
+}
+
+// Debugf implements lg.Log.
+func (l *Log) Debugf(format string, a ...any) {
+	if l.Enabled(lg.LevelDebug) {
+		l.log(lg.LevelDebug, fmt.Sprintf(format, a...), nil)
+	}
+}
+
+// Warn implements lg.Log.
+func (l *Log) Warn(a ...any) {
+	if l.Enabled(lg.LevelWarn) {
+		l.log(lg.LevelWarn, fmt.Sprint(a...), nil)
+	}
+}
+
+// Warnf implements lg.Log.
+func (l *Log) Warnf(format string, a ...any) {
+	if l.Enabled(lg.LevelWarn) {
+		l.log(lg.LevelWarn, fmt.Sprintf(format, a...), nil)
+	}
+}
+
+// WarnIfError implements lg.Log.
+func (l *Log) WarnIfError(err error) {
+	if err != nil && l.Enabled(lg.LevelWarn) {
+		l.log(lg.LevelWarn, err.Error(), lg.ErrorFields(err))
+	}
+}
+
+// WarnIfFuncError implements lg.Log.
+func (l *Log) WarnIfFuncError(fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	if err := fn(); err != nil && l.Enabled(lg.LevelWarn) {
+		l.log(lg.LevelWarn, err.Error(), lg.ErrorFields(err))
+	}
+}
+
+// WarnIfCloseError implements lg.Log.
+func (l *Log) WarnIfCloseError(c io.Closer) {
+	if c == nil {
+		return
+	}
+
+	if err := c.Close(); err != nil && l.Enabled(lg.LevelWarn) {
+		l.log(lg.LevelWarn, err.Error(), lg.ErrorFields(err))
+	}
+}
+
+// Error implements lg.Log.
+func (l *Log) Error(a ...any) {
+	if l.Enabled(lg.LevelError) {
+		l.log(lg.LevelError, fmt.Sprint(a...), nil)
+	}
+}
+
+// Errorf implements lg.Log.
+func (l *Log) Errorf(format string, a ...any) {
+	if l.Enabled(lg.LevelError) {
+		l.log(lg.LevelError, fmt.Sprintf(format, a...), nil)
+	}
+}
+
+// With implements lg.Log. If key has already been added, its
+// value is replaced.
+func (l *Log) With(key string, val any) lg.Log {
+	key = lg.ValidKey(l, key)
+
+	clone := *l
+	clone.fields = mergeFields(l.fields, []lg.Field{{Key: key, Val: val}})
+	return &clone
+}
+
+// mergeFields returns a new slice containing base, with the
+// values of extra replacing those of base with the same key,
+// and the remaining extra fields appended.
+func mergeFields(base, extra []lg.Field) []lg.Field {
+	merged := make([]lg.Field, len(base), len(base)+len(extra))
+	copy(merged, base)
+
+outer:
+	for _, f := range extra {
+		for i := range merged {
+			if merged[i].Key == f.Key {
+				merged[i].Val = f.Val
+				continue outer
+			}
+		}
+		merged = append(merged, f)
+	}
+
+	return merged
+}
@@ -0,0 +1,81 @@
+package lgcore_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+var _ lg.Log = (*lgcore.Log)(nil)
+
+type codeErr struct{}
+
+func (codeErr) Error() string { return "not found" }
+func (codeErr) Code() string  { return "E404" }
+
+func TestLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := lgcore.New(buf, nil)
+
+	log.With("k", "v").With("n", 1).With("k", "v2").Debugf("hello %s", "world")
+	log.Warn("warn")
+	log.WarnIfError(codeErr{})
+	log.WarnIfFuncError(func() error { return nil })
+	log.Error("error")
+	lg.AddCallerSkip(log, 0).Errorf("errorf")
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 5)
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "hello world", ms[0]["message"])
+	require.Equal(t, "v2", ms[0]["k"])
+	require.Equal(t, float64(1), ms[0]["n"])
+	require.Contains(t, ms[0], "time")
+	require.Contains(t, ms[0]["caller"], "lgcore/log_test.go:")
+	require.Equal(t, "warn", ms[1]["level"])
+	require.Equal(t, "E404", ms[2]["error.code"])
+	require.Equal(t, "error", ms[3]["level"])
+	require.Contains(t, ms[4]["caller"], "lgcore/log_test.go:")
+}
+
+func TestLog_Options(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := lgcore.New(buf, nil, lgcore.WithLevel(lg.LevelWarn),
+		lgcore.WithCaller(false), lgcore.WithTime(false))
+
+	require.False(t, lg.Enabled(log, lg.LevelDebug))
+	log.Debug("disabled")
+	log.Warn("enabled")
+	require.Equal(t, `{"level":"warn","message":"enabled"}`+"\n", buf.String())
+}
+
+func TestLog_Encoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := lgcore.EncoderFunc(func(buf *bytes.Buffer, ent lgcore.Entry) error {
+		if ent.Msg == "bad" {
+			return errors.New("boom")
+		}
+
+		fields := make([]string, len(ent.Fields))
+		for i, f := range ent.Fields {
+			fields[i] = f.Key
+		}
+		buf.WriteString(strings.ToUpper(ent.Level.String()) + "|" + ent.Msg + "|" + strings.Join(fields, ",") + "\n")
+		return nil
+	})
+
+	log := lgcore.New(buf, enc).With("a", 1).With("b", 2)
+	log.Warn("hello")
+	log.Warn("bad")
+	require.Equal(t, "WARN|hello|a,b\nlgcore: failed to encode entry: boom: bad\n", buf.String())
+
+	lgcore.New(io.Discard, enc).Debug("discarded")
+}
@@ -0,0 +1,633 @@
+package lgcore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// MsgpackEncoder is an Encoder that encodes each entry as a compact
+// binary record, for high-volume logs where the overhead of JSON is
+// prohibitive. Use MsgpackReader to decode the records.
+//
+// Each record is a uvarint length prefix, followed by a msgpack map
+// with keys "t" (time, as a msgpack timestamp), "l" (level), "c"
+// (caller, as [file, line, function]), "m" (message) and "f" (fields,
+// as a map). Keys "t" and "c" are omitted if not populated.
+//
+// Field values of type nil, bool, string, []byte, time.Time, and the
+// integer and float types are encoded natively, as are slices and
+// maps with string keys of those types. Errors are encoded as their
+// message, and other values via fmt %v.
+type MsgpackEncoder struct{}
+
+// Encode implements Encoder.
+func (MsgpackEncoder) Encode(buf *bytes.Buffer, ent Entry) error {
+	rec := &bytes.Buffer{}
+
+	n := 3
+	if !ent.Time.IsZero() {
+		n++
+	}
+	if ent.Caller.Defined {
+		n++
+	}
+	writeMapHeader(rec, n)
+
+	if !ent.Time.IsZero() {
+		writeString(rec, "t")
+		writeTime(rec, ent.Time)
+	}
+
+	writeString(rec, "l")
+	writeInt(rec, int64(ent.Level))
+
+	if ent.Caller.Defined {
+		writeString(rec, "c")
+		writeArrayHeader(rec, 3)
+		writeString(rec, ent.Caller.File)
+		writeInt(rec, int64(ent.Caller.Line))
+		writeString(rec, ent.Caller.Function)
+	}
+
+	writeString(rec, "m")
+	writeString(rec, ent.Msg)
+
+	writeString(rec, "f")
+	writeMapHeader(rec, len(ent.Fields))
+	for _, f := range ent.Fields {
+		writeString(rec, f.Key)
+		writeValue(rec, f.Val)
+	}
+
+	var prefix [binary.MaxVarintLen64]byte
+	buf.Write(prefix[:binary.PutUvarint(prefix[:], uint64(rec.Len()))])
+	buf.Write(rec.Bytes())
+	return nil
+}
+
+func writeValue(buf *bytes.Buffer, val any) {
+	switch v := val.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case string:
+		writeString(buf, v)
+	case []byte:
+		writeBytes(buf, v)
+	case time.Time:
+		writeTime(buf, v)
+	case error:
+		writeString(buf, v.Error())
+	case float32:
+		buf.WriteByte(0xca)
+		_ = binary.Write(buf, binary.BigEndian, math.Float32bits(v))
+	case float64:
+		buf.WriteByte(0xcb)
+		_ = binary.Write(buf, binary.BigEndian, math.Float64bits(v))
+	case int, int8, int16, int32, int64:
+		writeInt(buf, reflect.ValueOf(v).Int())
+	case uint, uint8, uint16, uint32, uint64, uintptr:
+		writeUint(buf, reflect.ValueOf(v).Uint())
+	default:
+		rv := reflect.ValueOf(v)
+		switch {
+		case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+			writeArrayHeader(buf, rv.Len())
+			for i := 0; i < rv.Len(); i++ {
+				writeValue(buf, rv.Index(i).Interface())
+			}
+		case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+			writeMapHeader(buf, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				writeString(buf, iter.Key().String())
+				writeValue(buf, iter.Value().Interface())
+			}
+		default:
+			writeString(buf, fmt.Sprintf("%v", v))
+		}
+	}
+}
+
+func writeInt(buf *bytes.Buffer, v int64) {
+	switch {
+	case v >= 0:
+		writeUint(buf, uint64(v))
+	case v >= -32:
+		buf.WriteByte(byte(v))
+	case v >= math.MinInt8:
+		buf.WriteByte(0xd0)
+		buf.WriteByte(byte(v))
+	case v >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		_ = binary.Write(buf, binary.BigEndian, int16(v))
+	case v >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		_ = binary.Write(buf, binary.BigEndian, int32(v))
+	default:
+		buf.WriteByte(0xd3)
+		_ = binary.Write(buf, binary.BigEndian, v)
+	}
+}
+
+func writeUint(buf *bytes.Buffer, v uint64) {
+	switch {
+	case v <= 0x7f:
+		buf.WriteByte(byte(v))
+	case v <= math.MaxUint8:
+		buf.WriteByte(0xcc)
+		buf.WriteByte(byte(v))
+	case v <= math.MaxUint16:
+		buf.WriteByte(0xcd)
+		_ = binary.Write(buf, binary.BigEndian, uint16(v))
+	case v <= math.MaxUint32:
+		buf.WriteByte(0xce)
+		_ = binary.Write(buf, binary.BigEndian, uint32(v))
+	default:
+		buf.WriteByte(0xcf)
+		_ = binary.Write(buf, binary.BigEndian, v)
+	}
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(0xd9)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xda)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdb)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+	buf.WriteString(s)
+}
+
+func writeBytes(buf *bytes.Buffer, b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf.WriteByte(0xc4)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xc5)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xc6)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+	buf.Write(b)
+}
+
+// writeTime writes t using the 96-bit msgpack timestamp extension.
+func writeTime(buf *bytes.Buffer, t time.Time) {
+	buf.Write([]byte{0xc7, 12, 0xff})
+	_ = binary.Write(buf, binary.BigEndian, uint32(t.Nanosecond()))
+	_ = binary.Write(buf, binary.BigEndian, t.Unix())
+}
+
+func writeArrayHeader(buf *bytes.Buffer, n int) {
+	switch {
+	case n < 16:
+		buf.WriteByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xdc)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdd)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+func writeMapHeader(buf *bytes.Buffer, n int) {
+	switch {
+	case n < 16:
+		buf.WriteByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xde)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdf)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+// MaxMsgpackRecordSize is the maximum size of a record
+// accepted by MsgpackReader.
+const MaxMsgpackRecordSize = 16 << 20
+
+// ErrMalformedRecord is returned by MsgpackReader.Next
+// if a record can't be decoded.
+var ErrMalformedRecord = errors.New("lgcore: malformed msgpack record")
+
+// MsgpackReader decodes the records written by MsgpackEncoder.
+type MsgpackReader struct {
+	r   *bufio.Reader
+	rec []byte
+}
+
+// NewMsgpackReader returns a MsgpackReader that reads records from r.
+func NewMsgpackReader(r io.Reader) *MsgpackReader {
+	return &MsgpackReader{r: bufio.NewReader(r)}
+}
+
+// Next decodes the next record. It returns io.EOF if there are
+// no more records, io.ErrUnexpectedEOF if the final record is
+// truncated, and ErrMalformedRecord if a record is invalid.
+//
+// Field values are decoded as nil, bool, string, []byte, time.Time,
+// int64 (uint64 if greater than math.MaxInt64), float32, float64,
+// []any, or map[string]any.
+func (r *MsgpackReader) Next() (Entry, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Entry{}, io.EOF
+		}
+		return Entry{}, io.ErrUnexpectedEOF
+	}
+
+	if size > MaxMsgpackRecordSize {
+		return Entry{}, fmt.Errorf("%w: record size %d exceeds max", ErrMalformedRecord, size)
+	}
+
+	if uint64(cap(r.rec)) < size {
+		r.rec = make([]byte, size)
+	}
+	r.rec = r.rec[:size]
+	if _, err = io.ReadFull(r.r, r.rec); err != nil {
+		return Entry{}, io.ErrUnexpectedEOF
+	}
+
+	d := &decoder{b: r.rec}
+	ent, err := d.entry()
+	if err != nil {
+		return Entry{}, fmt.Errorf("%w: %v", ErrMalformedRecord, err)
+	}
+
+	return ent, nil
+}
+
+// decoder decodes msgpack values from b.
+type decoder struct {
+	b []byte
+}
+
+var errShort = errors.New("short buffer")
+
+func (d *decoder) entry() (Entry, error) {
+	var ent Entry
+	n, err := d.mapHeader()
+	if err != nil {
+		return ent, err
+	}
+
+	for i := 0; i < n; i++ {
+		key, err := d.str()
+		if err != nil {
+			return ent, err
+		}
+
+		switch key {
+		case "t":
+			v, err := d.value()
+			if err != nil {
+				return ent, err
+			}
+			t, ok := v.(time.Time)
+			if !ok {
+				return ent, fmt.Errorf("time is %T", v)
+			}
+			ent.Time = t
+		case "l":
+			v, err := d.value()
+			if err != nil {
+				return ent, err
+			}
+			level, ok := v.(int64)
+			if !ok {
+				return ent, fmt.Errorf("level is %T", v)
+			}
+			ent.Level = lg.Level(level)
+		case "c":
+			if ent.Caller, err = d.caller(); err != nil {
+				return ent, err
+			}
+		case "m":
+			if ent.Msg, err = d.str(); err != nil {
+				return ent, err
+			}
+		case "f":
+			if ent.Fields, err = d.fields(); err != nil {
+				return ent, err
+			}
+		default:
+			// Skip unknown keys, for forward compatibility.
+			if _, err = d.value(); err != nil {
+				return ent, err
+			}
+		}
+	}
+
+	if len(d.b) != 0 {
+		return ent, fmt.Errorf("%d trailing bytes", len(d.b))
+	}
+
+	return ent, nil
+}
+
+func (d *decoder) caller() (Caller, error) {
+	v, err := d.value()
+	if err != nil {
+		return Caller{}, err
+	}
+
+	a, ok := v.([]any)
+	if !ok || len(a) != 3 {
+		return Caller{}, errors.New("invalid caller")
+	}
+
+	file, ok1 := a[0].(string)
+	line, ok2 := a[1].(int64)
+	fn, ok3 := a[2].(string)
+	if !ok1 || !ok2 || !ok3 {
+		return Caller{}, errors.New("invalid caller")
+	}
+
+	return Caller{Defined: true, File: file, Line: int(line), Function: fn}, nil
+}
+
+func (d *decoder) fields() ([]lg.Field, error) {
+	n, err := d.mapHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []lg.Field
+	for i := 0; i < n; i++ {
+		var f lg.Field
+		if f.Key, err = d.str(); err != nil {
+			return nil, err
+		}
+		if f.Val, err = d.value(); err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+func (d *decoder) str() (string, error) {
+	v, err := d.value()
+	if err != nil {
+		return "", err
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("expected string, got %T", v)
+	}
+
+	return s, nil
+}
+
+func (d *decoder) mapHeader() (int, error) {
+	c, err := d.byte()
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case c&0xf0 == 0x80:
+		return int(c & 0x0f), nil
+	case c == 0xde:
+		n, err := d.uint(2)
+		return int(n), err
+	case c == 0xdf:
+		n, err := d.uint(4)
+		return int(n), err
+	default:
+		return 0, fmt.Errorf("expected map, got 0x%02x", c)
+	}
+}
+
+func (d *decoder) byte() (byte, error) {
+	if len(d.b) == 0 {
+		return 0, errShort
+	}
+
+	c := d.b[0]
+	d.b = d.b[1:]
+	return c, nil
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.b) < n {
+		return nil, errShort
+	}
+
+	b := d.b[:n]
+	d.b = d.b[n:]
+	return b, nil
+}
+
+// uint reads a big-endian unsigned integer of size 1, 2, 4 or 8.
+func (d *decoder) uint(size int) (uint64, error) {
+	b, err := d.next(size)
+	if err != nil {
+		return 0, err
+	}
+
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+
+	return v, nil
+}
+
+func (d *decoder) value() (any, error) { //nolint:gocyclo,cyclop,funlen // msgpack type switch
+	c, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		b, err := d.next(int(c & 0x1f))
+		return string(b), err
+	case c&0xf0 == 0x90:
+		return d.array(int(c & 0x0f))
+	case c&0xf0 == 0x80:
+		return d.mapValue(int(c & 0x0f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if v > math.MaxInt64 {
+			return v, nil
+		}
+		return int64(v), nil
+	case 0xd0:
+		v, err := d.uint(1)
+		return int64(int8(v)), err
+	case 0xd1:
+		v, err := d.uint(2)
+		return int64(int16(v)), err
+	case 0xd2:
+		v, err := d.uint(4)
+		return int64(int32(v)), err
+	case 0xd3:
+		v, err := d.uint(8)
+		return int64(v), err
+	case 0xca:
+		v, err := d.uint(4)
+		return math.Float32frombits(uint32(v)), err
+	case 0xcb:
+		v, err := d.uint(8)
+		return math.Float64frombits(v), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(int(n))
+		return string(b), err
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(int(n))
+		return append([]byte(nil), b...), err
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapValue(int(n))
+	case 0xd6, 0xd7, 0xc7:
+		return d.timestamp(c)
+	default:
+		return nil, fmt.Errorf("unsupported type 0x%02x", c)
+	}
+}
+
+func (d *decoder) array(n int) ([]any, error) {
+	if n > len(d.b) {
+		return nil, errShort
+	}
+
+	a := make([]any, n)
+	for i := range a {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+
+	return a, nil
+}
+
+func (d *decoder) mapValue(n int) (map[string]any, error) {
+	if n > len(d.b) {
+		return nil, errShort
+	}
+
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = v
+	}
+
+	return m, nil
+}
+
+// timestamp decodes the msgpack timestamp extension
+// in its 32, 64 and 96-bit forms.
+func (d *decoder) timestamp(c byte) (time.Time, error) {
+	size := 4
+	switch c {
+	case 0xd7:
+		size = 8
+	case 0xc7:
+		n, err := d.uint(1)
+		if err != nil {
+			return time.Time{}, err
+		}
+		size = int(n)
+	}
+
+	typ, err := d.byte()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if typ != 0xff {
+		return time.Time{}, fmt.Errorf("unsupported extension type %d", int8(typ))
+	}
+
+	switch size {
+	case 4:
+		sec, err := d.uint(4)
+		return time.Unix(int64(sec), 0), err
+	case 8:
+		v, err := d.uint(8)
+		return time.Unix(int64(v&(1<<34-1)), int64(v>>34)), err
+	case 12:
+		nsec, err := d.uint(4)
+		if err != nil {
+			return time.Time{}, err
+		}
+		sec, err := d.uint(8)
+		return time.Unix(int64(sec), int64(nsec)), err
+	default:
+		return time.Time{}, fmt.Errorf("invalid timestamp size %d", size)
+	}
+}
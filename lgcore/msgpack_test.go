@@ -0,0 +1,138 @@
+package lgcore_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+func TestMsgpack(t *testing.T) {
+	now := time.Date(2022, 11, 10, 10, 0, 0, 123456789, time.UTC)
+	ents := []lgcore.Entry{
+		{
+			Time:   now,
+			Level:  lg.LevelError,
+			Caller: lgcore.Caller{Defined: true, File: "/app/main.go", Line: 7, Function: "main.main"},
+			Msg:    "failed",
+			Fields: []lg.Field{
+				{Key: "error", Val: errors.New("boom")},
+				{Key: "nil", Val: nil},
+				{Key: "bool", Val: true},
+				{Key: "small", Val: -3},
+				{Key: "int8", Val: int8(-100)},
+				{Key: "int", Val: -70000},
+				{Key: "int64", Val: int64(math.MinInt64)},
+				{Key: "uint", Val: uint(300)},
+				{Key: "uint64", Val: uint64(math.MaxUint64)},
+				{Key: "float32", Val: float32(1.5)},
+				{Key: "float64", Val: 2.5},
+				{Key: "long", Val: string(bytes.Repeat([]byte("x"), 300))},
+				{Key: "bytes", Val: []byte{1, 2}},
+				{Key: "time", Val: now},
+				{Key: "slice", Val: []string{"a", "b"}},
+				{Key: "map", Val: map[string]int{"a": 1}},
+				{Key: "other", Val: struct{ A int }{1}},
+			},
+		},
+		{Level: lg.LevelDebug, Msg: "plain"},
+	}
+
+	buf := &bytes.Buffer{}
+	for _, ent := range ents {
+		require.NoError(t, lgcore.MsgpackEncoder{}.Encode(buf, ent))
+	}
+
+	r := lgcore.NewMsgpackReader(buf)
+	got, err := r.Next()
+	require.NoError(t, err)
+	require.True(t, got.Time.Equal(now))
+	require.Equal(t, lg.LevelError, got.Level)
+	require.Equal(t, ents[0].Caller, got.Caller)
+	require.Equal(t, "failed", got.Msg)
+	require.Equal(t, []lg.Field{
+		{Key: "error", Val: "boom"},
+		{Key: "nil", Val: nil},
+		{Key: "bool", Val: true},
+		{Key: "small", Val: int64(-3)},
+		{Key: "int8", Val: int64(-100)},
+		{Key: "int", Val: int64(-70000)},
+		{Key: "int64", Val: int64(math.MinInt64)},
+		{Key: "uint", Val: int64(300)},
+		{Key: "uint64", Val: uint64(math.MaxUint64)},
+		{Key: "float32", Val: float32(1.5)},
+		{Key: "float64", Val: 2.5},
+		{Key: "long", Val: string(bytes.Repeat([]byte("x"), 300))},
+		{Key: "bytes", Val: []byte{1, 2}},
+		{Key: "time", Val: got.Fields[13].Val},
+		{Key: "slice", Val: []any{"a", "b"}},
+		{Key: "map", Val: map[string]any{"a": int64(1)}},
+		{Key: "other", Val: "{1}"},
+	}, got.Fields)
+	require.True(t, got.Fields[13].Val.(time.Time).Equal(now))
+
+	got, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, lgcore.Entry{Level: lg.LevelDebug, Msg: "plain"}, got)
+
+	_, err = r.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestMsgpackReader_Errors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, lgcore.MsgpackEncoder{}.Encode(buf, lgcore.Entry{Msg: "hello"}))
+	b := buf.Bytes()
+
+	_, err := lgcore.NewMsgpackReader(bytes.NewReader(b[:len(b)-1])).Next()
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+
+	corrupt := append([]byte(nil), b...)
+	corrupt[1] = 0xc1 // never used
+	_, err = lgcore.NewMsgpackReader(bytes.NewReader(corrupt)).Next()
+	require.ErrorIs(t, err, lgcore.ErrMalformedRecord)
+}
+
+func TestMsgpack_Log(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := lgcore.New(buf, lgcore.MsgpackEncoder{})
+	log.With("k", "v").Warn("hello")
+
+	got, err := lgcore.NewMsgpackReader(buf).Next()
+	require.NoError(t, err)
+	require.Equal(t, lg.LevelWarn, got.Level)
+	require.Equal(t, "hello", got.Msg)
+	require.Contains(t, got.Caller.String(), "lgcore/msgpack_test.go:")
+	require.Equal(t, []lg.Field{{Key: "k", Val: "v"}}, got.Fields)
+}
+
+func BenchmarkMsgpackEncoder(b *testing.B) {
+	benchmarkEncoder(b, lgcore.MsgpackEncoder{})
+}
+
+func BenchmarkJSONEncoder(b *testing.B) {
+	benchmarkEncoder(b, lgcore.JSONEncoder{})
+}
+
+func benchmarkEncoder(b *testing.B, enc lgcore.Encoder) {
+	ent := lgcore.Entry{
+		Time:   time.Now(),
+		Level:  lg.LevelWarn,
+		Msg:    "request completed",
+		Fields: []lg.Field{{Key: "status", Val: 200}, {Key: "path", Val: "/api/v1/users"}},
+	}
+
+	buf := &bytes.Buffer{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		_ = enc.Encode(buf, ent)
+	}
+}
@@ -0,0 +1,120 @@
+package lgcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// ANSI escape sequences used by PrettyEncoder.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// PrettyEncoder is an Encoder for local development, which renders
+// each entry as a header line, followed by each field on its own
+// indented line:
+//
+//	12:00:00.123 WARN  request failed  handler/users.go:42
+//	    status: 503
+//	    elapsed: 1.5s
+//	    user: {
+//	      "id": 7
+//	    }
+//
+// Durations are rendered in humanized form via time.Duration.String,
+// e.g. "1m32s". Values that are maps or slices are rendered as
+// indented JSON.
+type PrettyEncoder struct {
+	// Color determines whether the output is colorized
+	// via ANSI escape sequences.
+	Color bool
+
+	// TimeLayout is the layout of the time. If empty,
+	// "15:04:05.000" is used.
+	TimeLayout string
+}
+
+// Encode implements Encoder.
+func (e PrettyEncoder) Encode(buf *bytes.Buffer, ent Entry) error {
+	if !ent.Time.IsZero() {
+		layout := e.TimeLayout
+		if layout == "" {
+			layout = "15:04:05.000"
+		}
+		e.write(buf, ansiDim, ent.Time.Format(layout))
+		buf.WriteByte(' ')
+	}
+
+	e.write(buf, levelColor(ent.Level), fmt.Sprintf("%-5s", strings.ToUpper(ent.Level.String())))
+	buf.WriteByte(' ')
+	buf.WriteString(ent.Msg)
+
+	if ent.Caller.Defined {
+		buf.WriteString("  ")
+		e.write(buf, ansiDim, ent.Caller.String())
+	}
+	buf.WriteByte('\n')
+
+	for _, f := range ent.Fields {
+		buf.WriteString("    ")
+		e.write(buf, ansiCyan, f.Key)
+		buf.WriteString(": ")
+		buf.WriteString(prettyValue(f.Val))
+		buf.WriteByte('\n')
+	}
+
+	return nil
+}
+
+// write writes s to buf, wrapped in the escape sequence
+// color, if e.Color is true.
+func (e PrettyEncoder) write(buf *bytes.Buffer, color, s string) {
+	if !e.Color {
+		buf.WriteString(s)
+		return
+	}
+
+	buf.WriteString(color)
+	buf.WriteString(s)
+	buf.WriteString(ansiReset)
+}
+
+func levelColor(level lg.Level) string {
+	switch level {
+	case lg.LevelWarn:
+		return ansiYellow
+	case lg.LevelError:
+		return ansiRed
+	default:
+		return ansiMagenta
+	}
+}
+
+// prettyValue returns v in humanized form.
+func prettyValue(v any) string {
+	switch v := v.(type) {
+	case time.Duration:
+		return v.String()
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	case map[string]any, []any:
+		b, err := json.MarshalIndent(v, "    ", "  ")
+		if err == nil {
+			return string(b)
+		}
+	}
+
+	return fmt.Sprint(v)
+}
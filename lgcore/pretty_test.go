@@ -0,0 +1,41 @@
+package lgcore_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+func TestPrettyEncoder(t *testing.T) {
+	ent := lgcore.Entry{
+		Time:   time.Date(2022, 11, 10, 12, 0, 0, 123e6, time.UTC),
+		Level:  lg.LevelWarn,
+		Caller: lgcore.Caller{Defined: true, File: "/src/handler/users.go", Line: 42},
+		Msg:    "request failed",
+		Fields: []lg.Field{
+			{Key: "status", Val: 503},
+			{Key: "elapsed", Val: 92 * time.Second},
+			{Key: "user", Val: map[string]any{"id": 7}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, lgcore.PrettyEncoder{}.Encode(buf, ent))
+	require.Equal(t, `12:00:00.123 WARN  request failed  handler/users.go:42
+    status: 503
+    elapsed: 1m32s
+    user: {
+      "id": 7
+    }
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, lgcore.PrettyEncoder{Color: true}.Encode(buf, ent))
+	require.Contains(t, buf.String(), "\x1b[33mWARN \x1b[0m request failed")
+	require.Contains(t, buf.String(), "\x1b[36mstatus\x1b[0m: 503")
+}
@@ -0,0 +1,163 @@
+package lgcore
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// DefaultSDID is the default STRUCTURED-DATA element ID
+// of RFC5424Encoder. 32473 is the IANA example enterprise number.
+const DefaultSDID = "lg@32473"
+
+// RFC5424Encoder is an Encoder that encodes each entry as an RFC5424
+// syslog line, with the entry's fields (and caller) as the parameters
+// of a STRUCTURED-DATA element, so that entries shipped to syslog
+// infrastructure retain their structure:
+//
+//	<12>1 2022-11-10T10:00:00.000000Z host app 42 - [lg@32473 user="alice"] hello
+//
+// Empty header fields are written as the NILVALUE "-". Characters that
+// are not permitted in a header field or parameter name are replaced
+// with '_'.
+type RFC5424Encoder struct {
+	// Facility is the syslog facility code. If zero,
+	// 1 (user-level) is used.
+	Facility int
+
+	// Hostname is the HOSTNAME header field.
+	Hostname string
+
+	// AppName is the APP-NAME header field.
+	AppName string
+
+	// ProcID is the PROCID header field, typically the process ID.
+	ProcID string
+
+	// MsgID is the MSGID header field.
+	MsgID string
+
+	// SDID is the ID of the STRUCTURED-DATA element. If empty,
+	// DefaultSDID is used.
+	SDID string
+}
+
+// Encode implements Encoder.
+func (e RFC5424Encoder) Encode(buf *bytes.Buffer, ent Entry) error {
+	facility := e.Facility
+	if facility == 0 {
+		facility = 1
+	}
+
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(facility*8 + severity(ent.Level)))
+	buf.WriteString(">1 ")
+
+	if ent.Time.IsZero() {
+		buf.WriteByte('-')
+	} else {
+		buf.WriteString(ent.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+	}
+
+	for _, field := range []struct {
+		val string
+		max int
+	}{{e.Hostname, 255}, {e.AppName, 48}, {e.ProcID, 128}, {e.MsgID, 32}} {
+		buf.WriteByte(' ')
+		writeSDName(buf, field.val, field.max, false)
+	}
+
+	buf.WriteByte(' ')
+	if len(ent.Fields) == 0 && !ent.Caller.Defined {
+		buf.WriteByte('-')
+	} else {
+		id := e.SDID
+		if id == "" {
+			id = DefaultSDID
+		}
+
+		buf.WriteByte('[')
+		writeSDName(buf, id, 32, true)
+		if ent.Caller.Defined {
+			writeSDParam(buf, "caller", ent.Caller.String())
+		}
+		for _, f := range ent.Fields {
+			writeSDParam(buf, f.Key, f.Val)
+		}
+		buf.WriteByte(']')
+	}
+
+	if ent.Msg != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(ent.Msg)
+	}
+
+	buf.WriteByte('\n')
+	return nil
+}
+
+// severity returns the syslog severity of level.
+func severity(level lg.Level) int {
+	switch level {
+	case lg.LevelError:
+		return 3
+	case lg.LevelWarn:
+		return 4
+	default:
+		return 7
+	}
+}
+
+// writeSDName writes s, truncated to max bytes, replacing characters
+// that are not printable ASCII with '_'. If sd is true, the characters
+// '=', ']' and '"' are also replaced, as required for SD-NAME.
+func writeSDName(buf *bytes.Buffer, s string, max int, sd bool) {
+	if s == "" {
+		buf.WriteByte('-')
+		return
+	}
+
+	if len(s) > max {
+		s = s[:max]
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 33 || c > 126 || (sd && (c == '=' || c == ']' || c == '"')) {
+			c = '_'
+		}
+		buf.WriteByte(c)
+	}
+}
+
+// writeSDParam writes an SD-PARAM, escaping the value's
+// '"', '\' and ']' characters.
+func writeSDParam(buf *bytes.Buffer, key string, val any) {
+	buf.WriteByte(' ')
+	writeSDName(buf, key, 32, true)
+	buf.WriteString(`="`)
+
+	var s string
+	switch val := val.(type) {
+	case nil:
+	case string:
+		s = val
+	case error:
+		s = val.Error()
+	case time.Time:
+		s = val.Format(rfc3339Milli)
+	default:
+		s = fmt.Sprint(val)
+	}
+
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == '"' || c == '\\' || c == ']' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(s[i])
+	}
+	buf.WriteByte('"')
+}
@@ -0,0 +1,43 @@
+package lgcore_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+func TestRFC5424Encoder(t *testing.T) {
+	ent := lgcore.Entry{
+		Time:   time.Date(2022, 11, 10, 10, 0, 0, 123456789, time.UTC),
+		Level:  lg.LevelWarn,
+		Caller: lgcore.Caller{Defined: true, File: "/app/main.go", Line: 7},
+		Msg:    "hello world",
+		Fields: []lg.Field{
+			{Key: "user", Val: "alice"},
+			{Key: "bad key=]", Val: `a "b" \c]`},
+			{Key: "error", Val: errors.New("boom")},
+			{Key: "nil", Val: nil},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	enc := lgcore.RFC5424Encoder{Hostname: "host", AppName: "my app", ProcID: "42"}
+	require.NoError(t, enc.Encode(buf, ent))
+	require.Equal(t, `<12>1 2022-11-10T10:00:00.123456Z host my_app 42 - [lg@32473 caller="app/main.go:7" `+
+		`user="alice" bad_key__="a \"b\" \\c\]" error="boom" nil=""] hello world`+"\n", buf.String())
+
+	buf.Reset()
+	enc = lgcore.RFC5424Encoder{Facility: 16, SDID: "app@12345"}
+	require.NoError(t, enc.Encode(buf, lgcore.Entry{Level: lg.LevelError}))
+	require.Equal(t, "<131>1 - - - - - -\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, enc.Encode(buf, lgcore.Entry{Fields: []lg.Field{{Key: "k", Val: 1}}, Msg: "x"}))
+	require.Equal(t, `<135>1 - - - - - [app@12345 k="1"] x`+"\n", buf.String())
+}
@@ -0,0 +1,137 @@
+// Package lgcrash writes crash reports for post-mortem analysis. On
+// panic, a Reporter writes the panic value, the stack, and the most
+// recent log entries held by a Ring, to a dedicated crash file:
+//
+//	ring := lgcrash.NewRing(log, 0)
+//	rep := lgcrash.NewReporter("/var/log/app", lgcrash.WithRing(ring), lgcrash.WithLog(log))
+//	defer rep.Recover()
+//	run(ring) // Log via the ring.
+//
+// A Reporter can also be combined with the panic recovery of package
+// lghttp, via its WithOnPanic option:
+//
+//	lghttp.Middleware(log, lghttp.WithOnPanic(func(_ *http.Request, rec any, stack []byte) {
+//	  _, _ = rep.Report(rec, stack)
+//	}))
+package lgcrash
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Option is a functional option for NewReporter.
+type Option func(r *Reporter)
+
+// WithRing returns an Option that sets the Ring whose
+// entries are included in crash reports.
+func WithRing(ring *Ring) Option {
+	return func(r *Reporter) {
+		r.ring = ring
+	}
+}
+
+// WithLog returns an Option that sets the Log to which the path
+// of each crash report (or the failure to write it) is logged,
+// at ERROR level.
+func WithLog(log lg.Log) Option {
+	return func(r *Reporter) {
+		r.log = log
+	}
+}
+
+// WithRepanic returns an Option that sets whether Recover re-panics
+// with the recovered value, after writing the crash report. The
+// default is true, so that the process still crashes.
+func WithRepanic(repanic bool) Option {
+	return func(r *Reporter) {
+		r.repanic = repanic
+	}
+}
+
+// Reporter writes crash reports, named crash-<timestamp>.log,
+// to a directory.
+type Reporter struct {
+	dir     string
+	ring    *Ring
+	log     lg.Log
+	repanic bool
+}
+
+// NewReporter returns a Reporter that writes crash reports to dir.
+func NewReporter(dir string, opts ...Option) *Reporter {
+	r := &Reporter{dir: dir, repanic: true}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.log = lg.OrDiscard(r.log)
+	return r
+}
+
+// Recover recovers from a panic, and writes a crash report. It must
+// be invoked directly via defer:
+//
+//	defer rep.Recover()
+//
+// Unless disabled via WithRepanic, Recover then re-panics.
+func (r *Reporter) Recover() {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+
+	_, _ = r.Report(rec, debug.Stack())
+	if r.repanic {
+		panic(rec)
+	}
+}
+
+// Report writes a crash report for the panic value rec, with stack,
+// returning the path of the crash file.
+func (r *Reporter) Report(rec any, stack []byte) (path string, err error) {
+	now := time.Now().UTC()
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "panic: %v\n", rec)
+	fmt.Fprintf(buf, "time: %s\n", now.Format(time.RFC3339Nano))
+	fmt.Fprintf(buf, "pid: %d\n\n", os.Getpid())
+	buf.WriteString("stack:\n")
+	buf.Write(stack)
+
+	if r.ring != nil {
+		ents := r.ring.Entries()
+		fmt.Fprintf(buf, "\nrecent entries (%d, oldest first):\n", len(ents))
+		for _, ent := range ents {
+			writeEntry(buf, ent)
+		}
+	}
+
+	path = filepath.Join(r.dir, "crash-"+now.Format("20060102T150405.000000Z")+".log")
+	if err = os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		r.log.Errorf("lgcrash: failed to write crash report: %v", err)
+		return "", fmt.Errorf("lgcrash: write crash report: %w", err)
+	}
+
+	r.log.With("path", path).Errorf("lgcrash: panic: %v: crash report written", rec)
+	return path, nil
+}
+
+func writeEntry(buf *bytes.Buffer, ent RingEntry) {
+	buf.WriteString(ent.Time.UTC().Format("2006-01-02T15:04:05.000000Z"))
+	buf.WriteByte('\t')
+	buf.WriteString(strings.ToUpper(ent.Level.String()))
+	buf.WriteByte('\t')
+	buf.WriteString(ent.Msg)
+	for _, f := range ent.Fields {
+		fmt.Fprintf(buf, "\t%s=%v", f.Key, f.Val)
+	}
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,68 @@
+package lgcrash_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgcrash"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestReporter_Recover(t *testing.T) {
+	dir := t.TempDir()
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	ring := lgcrash.NewRing(log, 0)
+	rep := lgcrash.NewReporter(dir, lgcrash.WithRing(ring), lgcrash.WithLog(log), lgcrash.WithRepanic(false))
+
+	func() {
+		defer rep.Recover()
+		ring.With("user", "alice").Debug("loading profile")
+		ring.WarnIfError(errors.New("cache miss"))
+		var m map[string]int
+		m["boom"]++ // panics
+	}()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "crash-*.log"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+
+	b, err := os.ReadFile(matches[0])
+	require.NoError(t, err)
+	s := string(b)
+	require.Contains(t, s, "panic: assignment to entry in nil map\n")
+	require.Contains(t, s, "lgcrash_test.go")
+	require.Contains(t, s, "recent entries (2, oldest first):\n")
+	require.Contains(t, s, "\tDEBUG\tloading profile\tuser=alice\n")
+	require.Contains(t, s, "\tWARN\tcache miss\n")
+
+	require.Contains(t, buf.String(), "crash report written")
+	require.Contains(t, buf.String(), matches[0])
+}
+
+func TestReporter_Repanic(t *testing.T) {
+	rep := lgcrash.NewReporter(t.TempDir())
+	require.PanicsWithValue(t, "boom", func() {
+		defer rep.Recover()
+		panic("boom")
+	})
+}
+
+func TestRing(t *testing.T) {
+	ring := lgcrash.NewRing(nil, 3)
+	require.Empty(t, ring.Entries())
+
+	for _, msg := range []string{"a", "b", "c", "d", "e"} {
+		ring.Debug(msg)
+	}
+
+	ents := ring.Entries()
+	require.Len(t, ents, 3)
+	require.Equal(t, "c", ents[0].Msg)
+	require.Equal(t, "e", ents[2].Msg)
+}
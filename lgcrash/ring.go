@@ -0,0 +1,169 @@
+package lgcrash
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// DefaultRingSize is the default number of entries held by a Ring.
+const DefaultRingSize = 256
+
+// RingEntry is an entry held by a Ring.
+type RingEntry struct {
+	Time   time.Time
+	Level  lg.Level
+	Msg    string
+	Fields []lg.Field
+}
+
+// Ring is a Log that writes to an underlying Log, and also holds the
+// most recent entries (of any level, including DEBUG entries that
+// the underlying Log may not have written) in a ring buffer, so that
+// they can be included in a crash report. The child logs returned by
+// With share the ring buffer of their parent. Ring is safe for
+// concurrent use.
+type Ring struct {
+	log    lg.Log
+	fields []lg.Field
+	buf    *ringBuffer
+}
+
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries []RingEntry
+	next    int
+	full    bool
+}
+
+// NewRing returns a Ring that writes to log, and holds the most
+// recent size entries. If size is not positive, DefaultRingSize
+// is used.
+func NewRing(log lg.Log, size int) *Ring {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+
+	// The caller skip accounts for the frame of Ring's methods.
+	return &Ring{log: lg.AddCallerSkip(lg.OrDiscard(log), 1), buf: &ringBuffer{entries: make([]RingEntry, size)}}
+}
+
+// Entries returns the entries held by r, oldest first.
+func (r *Ring) Entries() []RingEntry {
+	b := r.buf
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]RingEntry(nil), b.entries[:b.next]...)
+	}
+
+	ents := make([]RingEntry, 0, len(b.entries))
+	ents = append(ents, b.entries[b.next:]...)
+	return append(ents, b.entries[:b.next]...)
+}
+
+func (r *Ring) record(level lg.Level, msg string) {
+	b := r.buf
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = RingEntry{Time: time.Now(), Level: level, Msg: msg, Fields: r.fields}
+	b.next++
+	if b.next == len(b.entries) {
+		b.next = 0
+		b.full = true
+	}
+}
+
+// Enabled reports whether level is enabled for the underlying log.
+func (r *Ring) Enabled(level lg.Level) bool {
+	return lg.Enabled(r.log, level)
+}
+
+// AddCallerSkip implements the optional interface used by lg.AddCallerSkip.
+func (r *Ring) AddCallerSkip(skip int) lg.Log {
+	return &Ring{log: lg.AddCallerSkip(r.log, skip), fields: r.fields, buf: r.buf}
+}
+
+// Debug implements lg.Log.Debug.
+func (r *Ring) Debug(a ...any) {
+	r.record(lg.LevelDebug, fmt.Sprint(a...))
+	r.log.Debug(a...)
+}
+
+// Debugf implements lg.Log.Debugf.
+func (r *Ring) Debugf(format string, a ...any) {
+	r.record(lg.LevelDebug, fmt.Sprintf(format, a...))
+	r.log.Debugf(format, a...)
+}
+
+// Warn implements lg.Log.Warn.
+func (r *Ring) Warn(a ...any) {
+	r.record(lg.LevelWarn, fmt.Sprint(a...))
+	r.log.Warn(a...)
+}
+
+// Warnf implements lg.Log.Warnf.
+func (r *Ring) Warnf(format string, a ...any) {
+	r.record(lg.LevelWarn, fmt.Sprintf(format, a...))
+	r.log.Warnf(format, a...)
+}
+
+// WarnIfError implements lg.Log.WarnIfError.
+func (r *Ring) WarnIfError(err error) {
+	if err == nil {
+		return
+	}
+
+	r.record(lg.LevelWarn, err.Error())
+	r.log.WarnIfError(err)
+}
+
+// WarnIfFuncError implements lg.Log.WarnIfFuncError.
+func (r *Ring) WarnIfFuncError(fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	if err := fn(); err != nil {
+		r.record(lg.LevelWarn, err.Error())
+		r.log.WarnIfError(err)
+	}
+}
+
+// WarnIfCloseError implements lg.Log.WarnIfCloseError.
+func (r *Ring) WarnIfCloseError(c io.Closer) {
+	if c == nil {
+		return
+	}
+
+	if err := c.Close(); err != nil {
+		r.record(lg.LevelWarn, err.Error())
+		r.log.WarnIfError(err)
+	}
+}
+
+// Error implements lg.Log.Error.
+func (r *Ring) Error(a ...any) {
+	r.record(lg.LevelError, fmt.Sprint(a...))
+	r.log.Error(a...)
+}
+
+// Errorf implements lg.Log.Errorf.
+func (r *Ring) Errorf(format string, a ...any) {
+	r.record(lg.LevelError, fmt.Sprintf(format, a...))
+	r.log.Errorf(format, a...)
+}
+
+// With implements lg.Log.With. The returned
+// log shares the ring buffer of r.
+func (r *Ring) With(key string, val any) lg.Log {
+	fields := make([]lg.Field, len(r.fields), len(r.fields)+1)
+	copy(fields, r.fields)
+	fields = append(fields, lg.Field{Key: key, Val: val})
+	return &Ring{log: r.log.With(key, val), fields: fields, buf: r.buf}
+}
@@ -0,0 +1,88 @@
+// Package lgcron adapts a lg.Log for use by robfig/cron and
+// similar scheduled-job frameworks.
+//
+//	c := cron.New(cron.WithLogger(lgcron.New(log)))
+//	c.AddFunc("@hourly", lgcron.Func(log, "cleanup", cleanup))
+//
+// The cron.Logger interface is satisfied structurally, so this
+// package does not depend on robfig/cron.
+package lgcron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Logger implements robfig/cron's cron.Logger interface. Info
+// messages, which report routine scheduler activity, are logged
+// at DEBUG level; Error messages at ERROR level.
+type Logger struct {
+	log lg.Log
+}
+
+// New returns a Logger that logs to log.
+func New(log lg.Log) *Logger {
+	return &Logger{log: lg.AddCallerSkip(lg.OrDiscard(log), 1)}
+}
+
+// Info implements cron.Logger.
+func (l *Logger) Info(msg string, keysAndValues ...any) {
+	lg.WithKeyVals(l.log, keysAndValues...).Debug(msg)
+}
+
+// Error implements cron.Logger.
+func (l *Logger) Error(err error, msg string, keysAndValues ...any) {
+	log := lg.WithKeyVals(l.log, keysAndValues...)
+	if err != nil {
+		log = lg.WithError(log, err)
+	}
+
+	log.Error(msg)
+}
+
+// Func returns a func, suitable for cron.AddFunc, that runs the job
+// fn with a log that has the job name as field "job". The job's
+// completion is logged with field "job.duration": at DEBUG level
+// if fn succeeded, or at ERROR level if fn returned an error,
+// panicked, or invoked runtime.Goexit. A panic is logged and then
+// re-panicked.
+func Func(log lg.Log, name string, fn func(log lg.Log) error) func() {
+	log = lg.OrDiscard(log).With("job", name)
+
+	return func() {
+		start := time.Now()
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+
+			r := recover()
+			if r == nil {
+				// fn invoked runtime.Goexit, which must not be
+				// turned into a panic.
+				log.With("job.duration", time.Since(start)).
+					Errorf("job %s exited without completing", name)
+				return
+			}
+
+			log.With("job.duration", time.Since(start)).
+				With("panic", fmt.Sprint(r)).
+				Errorf("job %s panicked", name)
+			panic(r)
+		}()
+
+		err := fn(log)
+		completed = true
+
+		jobLog := log.With("job.duration", time.Since(start))
+		if err != nil {
+			lg.WithError(jobLog, err).Errorf("job %s failed", name)
+			return
+		}
+
+		jobLog.Debugf("job %s completed", name)
+	}
+}
@@ -0,0 +1,91 @@
+package lgcron_test
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcron"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+// cronLogger is robfig/cron's cron.Logger interface.
+type cronLogger interface {
+	Info(msg string, keysAndValues ...any)
+	Error(err error, msg string, keysAndValues ...any)
+}
+
+var _ cronLogger = (*lgcron.Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := lgcron.New(testlg.NewJSON(buf))
+
+	l.Info("run", "entry", 1, "next", "2022-11-10T10:00:00Z")
+	l.Error(errors.New("boom"), "panic", "entry", 2)
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "run", ms[0]["message"])
+	require.Equal(t, float64(1), ms[0]["entry"])
+	require.Contains(t, ms[0]["caller"], "lgcron_test.go")
+
+	require.Equal(t, "error", ms[1]["level"])
+	require.Equal(t, "boom", ms[1]["error"])
+	require.Equal(t, float64(2), ms[1]["entry"])
+}
+
+func TestFunc(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := testlg.NewJSON(buf)
+
+	lgcron.Func(log, "ok", func(log lg.Log) error {
+		log.Debug("working")
+		return nil
+	})()
+	lgcron.Func(log, "fails", func(log lg.Log) error { return errors.New("boom") })()
+	require.Panics(t, lgcron.Func(log, "panics", func(log lg.Log) error { panic("oh no") }))
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 4)
+
+	require.Equal(t, "working", ms[0]["message"])
+	require.Equal(t, "ok", ms[0]["job"])
+	require.Equal(t, "debug", ms[1]["level"])
+	require.Equal(t, "job ok completed", ms[1]["message"])
+	require.Contains(t, ms[1], "job.duration")
+
+	require.Equal(t, "error", ms[2]["level"])
+	require.Equal(t, "fails", ms[2]["job"])
+	require.Equal(t, "boom", ms[2]["error"])
+
+	require.Equal(t, "error", ms[3]["level"])
+	require.Equal(t, "panics", ms[3]["job"])
+	require.Equal(t, "oh no", ms[3]["panic"])
+}
+
+func TestFunc_Goexit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	job := lgcron.Func(testlg.NewJSON(buf), "exits", func(log lg.Log) error {
+		runtime.Goexit()
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		job()
+	}()
+	<-done
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "error", ms[0]["level"])
+	require.Equal(t, "job exits exited without completing", ms[0]["message"])
+	require.NotContains(t, ms[0], "panic")
+}
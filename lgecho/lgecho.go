@@ -0,0 +1,233 @@
+// Package lgecho adapts a lg.Log for use as the Echo web
+// framework's logger.
+//
+//	e := echo.New()
+//	e.Logger = lgecho.New(log)
+//
+// For request logging, use lghttp.Middleware via echo.WrapMiddleware:
+//
+//	e.Use(echo.WrapMiddleware(lghttp.Middleware(log)))
+package lgecho
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+var _ echo.Logger = (*Logger)(nil)
+
+// Logger implements echo.Logger. lg has no INFO level: Print and
+// Info messages are logged at DEBUG level. Fatal and Panic messages
+// are logged at ERROR level, before calling os.Exit(1) or panic.
+//
+// The output of a Logger is determined by its lg.Log: SetOutput
+// and SetHeader are no-ops, and the prefix is not added to entries.
+type Logger struct {
+	log lg.Log
+
+	// level is the log.Lvl set via SetLevel, or zero.
+	level atomic.Uint32
+
+	mu     sync.Mutex
+	prefix string
+}
+
+// New returns a Logger that logs to log.
+func New(log lg.Log) *Logger {
+	return &Logger{log: lg.AddCallerSkip(lg.OrDiscard(log), 2)}
+}
+
+// Output returns a writer that logs each write at WARN level.
+// Echo uses Output for the http.Server's ErrorLog.
+func (l *Logger) Output() io.Writer {
+	return outputWriter{l: l}
+}
+
+// SetOutput is a no-op.
+func (l *Logger) SetOutput(io.Writer) {}
+
+// Prefix returns the prefix set via SetPrefix.
+func (l *Logger) Prefix() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.prefix
+}
+
+// SetPrefix sets the value returned by Prefix.
+func (l *Logger) SetPrefix(p string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prefix = p
+}
+
+// Level returns the level set via SetLevel. If no level has been
+// set, the level is derived from the lg.Log (see lg.Enabled).
+func (l *Logger) Level() log.Lvl {
+	if v := log.Lvl(l.level.Load()); v != 0 {
+		return v
+	}
+
+	switch {
+	case lg.Enabled(l.log, lg.LevelDebug):
+		return log.DEBUG
+	case lg.Enabled(l.log, lg.LevelWarn):
+		return log.WARN
+	case lg.Enabled(l.log, lg.LevelError):
+		return log.ERROR
+	default:
+		return log.OFF
+	}
+}
+
+// SetLevel sets the minimum level of messages that are logged.
+// Note that the lg.Log may itself filter messages by level.
+func (l *Logger) SetLevel(v log.Lvl) {
+	l.level.Store(uint32(v))
+}
+
+// SetHeader is a no-op.
+func (l *Logger) SetHeader(string) {}
+
+// Print implements echo.Logger.
+func (l *Logger) Print(i ...any) { l.print(log.DEBUG, fmt.Sprint(i...), nil) }
+
+// Printf implements echo.Logger.
+func (l *Logger) Printf(format string, args ...any) {
+	l.print(log.DEBUG, fmt.Sprintf(format, args...), nil)
+}
+
+// Printj implements echo.Logger.
+func (l *Logger) Printj(j log.JSON) { l.print(log.DEBUG, "", j) }
+
+// Debug implements echo.Logger.
+func (l *Logger) Debug(i ...any) { l.print(log.DEBUG, fmt.Sprint(i...), nil) }
+
+// Debugf implements echo.Logger.
+func (l *Logger) Debugf(format string, args ...any) {
+	l.print(log.DEBUG, fmt.Sprintf(format, args...), nil)
+}
+
+// Debugj implements echo.Logger.
+func (l *Logger) Debugj(j log.JSON) { l.print(log.DEBUG, "", j) }
+
+// Info implements echo.Logger.
+func (l *Logger) Info(i ...any) { l.print(log.INFO, fmt.Sprint(i...), nil) }
+
+// Infof implements echo.Logger.
+func (l *Logger) Infof(format string, args ...any) {
+	l.print(log.INFO, fmt.Sprintf(format, args...), nil)
+}
+
+// Infoj implements echo.Logger.
+func (l *Logger) Infoj(j log.JSON) { l.print(log.INFO, "", j) }
+
+// Warn implements echo.Logger.
+func (l *Logger) Warn(i ...any) { l.print(log.WARN, fmt.Sprint(i...), nil) }
+
+// Warnf implements echo.Logger.
+func (l *Logger) Warnf(format string, args ...any) {
+	l.print(log.WARN, fmt.Sprintf(format, args...), nil)
+}
+
+// Warnj implements echo.Logger.
+func (l *Logger) Warnj(j log.JSON) { l.print(log.WARN, "", j) }
+
+// Error implements echo.Logger.
+func (l *Logger) Error(i ...any) { l.print(log.ERROR, fmt.Sprint(i...), nil) }
+
+// Errorf implements echo.Logger.
+func (l *Logger) Errorf(format string, args ...any) {
+	l.print(log.ERROR, fmt.Sprintf(format, args...), nil)
+}
+
+// Errorj implements echo.Logger.
+func (l *Logger) Errorj(j log.JSON) { l.print(log.ERROR, "", j) }
+
+// Fatal implements echo.Logger.
+func (l *Logger) Fatal(i ...any) {
+	l.print(log.ERROR, fmt.Sprint(i...), nil)
+	os.Exit(1)
+}
+
+// Fatalf implements echo.Logger.
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.print(log.ERROR, fmt.Sprintf(format, args...), nil)
+	os.Exit(1)
+}
+
+// Fatalj implements echo.Logger.
+func (l *Logger) Fatalj(j log.JSON) {
+	l.print(log.ERROR, "", j)
+	os.Exit(1)
+}
+
+// Panic implements echo.Logger.
+func (l *Logger) Panic(i ...any) {
+	msg := fmt.Sprint(i...)
+	l.print(log.ERROR, msg, nil)
+	panic(msg)
+}
+
+// Panicf implements echo.Logger.
+func (l *Logger) Panicf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	l.print(log.ERROR, msg, nil)
+	panic(msg)
+}
+
+// Panicj implements echo.Logger.
+func (l *Logger) Panicj(j log.JSON) {
+	l.print(log.ERROR, "", j)
+	panic(j)
+}
+
+// print logs msg, with fields j, if lvl is enabled. It must be
+// invoked directly by the exported methods, for the caller skip
+// to be correct.
+func (l *Logger) print(lvl log.Lvl, msg string, j log.JSON) {
+	if min := log.Lvl(l.level.Load()); min != 0 && lvl < min {
+		return
+	}
+
+	lgLog := l.log
+	if len(j) > 0 {
+		keys := make([]string, 0, len(j))
+		for k := range j {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			lgLog = lgLog.With(k, j[k])
+		}
+	}
+
+	switch lvl { //nolint:exhaustive // remaining levels logged at DEBUG
+	case log.ERROR:
+		lgLog.Error(msg)
+	case log.WARN:
+		lgLog.Warn(msg)
+	default:
+		lgLog.Debug(msg)
+	}
+}
+
+// outputWriter is the io.Writer returned by Logger.Output.
+type outputWriter struct {
+	l *Logger
+}
+
+func (w outputWriter) Write(p []byte) (int, error) {
+	w.l.print(log.WARN, strings.TrimSuffix(string(p), "\n"), nil)
+	return len(p), nil
+}
@@ -0,0 +1,75 @@
+package lgecho_test
+
+import (
+	"bytes"
+	stdlog "log"
+	"testing"
+
+	"github.com/labstack/gommon/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgecho"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := lgecho.New(testlg.NewJSON(buf))
+
+	l.Print("print msg")
+	l.Infof("⇨ http server started on %s", "[::]:1323")
+	l.Warnj(log.JSON{"b": 2, "a": "x"})
+	l.Error("error msg")
+	require.Panics(t, func() { l.Panicf("panic %d", 1) })
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 5)
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "print msg", ms[0]["message"])
+	require.Contains(t, ms[0]["caller"], "lgecho_test.go")
+	require.Equal(t, "debug", ms[1]["level"])
+	require.Equal(t, "⇨ http server started on [::]:1323", ms[1]["message"])
+	require.Equal(t, "warn", ms[2]["level"])
+	require.Equal(t, "x", ms[2]["a"])
+	require.Equal(t, float64(2), ms[2]["b"])
+	require.Equal(t, "error", ms[3]["level"])
+	require.Equal(t, "error", ms[4]["level"])
+	require.Equal(t, "panic 1", ms[4]["message"])
+}
+
+func TestLogger_Level(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := lgecho.New(testlg.NewJSON(buf))
+	require.Equal(t, log.DEBUG, l.Level())
+
+	l.SetLevel(log.WARN)
+	require.Equal(t, log.WARN, l.Level())
+	l.Debug("dropped")
+	l.Info("dropped")
+	l.Warn("kept")
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "kept", ms[0]["message"])
+
+	warnLog := testlg.NewJSON(buf, zaplg.WithLevel(lg.LevelWarn))
+	require.Equal(t, log.WARN, lgecho.New(warnLog).Level())
+	require.Equal(t, log.OFF, lgecho.New(nil).Level())
+}
+
+func TestLogger_Output(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := lgecho.New(testlg.NewJSON(buf))
+	l.SetPrefix("echo")
+	require.Equal(t, "echo", l.Prefix())
+
+	// Echo uses Output for the http.Server's ErrorLog.
+	errLog := stdlog.New(l.Output(), l.Prefix()+": ", 0)
+	errLog.Printf("http: TLS handshake error from %s", "127.0.0.1:1234")
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "warn", ms[0]["level"])
+	require.Equal(t, "echo: http: TLS handshake error from 127.0.0.1:1234", ms[0]["message"])
+}
@@ -0,0 +1,87 @@
+// Package lggin provides Gin middleware that logs each
+// request to a lg.Log.
+//
+//	r := gin.New()
+//	r.Use(gin.Recovery(), lggin.Middleware(log))
+//
+// Each request is logged with the fields returned by lg.ContextFields,
+// lg.HTTPRequestFields and lg.HTTPResponseFields, and the matched
+// route as field "http.route". Errors attached to the request via
+// gin.Context.Error are logged as field "error". The middleware
+// adds the request log to the request's context, which the handler
+// can retrieve via lg.Ctx(c.Request.Context()).
+package lggin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lghttp"
+)
+
+// Option is a functional option for Middleware.
+type Option func(o *options)
+
+type options struct {
+	levelFn func(status int) lg.Level
+}
+
+// WithLevelFunc returns an Option that sets the func that determines
+// the level at which a request is logged, based on the response
+// status. The default is lghttp.DefaultLevel.
+func WithLevelFunc(fn func(status int) lg.Level) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.levelFn = fn
+		}
+	}
+}
+
+// Middleware returns a gin.HandlerFunc that logs each request to log.
+func Middleware(log lg.Log, opts ...Option) gin.HandlerFunc {
+	o := options{levelFn: lghttp.DefaultLevel}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	log = lg.OrDiscard(log)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		r := c.Request
+
+		reqLog := lg.WithFields(lg.WithContext(r.Context(), log), lg.HTTPRequestFields(r)...)
+		c.Request = r.WithContext(lg.NewContext(r.Context(), reqLog))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := o.levelFn(status)
+		if !lg.Enabled(reqLog, level) {
+			return
+		}
+
+		l := reqLog
+		if route := c.FullPath(); route != "" {
+			l = l.With("http.route", route)
+		}
+		l = lg.WithFields(l, lg.HTTPResponseFields(status, c.Writer.Size(), time.Since(start))...)
+		if len(c.Errors) > 0 {
+			l = l.With("error", strings.Join(c.Errors.Errors(), "; "))
+		}
+
+		msg := fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, status)
+		switch level {
+		case lg.LevelError:
+			l.Error(msg)
+		case lg.LevelWarn:
+			l.Warn(msg)
+		default:
+			l.Debug(msg)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package lggin_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lggin"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+func TestMiddleware(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := gin.New()
+	r.Use(lggin.Middleware(testlg.NewJSON(buf)))
+	r.GET("/users/:id", func(c *gin.Context) {
+		lg.Ctx(c.Request.Context()).Debug("in handler")
+		c.String(http.StatusOK, "hello")
+	})
+	r.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(errors.New("db down"))
+		c.String(http.StatusInternalServerError, "oops")
+	})
+
+	for _, path := range []string{"/users/42", "/fail", "/nope"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 4)
+
+	require.Equal(t, "in handler", ms[0]["message"])
+	require.Equal(t, "/users/42", ms[0]["http.path"])
+
+	require.Equal(t, "debug", ms[1]["level"])
+	require.Equal(t, "GET /users/42 200", ms[1]["message"])
+	require.Equal(t, "/users/:id", ms[1]["http.route"])
+	require.Equal(t, float64(200), ms[1]["http.status"])
+	require.Equal(t, float64(5), ms[1]["http.size"])
+
+	require.Equal(t, "error", ms[2]["level"])
+	require.Equal(t, "db down", ms[2]["error"])
+
+	require.Equal(t, "debug", ms[3]["level"])
+	require.Equal(t, float64(404), ms[3]["http.status"])
+	require.NotContains(t, ms[3], "http.route")
+}
+
+func TestMiddleware_LevelFunc(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := testlg.NewJSON(buf)
+
+	r := gin.New()
+	r.Use(lggin.Middleware(log, lggin.WithLevelFunc(func(status int) lg.Level { return lg.LevelWarn })))
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, "") })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "warn", ms[0]["level"])
+}
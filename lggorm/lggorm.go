@@ -0,0 +1,150 @@
+// Package lggorm adapts a lg.Log for use as a GORM logger.
+//
+//	db, err := gorm.Open(dialector, &gorm.Config{
+//	  Logger: lggorm.New(log),
+//	})
+//
+// GORM's levels are mapped to lg levels as follows: Info is logged
+// at DEBUG level (lg has no INFO level), Warn at WARN, and Error
+// at ERROR. Traced SQL statements are logged at DEBUG level; slow
+// or failed statements are logged at WARN level.
+package lggorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm/logger"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+var _ logger.Interface = (*Logger)(nil)
+
+// Option is a functional option for New.
+type Option func(l *Logger)
+
+// WithLogLevel returns an Option that sets the GORM log level.
+// The default is logger.Warn. Note that SQL statements are only
+// traced at logger.Info level; at lower levels, only slow and
+// failed statements are logged.
+func WithLogLevel(level logger.LogLevel) Option {
+	return func(l *Logger) {
+		l.level = level
+	}
+}
+
+// WithSlowThreshold returns an Option that sets the duration above
+// which a statement is logged as slow. The default is 200ms.
+// A zero value disables slow statement logging.
+func WithSlowThreshold(d time.Duration) Option {
+	return func(l *Logger) {
+		l.slowThreshold = d
+	}
+}
+
+// WithIgnoreRecordNotFound returns an Option that sets whether
+// logger.ErrRecordNotFound is logged as an error. The default is
+// true (the error is not logged), because a record not being found
+// is typically an expected outcome.
+func WithIgnoreRecordNotFound(ignore bool) Option {
+	return func(l *Logger) {
+		l.ignoreNotFound = ignore
+	}
+}
+
+// Logger implements GORM's logger.Interface.
+type Logger struct {
+	log            lg.Log
+	level          logger.LogLevel
+	slowThreshold  time.Duration
+	ignoreNotFound bool
+}
+
+// New returns a new Logger that logs to log.
+func New(log lg.Log, opts ...Option) *Logger {
+	l := &Logger{
+		log:            lg.AddCallerSkip(lg.OrDiscard(log), 1),
+		level:          logger.Warn,
+		slowThreshold:  200 * time.Millisecond,
+		ignoreNotFound: true,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// LogMode implements logger.Interface.
+func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
+	l2 := *l
+	l2.level = level
+	return &l2
+}
+
+// Info implements logger.Interface, logging at DEBUG level.
+func (l *Logger) Info(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Info {
+		l.ctxLog(ctx).Debugf(msg, data...)
+	}
+}
+
+// Warn implements logger.Interface.
+func (l *Logger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Warn {
+		l.ctxLog(ctx).Warnf(msg, data...)
+	}
+}
+
+// Error implements logger.Interface.
+func (l *Logger) Error(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Error {
+		l.ctxLog(ctx).Errorf(msg, data...)
+	}
+}
+
+// Trace implements logger.Interface.
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	failed := err != nil && !(l.ignoreNotFound && errors.Is(err, logger.ErrRecordNotFound))
+	slow := l.slowThreshold != 0 && elapsed > l.slowThreshold
+
+	switch {
+	case failed && l.level >= logger.Error:
+		log := lg.WithError(l.traceLog(ctx, fc, elapsed), err)
+		log.Warn("sql failed")
+	case slow && l.level >= logger.Warn:
+		log := l.traceLog(ctx, fc, elapsed).With("sql.slow_threshold", l.slowThreshold)
+		log.Warn("sql slow")
+	case l.level >= logger.Info:
+		l.traceLog(ctx, fc, elapsed).Debug("sql")
+	}
+}
+
+// traceLog returns a child log with the fields for a traced statement.
+func (l *Logger) traceLog(ctx context.Context, fc func() (string, int64), elapsed time.Duration) lg.Log {
+	sql, rows := fc()
+	log := l.ctxLog(ctx).With("sql.query", sql)
+	if rows >= 0 {
+		// GORM reports -1 when rows affected isn't applicable.
+		log = log.With("sql.rows", rows)
+	}
+
+	return log.With("sql.duration", elapsed)
+}
+
+// ctxLog returns the Log carried by ctx, if any, else l.log.
+func (l *Logger) ctxLog(ctx context.Context) lg.Log {
+	if log := lg.FromContext(ctx); log != nil {
+		return lg.AddCallerSkip(log, 1)
+	}
+
+	return l.log
+}
@@ -0,0 +1,103 @@
+package lggorm_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lggorm"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+func TestLogger_Levels(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name       string
+		level      logger.LogLevel
+		wantLevels []any
+	}{
+		{name: "silent", level: logger.Silent, wantLevels: nil},
+		{name: "error", level: logger.Error, wantLevels: []any{"error"}},
+		{name: "warn", level: logger.Warn, wantLevels: []any{"warn", "error"}},
+		{name: "info", level: logger.Info, wantLevels: []any{"debug", "warn", "error"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			var l logger.Interface = lggorm.New(testlg.NewJSON(buf))
+			l = l.LogMode(tc.level)
+
+			l.Info(ctx, "info %d", 1)
+			l.Warn(ctx, "warn %d", 2)
+			l.Error(ctx, "error %d", 3)
+
+			var got []any
+			for _, m := range testlg.DecodeJSON(t, buf) {
+				got = append(got, m["level"])
+			}
+			require.Equal(t, tc.wantLevels, got)
+		})
+	}
+}
+
+func TestLogger_Trace(t *testing.T) {
+	ctx := context.Background()
+	fc := func() (string, int64) { return "SELECT * FROM users", 3 }
+
+	buf := &bytes.Buffer{}
+	l := lggorm.New(testlg.NewJSON(buf), lggorm.WithLogLevel(logger.Info), lggorm.WithSlowThreshold(time.Minute))
+
+	l.Trace(ctx, time.Now(), fc, nil)
+	l.Trace(ctx, time.Now().Add(-time.Hour), fc, nil)
+	l.Trace(ctx, time.Now(), fc, errors.New("syntax error"))
+	l.Trace(ctx, time.Now(), fc, logger.ErrRecordNotFound)
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 4)
+
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "SELECT * FROM users", ms[0]["sql.query"])
+	require.Equal(t, float64(3), ms[0]["sql.rows"])
+	require.Contains(t, ms[0], "sql.duration")
+
+	require.Equal(t, "warn", ms[1]["level"])
+	require.Equal(t, "sql slow", ms[1]["message"])
+
+	require.Equal(t, "warn", ms[2]["level"])
+	require.Equal(t, "syntax error", ms[2]["error"])
+
+	// ErrRecordNotFound is ignored by default.
+	require.Equal(t, "debug", ms[3]["level"])
+
+	buf.Reset()
+	l = lggorm.New(testlg.NewJSON(buf), lggorm.WithIgnoreRecordNotFound(false))
+	l.Trace(ctx, time.Now(), fc, nil) // not logged at default Warn level
+	l.Trace(ctx, time.Now(), fc, logger.ErrRecordNotFound)
+	ms = testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "record not found", ms[0]["error"])
+}
+
+func TestLogger_ContextLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctxBuf := &bytes.Buffer{}
+
+	ctx := lg.NewContext(context.Background(), testlg.NewJSON(ctxBuf).With("request_id", "abc"))
+	l := lggorm.New(testlg.NewJSON(buf))
+	l.Warn(ctx, "hello")
+
+	require.Zero(t, buf.Len())
+	ms := testlg.DecodeJSON(t, ctxBuf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "abc", ms[0]["request_id"])
+}
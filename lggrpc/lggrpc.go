@@ -0,0 +1,200 @@
+// Package lggrpc provides gRPC interceptors that log each
+// RPC to a lg.Log.
+//
+//	srv := grpc.NewServer(
+//	  grpc.UnaryInterceptor(lggrpc.UnaryServerInterceptor(log)),
+//	  grpc.StreamInterceptor(lggrpc.StreamServerInterceptor(log)),
+//	)
+//
+// If the incoming context carries a Log (see lg.NewContext), that
+// Log is used in preference to the interceptor's Log. The server
+// interceptors add a request-scoped Log (with field "grpc.method",
+// and any fields from WithFieldsFunc) to the handler's context,
+// which the handler can retrieve via lg.Ctx.
+package lggrpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Option is a functional option for the interceptors.
+type Option func(o *options)
+
+type options struct {
+	levelFn  func(code codes.Code) lg.Level
+	fieldsFn func(ctx context.Context, fullMethod string) []lg.Field
+}
+
+// WithLevelFunc returns an Option that sets the func that determines
+// the level at which an RPC is logged, based on the RPC's status
+// code. The default is DefaultServerLevel for server interceptors,
+// and DefaultClientLevel for client interceptors.
+func WithLevelFunc(fn func(code codes.Code) lg.Level) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.levelFn = fn
+		}
+	}
+}
+
+// WithFieldsFunc returns an Option that sets a func that returns
+// additional fields for each RPC, e.g. from the incoming metadata.
+func WithFieldsFunc(fn func(ctx context.Context, fullMethod string) []lg.Field) Option {
+	return func(o *options) {
+		o.fieldsFn = fn
+	}
+}
+
+// DefaultServerLevel logs codes that indicate a server fault (such
+// as Internal or Unavailable) at ERROR level, and all others
+// at DEBUG level.
+func DefaultServerLevel(code codes.Code) lg.Level {
+	switch code { //nolint:exhaustive // default case handles the rest
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return lg.LevelError
+	default:
+		return lg.LevelDebug
+	}
+}
+
+// DefaultClientLevel logs all codes other than OK at WARN
+// level, and OK at DEBUG level. A failed outbound RPC is not
+// necessarily a failed business operation, so the caller can
+// decide whether to log that failure at ERROR level.
+func DefaultClientLevel(code codes.Code) lg.Level {
+	if code == codes.OK {
+		return lg.LevelDebug
+	}
+
+	return lg.LevelWarn
+}
+
+func newOptions(dfltLevelFn func(codes.Code) lg.Level, opts []Option) *options {
+	o := &options{levelFn: dfltLevelFn}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// requestLog returns the Log for the RPC: the Log carried by ctx
+// if any, otherwise log; with the RPC's fields, and the fields
+// returned by lg.ContextFields, added.
+func (o *options) requestLog(ctx context.Context, log lg.Log, fullMethod string) lg.Log {
+	if ctxLog := lg.FromContext(ctx); ctxLog != nil {
+		log = ctxLog
+	}
+
+	log = lg.WithContext(ctx, lg.OrDiscard(log)).With("grpc.method", fullMethod)
+	if o.fieldsFn != nil {
+		log = lg.WithFields(log, o.fieldsFn(ctx, fullMethod)...)
+	}
+
+	return log
+}
+
+// logRPC logs the completion of the RPC.
+func (o *options) logRPC(log lg.Log, fullMethod string, err error, elapsed time.Duration) {
+	code := status.Code(err)
+	level := o.levelFn(code)
+	if !lg.Enabled(log, level) {
+		return
+	}
+
+	log = log.With("grpc.code", code.String()).With("grpc.duration", elapsed)
+	if err != nil {
+		log = lg.WithError(log, err)
+	}
+
+	switch level {
+	case lg.LevelError:
+		log.Errorf("%s %s", fullMethod, code)
+	case lg.LevelWarn:
+		log.Warnf("%s %s", fullMethod, code)
+	default:
+		log.Debugf("%s %s", fullMethod, code)
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor that logs
+// each unary RPC.
+func UnaryServerInterceptor(log lg.Log, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(DefaultServerLevel, opts)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+		reqLog := o.requestLog(ctx, log, info.FullMethod)
+
+		resp, err := handler(lg.NewContext(ctx, reqLog), req)
+		o.logRPC(reqLog, info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that logs
+// each streaming RPC when the stream completes.
+func StreamServerInterceptor(log lg.Log, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(DefaultServerLevel, opts)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		reqLog := o.requestLog(ss.Context(), log, info.FullMethod)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: lg.NewContext(ss.Context(), reqLog)})
+		o.logRPC(reqLog, info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+// serverStream overrides the context of the wrapped grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context //nolint:containedctx // required to override Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryClientInterceptor returns an interceptor that logs
+// each outbound unary RPC.
+func UnaryClientInterceptor(log lg.Log, opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(DefaultClientLevel, opts)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		o.logRPC(o.requestLog(ctx, log, method), method, err, time.Since(start))
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor that logs the
+// establishment of each outbound stream. Note that errors that
+// occur later in the stream's lifetime are not logged.
+func StreamClientInterceptor(log lg.Log, opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(DefaultClientLevel, opts)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, callOpts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		o.logRPC(o.requestLog(ctx, log, method), method, err, time.Since(start))
+		return cs, err
+	}
+}
@@ -0,0 +1,141 @@
+package lggrpc_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lggrpc"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+const method = "/pkg.Service/Method"
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       error
+		wantCode  string
+		wantLevel string
+	}{
+		{name: "ok", wantCode: "OK", wantLevel: "debug"},
+		{name: "not_found", err: status.Error(codes.NotFound, "nope"), wantCode: "NotFound", wantLevel: "debug"},
+		{name: "internal", err: status.Error(codes.Internal, "bad"), wantCode: "Internal", wantLevel: "error"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			icpt := lggrpc.UnaryServerInterceptor(testlg.NewJSON(buf))
+
+			handler := func(ctx context.Context, req any) (any, error) {
+				// The handler should get the request-scoped log.
+				lg.Ctx(ctx).Debug("in handler")
+				return "resp", tc.err
+			}
+
+			resp, err := icpt(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: method}, handler)
+			require.Equal(t, "resp", resp)
+			require.Equal(t, tc.err, err)
+
+			ms := testlg.DecodeJSON(t, buf)
+			require.Len(t, ms, 2)
+			require.Equal(t, "in handler", ms[0]["message"])
+			require.Equal(t, method, ms[0]["grpc.method"])
+
+			require.Equal(t, tc.wantLevel, ms[1]["level"])
+			require.Equal(t, method, ms[1]["grpc.method"])
+			require.Equal(t, tc.wantCode, ms[1]["grpc.code"])
+			require.Contains(t, ms[1], "grpc.duration")
+			if tc.err != nil {
+				require.Equal(t, tc.err.Error(), ms[1]["error"])
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_ContextLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctxBuf := &bytes.Buffer{}
+
+	icpt := lggrpc.UnaryServerInterceptor(testlg.NewJSON(buf),
+		lggrpc.WithFieldsFunc(func(ctx context.Context, fullMethod string) []lg.Field {
+			return []lg.Field{{Key: "tenant", Val: "acme"}}
+		}),
+		lggrpc.WithLevelFunc(func(code codes.Code) lg.Level { return lg.LevelWarn }),
+	)
+
+	ctx := lg.NewContext(context.Background(), testlg.NewJSON(ctxBuf).With("request_id", "abc"))
+	_, err := icpt(ctx, "req", &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req any) (any, error) { return nil, nil })
+	require.NoError(t, err)
+
+	require.Zero(t, buf.Len(), "log carried by ctx should be preferred")
+	ms := testlg.DecodeJSON(t, ctxBuf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "warn", ms[0]["level"])
+	require.Equal(t, "abc", ms[0]["request_id"])
+	require.Equal(t, "acme", ms[0]["tenant"])
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context //nolint:containedctx // test fake
+}
+
+func (s fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	icpt := lggrpc.StreamServerInterceptor(testlg.NewJSON(buf))
+
+	ss := fakeServerStream{ctx: context.Background()}
+	err := icpt(nil, ss, &grpc.StreamServerInfo{FullMethod: method}, func(srv any, stream grpc.ServerStream) error {
+		lg.Ctx(stream.Context()).Debug("in handler")
+		return status.Error(codes.Unavailable, "down")
+	})
+	require.Error(t, err)
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	require.Equal(t, method, ms[0]["grpc.method"])
+	require.Equal(t, "error", ms[1]["level"])
+	require.Equal(t, "Unavailable", ms[1]["grpc.code"])
+}
+
+func TestClientInterceptors(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	unary := lggrpc.UnaryClientInterceptor(testlg.NewJSON(buf))
+	err := unary(context.Background(), method, "req", nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return status.Error(codes.NotFound, "nope")
+		})
+	require.Error(t, err)
+
+	stream := lggrpc.StreamClientInterceptor(testlg.NewJSON(buf))
+	_, err = stream(context.Background(), &grpc.StreamDesc{}, nil, method,
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+			opts ...grpc.CallOption,
+		) (grpc.ClientStream, error) {
+			return nil, nil
+		})
+	require.NoError(t, err)
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	require.Equal(t, "warn", ms[0]["level"])
+	require.Equal(t, "NotFound", ms[0]["grpc.code"])
+	require.Equal(t, "debug", ms[1]["level"])
+	require.Equal(t, "OK", ms[1]["grpc.code"])
+}
@@ -0,0 +1,144 @@
+package lghttp
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccessLog holds the details of a request, for use
+// by an AccessLogFormat.
+type AccessLog struct {
+	Request *http.Request
+	Status  int
+	Size    int
+	Start   time.Time
+	Latency time.Duration
+}
+
+// AccessLogFormat returns the access log line for a, without
+// the trailing newline.
+type AccessLogFormat func(a AccessLog) string
+
+// WithAccessLog returns an Option that writes an access log line for
+// each request to w, formatted by format, such as CommonLogFormat or
+// CombinedLogFormat. Requests are still logged to the Log passed to
+// Middleware; to produce only the access log, pass lg.Discard.
+func WithAccessLog(w io.Writer, format AccessLogFormat) Option {
+	return func(o *options) {
+		if w != nil && format != nil {
+			o.accessLog = &accessLogger{w: w, format: format}
+		}
+	}
+}
+
+// accessLogger writes access log lines.
+type accessLogger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format AccessLogFormat
+}
+
+func (l *accessLogger) write(a AccessLog) {
+	line := l.format(a) + "\n"
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.w, line)
+}
+
+// CommonLogFormat formats a in the Apache httpd Common Log Format:
+//
+//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326
+func CommonLogFormat(a AccessLog) string {
+	var sb strings.Builder
+	writeCommon(&sb, a)
+	return sb.String()
+}
+
+// CombinedLogFormat formats a in the Apache httpd Combined Log Format,
+// which is the Common Log Format followed by the Referer and User-Agent
+// request headers:
+//
+//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326 "http://example.com/" "Mozilla/5.0"
+func CombinedLogFormat(a AccessLog) string {
+	var sb strings.Builder
+	writeCommon(&sb, a)
+	sb.WriteString(` "`)
+	writeEscaped(&sb, a.Request.Referer())
+	sb.WriteString(`" "`)
+	writeEscaped(&sb, a.Request.UserAgent())
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func writeCommon(sb *strings.Builder, a AccessLog) {
+	r := a.Request
+
+	host := r.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	writeOrDash(sb, host)
+	sb.WriteString(" - ")
+
+	user := ""
+	if r.URL != nil && r.URL.User != nil {
+		user = r.URL.User.Username()
+	} else if u, _, ok := r.BasicAuth(); ok {
+		user = u
+	}
+	writeOrDash(sb, user)
+
+	sb.WriteString(" [")
+	sb.WriteString(a.Start.Format("02/Jan/2006:15:04:05 -0700"))
+	sb.WriteString(`] "`)
+
+	uri := r.RequestURI
+	if uri == "" && r.URL != nil {
+		uri = r.URL.RequestURI()
+	}
+	writeEscaped(sb, r.Method+" "+uri+" "+r.Proto)
+	sb.WriteString(`" `)
+
+	sb.WriteString(strconv.Itoa(a.Status))
+	sb.WriteByte(' ')
+	if a.Size == 0 {
+		sb.WriteByte('-')
+	} else {
+		sb.WriteString(strconv.Itoa(a.Size))
+	}
+}
+
+// writeOrDash writes s, escaped, or "-" if s is empty.
+func writeOrDash(sb *strings.Builder, s string) {
+	if s == "" {
+		sb.WriteByte('-')
+		return
+	}
+
+	writeEscaped(sb, s)
+}
+
+// writeEscaped writes s, escaping '"', '\' and non-printable
+// characters as httpd does.
+func writeEscaped(sb *strings.Builder, s string) {
+	const hex = "0123456789abcdef"
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			sb.WriteString(`\x`)
+			sb.WriteByte(hex[c>>4])
+			sb.WriteByte(hex[c&0xf])
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
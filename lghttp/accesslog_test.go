@@ -0,0 +1,54 @@
+package lghttp_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lghttp"
+)
+
+func TestAccessLogFormat(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/a.gif?x=1", nil)
+	r.RemoteAddr = "127.0.0.1:1234"
+	r.SetBasicAuth("frank", "secret")
+	r.Header.Set("Referer", "http://example.com/")
+	r.Header.Set("User-Agent", `Mozilla/5.0 "quoted"`+"\n")
+
+	a := lghttp.AccessLog{
+		Request: r,
+		Status:  200,
+		Size:    2326,
+		Start:   time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*3600)),
+	}
+
+	require.Equal(t, `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif?x=1 HTTP/1.1" 200 2326`,
+		lghttp.CommonLogFormat(a))
+	require.Equal(t, `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif?x=1 HTTP/1.1" 200 2326 `+
+		`"http://example.com/" "Mozilla/5.0 \"quoted\"\x0a"`, lghttp.CombinedLogFormat(a))
+
+	a.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	a.Request.RemoteAddr = ""
+	a.Status, a.Size = 204, 0
+	require.Equal(t, `- - - [10/Oct/2000:13:55:36 -0700] "POST / HTTP/1.1" 204 - "" ""`,
+		lghttp.CombinedLogFormat(a))
+}
+
+func TestMiddleware_AccessLog(t *testing.T) {
+	access := &bytes.Buffer{}
+	handler := lghttp.Middleware(lg.Discard(), lghttp.WithAccessLog(access, lghttp.CommonLogFormat))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("nope"))
+		}))
+
+	r := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.Regexp(t, `^192\.0\.2\.1 - - \[[^\]]+\] "GET /missing HTTP/1\.1" 404 4\n$`, access.String())
+}
@@ -0,0 +1,171 @@
+package lghttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgsink"
+)
+
+// SensitiveHeaders are the headers whose captured values are always
+// replaced by lgsink.DefaultMask. See WithCaptureHeaders.
+var SensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// truncatedSuffix is appended to a captured body that was truncated.
+const truncatedSuffix = "...[truncated]"
+
+// captureOptions holds the values set by the capture Options.
+type captureOptions struct {
+	headers []string
+	maxBody int
+	redact  func(s string) string
+}
+
+// captureOpts returns o's captureOptions, creating them if necessary.
+func (o *options) captureOpts() *captureOptions {
+	if o.capture == nil {
+		o.capture = &captureOptions{redact: defaultRedact}
+	}
+
+	return o.capture
+}
+
+// WithCaptureHeaders returns an Option that captures the values of the
+// named request and response headers, as fields
+// "http.request.header.<name>" and "http.response.header.<name>",
+// where name is lower case. The values of SensitiveHeaders are masked,
+// and other values are redacted (see WithCaptureRedact). Capture is
+// intended for debugging API integrations, and is only performed if
+// DEBUG level is enabled for the Log passed to Middleware.
+func WithCaptureHeaders(names ...string) Option {
+	return func(o *options) {
+		c := o.captureOpts()
+		for _, name := range names {
+			c.headers = append(c.headers, http.CanonicalHeaderKey(name))
+		}
+	}
+}
+
+// WithCaptureBodies returns an Option that captures up to maxBytes of
+// the request body (as read by the handler) and of the response body,
+// as fields "http.request.body" and "http.response.body". Truncated
+// bodies end with "...[truncated]". Bodies are redacted (see
+// WithCaptureRedact). As with WithCaptureHeaders, capture is only
+// performed if DEBUG level is enabled.
+func WithCaptureBodies(maxBytes int) Option {
+	return func(o *options) {
+		if maxBytes > 0 {
+			o.captureOpts().maxBody = maxBytes
+		}
+	}
+}
+
+// WithCaptureRedact returns an Option that sets the func that redacts
+// captured header values and bodies. By default, secret text is masked
+// as by lgsink.Masked with lgsink.DefaultSecretPatterns.
+func WithCaptureRedact(fn func(s string) string) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.captureOpts().redact = fn
+		}
+	}
+}
+
+// defaultRedact masks secret text in s as lgsink.Masked does.
+func defaultRedact(s string) string {
+	buf := &bytes.Buffer{}
+	_, _ = lgsink.NewMasked(buf).Write([]byte(s))
+	return buf.String()
+}
+
+// captureBuf holds up to max bytes written to it.
+type captureBuf struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (c *captureBuf) write(p []byte) {
+	if room := c.max - c.buf.Len(); len(p) > room {
+		p = p[:room]
+		c.truncated = true
+	}
+	c.buf.Write(p)
+}
+
+// String returns the captured bytes, with truncatedSuffix
+// appended if truncated.
+func (c *captureBuf) String() string {
+	if c.truncated {
+		return c.buf.String() + truncatedSuffix
+	}
+
+	return c.buf.String()
+}
+
+// captureBody is an io.ReadCloser that captures
+// the bytes read from the request body.
+type captureBody struct {
+	io.ReadCloser
+	capture *captureBuf
+}
+
+func (b *captureBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.capture.write(p[:n])
+	return n, err
+}
+
+// fields returns the captured header and body fields.
+func (c *captureOptions) fields(r *http.Request, sw *statusWriter, reqBody *captureBuf) []lg.Field {
+	var fields []lg.Field
+	addHeader := func(prefix string, h http.Header, name string) {
+		vals := h.Values(name)
+		if len(vals) == 0 {
+			return
+		}
+
+		val := strings.Join(vals, ", ")
+		if isSensitiveHeader(name) {
+			val = lgsink.DefaultMask
+		} else {
+			val = c.redact(val)
+		}
+		fields = append(fields, lg.Field{Key: prefix + strings.ToLower(name), Val: val})
+	}
+
+	for _, name := range c.headers {
+		addHeader("http.request.header.", r.Header, name)
+	}
+	for _, name := range c.headers {
+		addHeader("http.response.header.", sw.Header(), name)
+	}
+
+	if reqBody != nil {
+		fields = append(fields, lg.Field{Key: "http.request.body", Val: c.redact(reqBody.String())})
+	}
+	if sw.body != nil {
+		fields = append(fields, lg.Field{Key: "http.response.body", Val: c.redact(sw.body.String())})
+	}
+
+	return fields
+}
+
+func isSensitiveHeader(name string) bool {
+	for _, s := range SensitiveHeaders {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+
+	return false
+}
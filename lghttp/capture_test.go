@@ -0,0 +1,83 @@
+package lghttp_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lghttp"
+	"github.com/neilotoole/lg/v2/lgsink"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func captureHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Set-Cookie", "session=secret")
+		_, _ = w.Write([]byte("echo: "))
+		_, _ = w.Write(body)
+	})
+}
+
+func TestMiddleware_Capture(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := lghttp.Middleware(testlg.NewJSON(buf),
+		lghttp.WithCaptureHeaders("content-type", "Authorization", "Set-Cookie", "X-Trace"),
+		lghttp.WithCaptureBodies(32),
+	)(captureHandler())
+
+	reqBody := "token=Bearer abc.def " + strings.Repeat("x", 40)
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, "echo: "+reqBody, rec.Body.String())
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	m := ms[0]
+	require.Equal(t, "text/plain", m["http.request.header.content-type"])
+	require.Equal(t, lgsink.DefaultMask, m["http.request.header.authorization"])
+	require.Nil(t, m["http.request.header.x-trace"])
+	require.Equal(t, "text/plain", m["http.response.header.content-type"])
+	require.Equal(t, lgsink.DefaultMask, m["http.response.header.set-cookie"])
+	require.Equal(t, "token=Bearer "+lgsink.DefaultMask+" "+strings.Repeat("x", 11)+"...[truncated]",
+		m["http.request.body"])
+	require.Equal(t, "echo: token=Bearer "+lgsink.DefaultMask+" xxxxx...[truncated]", m["http.response.body"])
+}
+
+func TestMiddleware_CaptureRedact(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := lghttp.Middleware(testlg.NewJSON(buf),
+		lghttp.WithCaptureBodies(1024),
+		lghttp.WithCaptureRedact(strings.ToUpper),
+	)(captureHandler())
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hi")))
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "HI", ms[0]["http.request.body"])
+	require.Equal(t, "ECHO: HI", ms[0]["http.response.body"])
+}
+
+func TestMiddleware_CaptureNotDebug(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := testlg.NewJSON(buf, zaplg.WithLevel(lg.LevelWarn))
+	h := lghttp.Middleware(log, lghttp.WithCaptureBodies(1024),
+		lghttp.WithLevelFunc(func(int) lg.Level { return lg.LevelWarn }))(captureHandler())
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hi")))
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Nil(t, ms[0]["http.request.body"])
+	require.Nil(t, ms[0]["http.response.body"])
+}
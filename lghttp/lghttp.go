@@ -0,0 +1,335 @@
+// Package lghttp provides net/http middleware that logs
+// each request to a lg.Log.
+//
+//	mux := http.NewServeMux()
+//	// ... register handlers
+//	handler := lghttp.Middleware(log)(mux)
+//	http.ListenAndServe(":8080", handler)
+package lghttp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Option is a functional option for Middleware.
+type Option func(o *options)
+
+type options struct {
+	levelFn       func(status int) lg.Level
+	routeFn       func(r *http.Request) string
+	recoverPanics bool
+	onPanic       func(r *http.Request, rec any, stack []byte)
+	accessLog     *accessLogger
+	requestID     bool
+	traceContext  bool
+	sampleRules   []*sampleRule
+	capture       *captureOptions
+}
+
+// RequestIDHeader is the header that carries the request ID.
+// See WithRequestID.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen is the maximum length of an inbound request ID.
+const maxRequestIDLen = 128
+
+// WithLevelFunc returns an Option that sets the func that determines
+// the level at which a request is logged, based on the response
+// status. The default is DefaultLevel.
+func WithLevelFunc(fn func(status int) lg.Level) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.levelFn = fn
+		}
+	}
+}
+
+// WithRouteFunc returns an Option that sets the func that determines
+// the request's route, logged as field "http.route". This is
+// typically the route pattern from the application's router, e.g.
+// "/users/{id}". By default, the route is not logged.
+func WithRouteFunc(fn func(r *http.Request) string) Option {
+	return func(o *options) {
+		o.routeFn = fn
+	}
+}
+
+// WithRecover returns an Option that sets whether Middleware
+// recovers from panics in the wrapped handler. If true (the default),
+// a 500 response is sent if the response has not already been started,
+// and the request is logged at ERROR level with fields "panic" and
+// "stack". If false, the panic propagates, and the request is not
+// logged. Note that http.ErrAbortHandler is never recovered.
+func WithRecover(enabled bool) Option {
+	return func(o *options) {
+		o.recoverPanics = enabled
+	}
+}
+
+// WithOnPanic returns an Option that sets a func that is invoked
+// with each panic recovered by Middleware (see WithRecover), and
+// its stack, after the panic is logged. For example, the func
+// could write a crash report via package lgcrash.
+func WithOnPanic(fn func(r *http.Request, rec any, stack []byte)) Option {
+	return func(o *options) {
+		o.onPanic = fn
+	}
+}
+
+// WithRequestID returns an Option that causes Middleware to correlate
+// each request's entries by request ID. The ID is taken from the
+// inbound RequestIDHeader if it is present and valid (at most 128
+// printable ASCII characters), or else is generated via
+// lg.NewRequestID. The ID is stored in the request's context via
+// lg.ContextWithRequestID, so that entries logged via lg.Ctx(r.Context())
+// have field lg.RequestIDKey, and it is set as the response's
+// RequestIDHeader.
+func WithRequestID() Option {
+	return func(o *options) {
+		o.requestID = true
+	}
+}
+
+// WithTraceContext returns an Option that sets whether Middleware
+// parses the W3C Trace Context headers (traceparent and tracestate)
+// of each request. If true (the default), and the request has a
+// valid traceparent header, the request's entries have the fields
+// of TraceContext, even when no tracing SDK is installed. The
+// TraceContext is also stored in the request's context: see
+// TraceFields.
+func WithTraceContext(enabled bool) Option {
+	return func(o *options) {
+		o.traceContext = enabled
+	}
+}
+
+// requestID returns the valid request ID of
+// header RequestIDHeader of r, or a new ID.
+func requestID(r *http.Request) string {
+	id := r.Header.Get(RequestIDHeader)
+	if id == "" || len(id) > maxRequestIDLen {
+		return lg.NewRequestID()
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return lg.NewRequestID()
+		}
+	}
+
+	return id
+}
+
+// DefaultLevel logs 5xx responses at ERROR level,
+// and all others at DEBUG level.
+func DefaultLevel(status int) lg.Level {
+	if status >= http.StatusInternalServerError {
+		return lg.LevelError
+	}
+
+	return lg.LevelDebug
+}
+
+// Middleware returns middleware that logs each request to log,
+// with the fields returned by lg.ContextFields for the request's
+// context (and TraceFields), lg.HTTPRequestFields and
+// lg.HTTPResponseFields.
+func Middleware(log lg.Log, opts ...Option) func(next http.Handler) http.Handler {
+	o := options{levelFn: DefaultLevel, recoverPanics: true, traceContext: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	log = lg.OrDiscard(log)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			if o.requestID {
+				id := requestID(r)
+				r = r.WithContext(lg.ContextWithRequestID(r.Context(), id))
+				w.Header().Set(RequestIDHeader, id)
+			}
+			if o.traceContext {
+				if tc, ok := ParseTraceContext(r.Header); ok {
+					r = r.WithContext(context.WithValue(r.Context(), traceContextKey{}, tc))
+				}
+			}
+			sw := &statusWriter{ResponseWriter: w}
+
+			var reqBody *captureBuf
+			if o.capture != nil && o.capture.maxBody > 0 && lg.Enabled(log, lg.LevelDebug) {
+				sw.body = &captureBuf{max: o.capture.maxBody}
+				if r.Body != nil && r.Body != http.NoBody {
+					reqBody = &captureBuf{max: o.capture.maxBody}
+					r.Body = &captureBody{ReadCloser: r.Body, capture: reqBody}
+				}
+			}
+
+			completed := false
+			defer func() {
+				if completed {
+					logRequest(log, &o, r, sw, reqBody, start, nil)
+					return
+				}
+
+				// The handler panicked (or invoked runtime.Goexit). If the
+				// panic isn't recovered, it's not logged here: the request
+				// is logged by whoever recovers it, such as net/http.
+				if !o.recoverPanics {
+					return
+				}
+
+				rec := recover()
+				if rec == nil {
+					// runtime.Goexit.
+					return
+				}
+				if rec == http.ErrAbortHandler { //nolint:errorlint // sentinel is compared by identity
+					panic(rec)
+				}
+
+				if !sw.wroteHeader {
+					http.Error(sw, http.StatusText(http.StatusInternalServerError),
+						http.StatusInternalServerError)
+				}
+
+				stack := debug.Stack()
+				logRequest(log, &o, r, sw, reqBody, start, []lg.Field{
+					{Key: "panic", Val: fmt.Sprint(rec)},
+					{Key: "stack", Val: string(stack)},
+				})
+
+				if o.onPanic != nil {
+					o.onPanic(r, rec, stack)
+				}
+			}()
+
+			next.ServeHTTP(sw, r)
+			completed = true
+		})
+	}
+}
+
+// logRequest logs the request r. If the handler panicked, panicFields
+// holds the panic and its stack, and the request is logged at ERROR.
+func logRequest(log lg.Log, o *options, r *http.Request, sw *statusWriter, reqBody *captureBuf, start time.Time,
+	panicFields []lg.Field,
+) {
+	latency := time.Since(start)
+	status := sw.status
+	if !sw.wroteHeader {
+		// The handler didn't write anything: net/http sends 200.
+		status = http.StatusOK
+	}
+
+	level := o.levelFn(status)
+	if panicFields != nil {
+		level = lg.LevelError
+	}
+	if level < lg.LevelError && len(o.sampleRules) > 0 && !sampled(o.sampleRules, r) {
+		return
+	}
+
+	if o.accessLog != nil {
+		o.accessLog.write(AccessLog{Request: r, Status: status, Size: sw.size, Start: start, Latency: latency})
+	}
+
+	if !lg.Enabled(log, level) {
+		return
+	}
+
+	log = lg.WithFields(lg.WithFields(log, contextFields(r.Context())...), lg.HTTPRequestFields(r)...)
+	if o.routeFn != nil {
+		log = log.With("http.route", o.routeFn(r))
+	}
+	log = lg.WithFields(log, lg.HTTPResponseFields(status, sw.size, latency)...)
+	if o.capture != nil && lg.Enabled(log, lg.LevelDebug) {
+		log = lg.WithFields(log, o.capture.fields(r, sw, reqBody)...)
+	}
+	log = lg.WithFields(log, panicFields...)
+
+	msg := fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, status)
+	switch level {
+	case lg.LevelError:
+		log.Error(msg)
+	case lg.LevelWarn:
+		log.Warn(msg)
+	default:
+		log.Debug(msg)
+	}
+}
+
+// statusWriter wraps http.ResponseWriter, recording
+// the response status and size.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+
+	// body, if non-nil, captures the response body.
+	body *captureBuf
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	if w.body != nil {
+		w.body.write(b[:n])
+	}
+	return n, err
+}
+
+// Flush implements http.Flusher, if the wrapped
+// writer supports it.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, if the wrapped
+// writer supports it.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("lghttp: wrapped http.ResponseWriter does not implement http.Hijacker")
+	}
+
+	if !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter,
+// for use with http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
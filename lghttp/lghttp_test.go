@@ -0,0 +1,206 @@
+package lghttp_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lghttp"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+func TestMiddleware(t *testing.T) {
+	testCases := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantSize   int
+		wantLevel  string
+	}{
+		{
+			name:       "ok",
+			handler:    func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("hello")) },
+			wantStatus: http.StatusOK,
+			wantSize:   5,
+			wantLevel:  "debug",
+		},
+		{
+			name:       "no_write",
+			handler:    func(w http.ResponseWriter, r *http.Request) {},
+			wantStatus: http.StatusOK,
+			wantLevel:  "debug",
+		},
+		{
+			name:       "not_found",
+			handler:    http.NotFound,
+			wantStatus: http.StatusNotFound,
+			wantSize:   19,
+			wantLevel:  "debug",
+		},
+		{
+			name: "server_error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			wantStatus: http.StatusBadGateway,
+			wantLevel:  "error",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			h := lghttp.Middleware(testlg.NewJSON(buf))(tc.handler)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/path", nil))
+			require.Equal(t, tc.wantStatus, rec.Code)
+
+			ms := testlg.DecodeJSON(t, buf)
+			require.Len(t, ms, 1)
+			require.Equal(t, tc.wantLevel, ms[0]["level"])
+			require.Equal(t, "GET", ms[0]["http.method"])
+			require.Equal(t, "/path", ms[0]["http.path"])
+			require.Equal(t, float64(tc.wantStatus), ms[0]["http.status"])
+			require.Equal(t, float64(tc.wantSize), ms[0]["http.size"])
+			require.Contains(t, ms[0], "http.latency")
+			require.NotContains(t, ms[0], "http.route")
+		})
+	}
+}
+
+func TestMiddleware_Options(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mw := lghttp.Middleware(testlg.NewJSON(buf),
+		lghttp.WithRouteFunc(func(r *http.Request) string { return "/users/{id}" }),
+		lghttp.WithLevelFunc(func(status int) lg.Level { return lg.LevelWarn }),
+	)
+
+	rec := httptest.NewRecorder()
+	mw(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/7", nil))
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	require.Equal(t, "warn", ms[0]["level"])
+	require.Equal(t, "/users/{id}", ms[0]["http.route"])
+}
+
+func TestMiddleware_OnPanic(t *testing.T) {
+	var gotPath string
+	var gotRec any
+	var gotStack []byte
+	h := lghttp.Middleware(lg.Discard(), lghttp.WithOnPanic(func(r *http.Request, rec any, stack []byte) {
+		gotPath, gotRec, gotStack = r.URL.Path, rec, stack
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	require.Equal(t, "/panic", gotPath)
+	require.Equal(t, "boom", gotRec)
+	require.Contains(t, string(gotStack), "lghttp_test.go")
+}
+
+func TestMiddleware_RequestID(t *testing.T) {
+	testCases := []struct {
+		name    string
+		inbound string
+		wantID  string // if empty, a new ID is expected
+	}{
+		{name: "none"},
+		{name: "inbound", inbound: "abc-123", wantID: "abc-123"},
+		{name: "invalid", inbound: "abc\n123"},
+		{name: "too_long", inbound: strings.Repeat("a", 129)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			log := testlg.NewJSON(buf)
+			h := lghttp.Middleware(log, lghttp.WithRequestID())(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					lg.WithContext(r.Context(), log).Debug("handling")
+				}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.inbound != "" {
+				req.Header.Set(lghttp.RequestIDHeader, tc.inbound)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			gotID := rec.Header().Get(lghttp.RequestIDHeader)
+			if tc.wantID != "" {
+				require.Equal(t, tc.wantID, gotID)
+			} else {
+				require.Len(t, gotID, 26)
+			}
+
+			ms := testlg.DecodeJSON(t, buf)
+			require.Len(t, ms, 2)
+			for _, m := range ms {
+				require.Equal(t, gotID, m[lg.RequestIDKey])
+			}
+		})
+	}
+}
+
+func TestMiddleware_Recover(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := lghttp.Middleware(testlg.NewJSON(buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	require.NotPanics(t, func() {
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/panic", nil))
+	})
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1, "the panicking request is logged once")
+	require.Equal(t, "error", ms[0]["level"])
+	require.Equal(t, "POST /panic 500", ms[0]["message"])
+	require.Equal(t, float64(http.StatusInternalServerError), ms[0]["http.status"])
+	require.Equal(t, "boom", ms[0]["panic"])
+	require.Contains(t, ms[0]["stack"], "lghttp_test.go")
+	buf.Reset()
+
+	// ErrAbortHandler must not be recovered.
+	h = lghttp.Middleware(testlg.NewJSON(buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	require.Panics(t, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	// Recovery disabled.
+	h = lghttp.Middleware(testlg.NewJSON(buf), lghttp.WithRecover(false))(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+	require.Panics(t, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	require.Empty(t, testlg.DecodeJSON(t, buf), "the unrecovered request isn't logged")
+}
+
+func TestMiddleware_Flush(t *testing.T) {
+	h := lghttp.Middleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, ok := w.(http.Flusher)
+		require.True(t, ok)
+		f.Flush()
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.True(t, rec.Flushed)
+}
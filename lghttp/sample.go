@@ -0,0 +1,91 @@
+package lghttp
+
+import (
+	"net/http"
+	"path"
+	"sync/atomic"
+)
+
+// SampleRule is an access-log sampling rule for requests matching
+// Method and Path. See WithSampleRules.
+type SampleRule struct {
+	// Method is the request method to match, e.g. "GET".
+	// If empty, any method matches.
+	Method string
+
+	// Path is the URL path pattern to match, in path.Match
+	// syntax, e.g. "/healthz" or "/static/*".
+	Path string
+
+	// Rate is the fraction, from 0 to 1, of matching requests that
+	// are logged. A rate of 0 excludes matching requests.
+	Rate float64
+}
+
+// WithSampleRules returns an Option that samples the logging of
+// requests per rules. The first rule that matches a request applies;
+// requests that match no rule are always logged. Requests logged at
+// ERROR level (see WithLevelFunc) are always logged. Sampling applies
+// to both the Log and the access log (see WithAccessLog), and is
+// deterministic: a rate of 0.01 logs exactly every hundredth request.
+//
+//	lghttp.WithSampleRules(
+//	  lghttp.SampleRule{Path: "/healthz", Rate: 0},
+//	  lghttp.SampleRule{Method: http.MethodGet, Path: "/metrics", Rate: 0.01},
+//	)
+func WithSampleRules(rules ...SampleRule) Option {
+	return func(o *options) {
+		for _, rule := range rules {
+			o.sampleRules = append(o.sampleRules, &sampleRule{SampleRule: rule})
+		}
+	}
+}
+
+// WithExcludePaths returns an Option that excludes requests whose URL
+// path matches any of patterns (in path.Match syntax) from logging.
+// It is equivalent to WithSampleRules with a rate of 0 for each pattern.
+func WithExcludePaths(patterns ...string) Option {
+	return func(o *options) {
+		for _, p := range patterns {
+			o.sampleRules = append(o.sampleRules, &sampleRule{SampleRule: SampleRule{Path: p}})
+		}
+	}
+}
+
+// sampleRule is a SampleRule, with a count of the requests it matched.
+type sampleRule struct {
+	SampleRule
+	n atomic.Uint64
+}
+
+func (rule *sampleRule) matches(r *http.Request) bool {
+	if rule.Method != "" && rule.Method != r.Method {
+		return false
+	}
+
+	ok, _ := path.Match(rule.Path, r.URL.Path)
+	return ok
+}
+
+// sampled reports whether r should be logged, per the
+// first of rules that matches r.
+func sampled(rules []*sampleRule, r *http.Request) bool {
+	for _, rule := range rules {
+		if !rule.matches(r) {
+			continue
+		}
+
+		switch {
+		case rule.Rate >= 1:
+			return true
+		case rule.Rate <= 0:
+			return false
+		}
+
+		// Log the request if it causes n*rate to cross an integer.
+		n := rule.n.Add(1) - 1
+		return uint64(float64(n+1)*rule.Rate) > uint64(float64(n)*rule.Rate)
+	}
+
+	return true
+}
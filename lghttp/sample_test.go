@@ -0,0 +1,58 @@
+package lghttp_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lghttp"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+func TestMiddleware_SampleRules(t *testing.T) {
+	buf, accessBuf := &bytes.Buffer{}, &bytes.Buffer{}
+	h := lghttp.Middleware(testlg.NewJSON(buf),
+		lghttp.WithExcludePaths("/healthz", "/static/*"),
+		lghttp.WithSampleRules(
+			lghttp.SampleRule{Method: http.MethodGet, Path: "/metrics", Rate: 0.1},
+			lghttp.SampleRule{Path: "/fail", Rate: 0},
+		),
+		lghttp.WithAccessLog(accessBuf, lghttp.CommonLogFormat),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	do := func(method, path string, n int) {
+		for i := 0; i < n; i++ {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+		}
+	}
+
+	do(http.MethodGet, "/healthz", 10)
+	do(http.MethodGet, "/static/app.js", 10)
+	do(http.MethodGet, "/static/js/app.js", 1) // "*" doesn't match "/"
+	do(http.MethodGet, "/metrics", 100)
+	do(http.MethodPost, "/metrics", 2)
+	do(http.MethodGet, "/users", 3)
+	do(http.MethodGet, "/fail", 2) // ERROR level is always logged
+
+	counts := map[string]int{}
+	for _, m := range testlg.DecodeJSON(t, buf) {
+		counts[m["message"].(string)]++
+	}
+
+	require.Equal(t, map[string]int{
+		"GET /static/js/app.js 200": 1,
+		"GET /metrics 200":          10,
+		"POST /metrics 200":         2,
+		"GET /users 200":            3,
+		"GET /fail 500":             2,
+	}, counts)
+	require.Equal(t, 18, strings.Count(accessBuf.String(), "\n"))
+}
@@ -0,0 +1,136 @@
+package lghttp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// W3C Trace Context headers.
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+// maxTraceStateLen is the maximum length of a tracestate
+// value that is logged. Longer values are omitted.
+const maxTraceStateLen = 512
+
+// TraceContext is the W3C Trace Context of a request, as carried by
+// its traceparent and tracestate headers.
+type TraceContext struct {
+	// TraceID is the trace ID, as 32 lowercase hex chars.
+	TraceID string
+
+	// ParentSpanID is the span ID of the caller, as 16
+	// lowercase hex chars.
+	ParentSpanID string
+
+	// Sampled is the value of the sampled trace flag.
+	Sampled bool
+
+	// State is the value of the tracestate header, or empty.
+	State string
+}
+
+// Fields returns the fields of tc: "trace_id", "parent_span_id",
+// and, if tc.State is not empty, "tracestate".
+func (tc TraceContext) Fields() []lg.Field {
+	fields := []lg.Field{
+		{Key: "trace_id", Val: tc.TraceID},
+		{Key: "parent_span_id", Val: tc.ParentSpanID},
+	}
+	if tc.State != "" {
+		fields = append(fields, lg.Field{Key: "tracestate", Val: tc.State})
+	}
+
+	return fields
+}
+
+// ParseTraceContext parses the W3C Trace Context headers of h. It
+// returns false if h has no traceparent header, or if the header is
+// invalid. The tracestate header is ignored if traceparent is invalid,
+// or if it is longer than 512 chars.
+func ParseTraceContext(h http.Header) (TraceContext, bool) {
+	var tc TraceContext
+	parts := strings.Split(h.Get(TraceParentHeader), "-")
+	if len(parts) < 4 {
+		return tc, false
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	switch {
+	case !isHex(version, 2), version == "ff":
+		return tc, false
+	case version == "00" && len(parts) != 4:
+		// Later versions may append fields.
+		return tc, false
+	case !isHex(traceID, 32), traceID == strings.Repeat("0", 32):
+		return tc, false
+	case !isHex(spanID, 16), spanID == strings.Repeat("0", 16):
+		return tc, false
+	case !isHex(flags, 2):
+		return tc, false
+	}
+
+	tc.TraceID, tc.ParentSpanID = traceID, spanID
+	f, _ := strconv.ParseUint(flags, 16, 8)
+	tc.Sampled = f&0x01 != 0
+
+	if state := strings.Join(h.Values(TraceStateHeader), ","); len(state) <= maxTraceStateLen {
+		tc.State = state
+	}
+
+	return tc, true
+}
+
+// isHex reports whether s consists of n lowercase hex chars.
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+type traceContextKey struct{}
+
+// TraceFields returns the fields of the TraceContext that Middleware
+// stored in ctx (see WithTraceContext), or nil. It is an
+// lg.ContextFieldsFunc, so the fields can be added to the Log
+// returned by lg.Ctx or lg.WithContext for the request:
+//
+//	lg.RegisterContextFields(lghttp.TraceFields)
+func TraceFields(ctx context.Context) []lg.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	if tc, ok := ctx.Value(traceContextKey{}).(TraceContext); ok {
+		return tc.Fields()
+	}
+
+	return nil
+}
+
+// contextFields returns the fields returned by lg.ContextFields for
+// ctx, and by TraceFields, unless TraceFields is registered.
+func contextFields(ctx context.Context) []lg.Field {
+	fields := lg.ContextFields(ctx)
+	for _, f := range fields {
+		if f.Key == "trace_id" {
+			return fields
+		}
+	}
+
+	return append(fields, TraceFields(ctx)...)
+}
@@ -0,0 +1,103 @@
+package lghttp_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lghttp"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+const (
+	testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID  = "00f067aa0ba902b7"
+)
+
+func TestParseTraceContext(t *testing.T) {
+	testCases := []struct {
+		name        string
+		traceparent string
+		tracestate  []string
+		want        lghttp.TraceContext
+		wantOK      bool
+	}{
+		{name: "none"},
+		{
+			name:        "sampled",
+			traceparent: "00-" + testTraceID + "-" + testSpanID + "-01",
+			tracestate:  []string{"congo=t61rcWkgMzE", "rojo=00f067aa0ba902b7"},
+			want: lghttp.TraceContext{
+				TraceID: testTraceID, ParentSpanID: testSpanID, Sampled: true,
+				State: "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7",
+			},
+			wantOK: true,
+		},
+		{
+			name:        "not_sampled",
+			traceparent: "00-" + testTraceID + "-" + testSpanID + "-00",
+			want:        lghttp.TraceContext{TraceID: testTraceID, ParentSpanID: testSpanID},
+			wantOK:      true,
+		},
+		{
+			name:        "future_version",
+			traceparent: "cc-" + testTraceID + "-" + testSpanID + "-01-what-the-future-holds",
+			want:        lghttp.TraceContext{TraceID: testTraceID, ParentSpanID: testSpanID, Sampled: true},
+			wantOK:      true,
+		},
+		{name: "v00_extra", traceparent: "00-" + testTraceID + "-" + testSpanID + "-01-extra"},
+		{name: "version_ff", traceparent: "ff-" + testTraceID + "-" + testSpanID + "-01"},
+		{name: "upper_case", traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + testSpanID + "-01"},
+		{name: "zero_trace", traceparent: "00-00000000000000000000000000000000-" + testSpanID + "-01"},
+		{name: "zero_span", traceparent: "00-" + testTraceID + "-0000000000000000-01"},
+		{name: "short_span", traceparent: "00-" + testTraceID + "-00f067aa0ba902-01"},
+		{name: "garbage", traceparent: "hello"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			if tc.traceparent != "" {
+				h.Set(lghttp.TraceParentHeader, tc.traceparent)
+			}
+			for _, v := range tc.tracestate {
+				h.Add(lghttp.TraceStateHeader, v)
+			}
+
+			got, ok := lghttp.ParseTraceContext(h)
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMiddleware_TraceContext(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		buf := &bytes.Buffer{}
+		log := testlg.NewJSON(buf)
+		h := lghttp.Middleware(log, lghttp.WithTraceContext(enabled))(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				lg.WithFields(log, lghttp.TraceFields(r.Context())...).Debug("handling")
+			}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(lghttp.TraceParentHeader, "00-"+testTraceID+"-"+testSpanID+"-01")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		ms := testlg.DecodeJSON(t, buf)
+		require.Len(t, ms, 2)
+		for _, m := range ms {
+			if enabled {
+				require.Equal(t, testTraceID, m["trace_id"])
+				require.Equal(t, testSpanID, m["parent_span_id"])
+			} else {
+				require.Nil(t, m["trace_id"])
+			}
+		}
+	}
+}
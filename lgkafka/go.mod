@@ -0,0 +1,23 @@
+module github.com/neilotoole/lg/v2/lgkafka
+
+go 1.19
+
+require (
+	github.com/neilotoole/lg/v2 v2.0.0
+	github.com/stretchr/testify v1.8.1
+	github.com/twmb/franz-go v1.10.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/twmb/franz-go/pkg/kmsg v1.2.0 // indirect
+	go.uber.org/atomic v1.10.0 // indirect
+	go.uber.org/multierr v1.8.0 // indirect
+	go.uber.org/zap v1.23.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/neilotoole/lg/v2 => ../
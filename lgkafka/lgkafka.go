@@ -0,0 +1,132 @@
+// Package lgkafka adapts a lg.Log for use by Kafka client
+// libraries, so that client internals (rebalances, broker errors,
+// etc.) are logged via lg rather than to a raw stdlib logger.
+//
+// For github.com/Shopify/sarama:
+//
+//	sarama.Logger = lgkafka.NewSaramaLogger(log)
+//
+// For github.com/twmb/franz-go:
+//
+//	client, err := kgo.NewClient(kgo.WithLogger(lgkafka.NewKgoLogger(log)))
+package lgkafka
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// SaramaLogger implements sarama's StdLogger interface.
+// Sarama's log messages don't have levels; they are logged at
+// the level returned by LevelFunc, which by default logs messages
+// that look like errors at WARN level, and others at DEBUG level.
+type SaramaLogger struct {
+	log lg.Log
+
+	// LevelFunc returns the level at which msg is logged.
+	LevelFunc func(msg string) lg.Level
+}
+
+// NewSaramaLogger returns a new SaramaLogger that logs to log.
+func NewSaramaLogger(log lg.Log) *SaramaLogger {
+	return &SaramaLogger{
+		log:       lg.AddCallerSkip(lg.OrDiscard(log), 1),
+		LevelFunc: DefaultSaramaLevel,
+	}
+}
+
+// DefaultSaramaLevel returns lg.LevelWarn if msg contains
+// "error" or "failed" (case-insensitive); otherwise lg.LevelDebug.
+func DefaultSaramaLevel(msg string) lg.Level {
+	lower := strings.ToLower(msg)
+	if strings.Contains(lower, "error") || strings.Contains(lower, "failed") {
+		return lg.LevelWarn
+	}
+
+	return lg.LevelDebug
+}
+
+// Print implements sarama.StdLogger.
+func (l *SaramaLogger) Print(v ...any) {
+	l.logMsg(fmt.Sprint(v...))
+}
+
+// Printf implements sarama.StdLogger.
+func (l *SaramaLogger) Printf(format string, v ...any) {
+	l.logMsg(fmt.Sprintf(format, v...))
+}
+
+// Println implements sarama.StdLogger.
+func (l *SaramaLogger) Println(v ...any) {
+	l.logMsg(fmt.Sprintln(v...))
+}
+
+func (l *SaramaLogger) logMsg(msg string) {
+	msg = strings.TrimSuffix(msg, "\n")
+	log := lg.AddCallerSkip(l.log, 1)
+
+	levelFn := l.LevelFunc
+	if levelFn == nil {
+		levelFn = DefaultSaramaLevel
+	}
+
+	switch levelFn(msg) {
+	case lg.LevelError:
+		log.Error(msg)
+	case lg.LevelWarn:
+		log.Warn(msg)
+	default:
+		log.Debug(msg)
+	}
+}
+
+var _ kgo.Logger = (*KgoLogger)(nil)
+
+// KgoLogger implements franz-go's kgo.Logger interface. The kgo
+// levels are mapped to lg levels as follows: Error to ERROR, Warn
+// to WARN, and Info and Debug to DEBUG (lg has no INFO level).
+type KgoLogger struct {
+	log lg.Log
+}
+
+// NewKgoLogger returns a new KgoLogger that logs to log.
+func NewKgoLogger(log lg.Log) *KgoLogger {
+	return &KgoLogger{log: lg.AddCallerSkip(lg.OrDiscard(log), 1)}
+}
+
+// Level implements kgo.Logger. It reports the most verbose kgo
+// level for which the underlying log is enabled (see lg.Enabled).
+func (l *KgoLogger) Level() kgo.LogLevel {
+	switch {
+	case lg.Enabled(l.log, lg.LevelDebug):
+		return kgo.LogLevelDebug
+	case lg.Enabled(l.log, lg.LevelWarn):
+		return kgo.LogLevelWarn
+	case lg.Enabled(l.log, lg.LevelError):
+		return kgo.LogLevelError
+	default:
+		return kgo.LogLevelNone
+	}
+}
+
+// Log implements kgo.Logger.
+func (l *KgoLogger) Log(level kgo.LogLevel, msg string, keyvals ...any) {
+	log := l.log
+	if len(keyvals) > 0 {
+		log = lg.WithKeyVals(log, keyvals...)
+	}
+
+	switch level { //nolint:exhaustive // remaining levels logged at DEBUG
+	case kgo.LogLevelNone:
+	case kgo.LogLevelError:
+		log.Error(msg)
+	case kgo.LogLevelWarn:
+		log.Warn(msg)
+	default:
+		log.Debug(msg)
+	}
+}
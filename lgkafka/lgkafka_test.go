@@ -0,0 +1,66 @@
+package lgkafka_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgkafka"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// stdLogger is sarama's StdLogger interface.
+type stdLogger interface {
+	Print(v ...any)
+	Printf(format string, v ...any)
+	Println(v ...any)
+}
+
+var _ stdLogger = (*lgkafka.SaramaLogger)(nil)
+
+func TestSaramaLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := lgkafka.NewSaramaLogger(testlg.NewJSON(buf))
+
+	l.Print("client/metadata fetching metadata for all topics from broker ", "localhost:9092")
+	l.Printf("consumer/broker/%d disconnecting due to error: %v", 1, "EOF")
+	l.Println("Failed to connect to broker")
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 3)
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "client/metadata fetching metadata for all topics from broker localhost:9092", ms[0]["message"])
+	require.Contains(t, ms[0]["caller"], "lgkafka_test.go")
+	require.Equal(t, "warn", ms[1]["level"])
+	require.Equal(t, "warn", ms[2]["level"])
+	require.Equal(t, "Failed to connect to broker", ms[2]["message"])
+}
+
+func TestKgoLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := lgkafka.NewKgoLogger(testlg.NewJSON(buf))
+	require.Equal(t, kgo.LogLevelDebug, l.Level())
+
+	l.Log(kgo.LogLevelInfo, "assigning partitions", "group", "g1", "partitions", 3)
+	l.Log(kgo.LogLevelWarn, "rebalance")
+	l.Log(kgo.LogLevelError, "broker unreachable", "broker", 2)
+	l.Log(kgo.LogLevelNone, "ignored")
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 3)
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "g1", ms[0]["group"])
+	require.Equal(t, float64(3), ms[0]["partitions"])
+	require.Contains(t, ms[0]["caller"], "lgkafka_test.go")
+	require.Equal(t, "warn", ms[1]["level"])
+	require.Equal(t, "error", ms[2]["level"])
+	require.Equal(t, float64(2), ms[2]["broker"])
+
+	warnLog := testlg.NewJSON(buf, zaplg.WithLevel(lg.LevelWarn))
+	require.Equal(t, kgo.LogLevelWarn, lgkafka.NewKgoLogger(warnLog).Level())
+	require.Equal(t, kgo.LogLevelNone, lgkafka.NewKgoLogger(lg.Discard()).Level())
+}
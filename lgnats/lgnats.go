@@ -0,0 +1,91 @@
+// Package lgnats provides nats.go connection event handlers that
+// log to a lg.Log, with the connection's metadata as fields.
+//
+//	nc, err := nats.Connect(url, lgnats.Options(log)...)
+//
+// Asynchronous errors (such as slow consumer errors) and
+// disconnects are logged at WARN level; reconnects and
+// closes are logged at DEBUG level.
+package lgnats
+
+import (
+	"github.com/nats-io/nats.go"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Options returns nats options that set each of the handlers
+// in this package.
+func Options(log lg.Log) []nats.Option {
+	return []nats.Option{
+		nats.ErrorHandler(ErrorHandler(log)),
+		nats.DisconnectErrHandler(DisconnectErrHandler(log)),
+		nats.ReconnectHandler(ReconnectHandler(log)),
+		nats.ClosedHandler(ClosedHandler(log)),
+	}
+}
+
+// ErrorHandler returns a nats.ErrHandler that logs asynchronous
+// errors. If the error relates to a subscription, the fields
+// "nats.subject" and "nats.queue" are added.
+func ErrorHandler(log lg.Log) nats.ErrHandler {
+	log = lg.OrDiscard(log)
+	return func(nc *nats.Conn, sub *nats.Subscription, err error) {
+		l := connLog(log, nc)
+		if sub != nil {
+			l = l.With("nats.subject", sub.Subject)
+			if sub.Queue != "" {
+				l = l.With("nats.queue", sub.Queue)
+			}
+		}
+
+		lg.WithError(l, err).Warn("nats: async error")
+	}
+}
+
+// DisconnectErrHandler returns a nats.ConnErrHandler that logs
+// disconnects, with the disconnect error if any.
+func DisconnectErrHandler(log lg.Log) nats.ConnErrHandler {
+	log = lg.OrDiscard(log)
+	return func(nc *nats.Conn, err error) {
+		l := connLog(log, nc)
+		if err != nil {
+			l = lg.WithError(l, err)
+		}
+
+		l.Warn("nats: disconnected")
+	}
+}
+
+// ReconnectHandler returns a nats.ConnHandler that logs reconnects.
+func ReconnectHandler(log lg.Log) nats.ConnHandler {
+	log = lg.OrDiscard(log)
+	return func(nc *nats.Conn) {
+		connLog(log, nc).Debug("nats: reconnected")
+	}
+}
+
+// ClosedHandler returns a nats.ConnHandler that logs connection close.
+func ClosedHandler(log lg.Log) nats.ConnHandler {
+	log = lg.OrDiscard(log)
+	return func(nc *nats.Conn) {
+		connLog(log, nc).Debug("nats: connection closed")
+	}
+}
+
+// connLog returns log with the metadata of nc, if any, as fields.
+func connLog(log lg.Log, nc *nats.Conn) lg.Log {
+	if nc == nil {
+		return log
+	}
+
+	if url := nc.ConnectedUrl(); url != "" {
+		log = log.With("nats.url", url)
+	}
+
+	if id := nc.ConnectedServerId(); id != "" {
+		log = log.With("nats.server_id", id)
+	}
+
+	return log
+}
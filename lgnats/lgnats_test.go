@@ -0,0 +1,56 @@
+package lgnats_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgnats"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+func TestHandlers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := testlg.NewJSON(buf)
+
+	sub := &nats.Subscription{Subject: "orders.created", Queue: "workers"}
+	lgnats.ErrorHandler(log)(nil, sub, errors.New("slow consumer"))
+	lgnats.DisconnectErrHandler(log)(nil, errors.New("EOF"))
+	lgnats.DisconnectErrHandler(log)(nil, nil)
+	lgnats.ReconnectHandler(log)(nil)
+	lgnats.ClosedHandler(log)(nil)
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 5)
+
+	require.Equal(t, "warn", ms[0]["level"])
+	require.Equal(t, "orders.created", ms[0]["nats.subject"])
+	require.Equal(t, "workers", ms[0]["nats.queue"])
+	require.Equal(t, "slow consumer", ms[0]["error"])
+
+	require.Equal(t, "warn", ms[1]["level"])
+	require.Equal(t, "EOF", ms[1]["error"])
+	require.NotContains(t, ms[2], "error")
+
+	require.Equal(t, "debug", ms[3]["level"])
+	require.Equal(t, "nats: reconnected", ms[3]["message"])
+	require.Equal(t, "nats: connection closed", ms[4]["message"])
+}
+
+func TestOptions(t *testing.T) {
+	opts := &nats.Options{}
+	for _, opt := range lgnats.Options(nil) {
+		require.NoError(t, opt(opts))
+	}
+
+	require.NotNil(t, opts.AsyncErrorCB)
+	require.NotNil(t, opts.DisconnectedErrCB)
+	require.NotNil(t, opts.ReconnectedCB)
+	require.NotNil(t, opts.ClosedCB)
+
+	// Nil log is treated as lg.Discard.
+	opts.ClosedCB(nil)
+}
@@ -0,0 +1,40 @@
+// Package lgredis provides a go-redis logger that logs to a lg.Log.
+//
+//	redis.SetLogger(lgredis.New(log, lg.Field{Key: "redis.addr", Val: addr}))
+//
+// The go-redis internal logger is used for problems such as bad
+// connections and failed pool operations, so messages are logged
+// at WARN level.
+package lgredis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Logger implements go-redis's internal logging interface.
+type Logger struct {
+	log    lg.Log
+	fields []lg.Field
+}
+
+// New returns a new Logger that logs to log, adding fields (such
+// as the connection address) to each entry. If the ctx passed to
+// Printf carries a Log (see lg.NewContext), that Log is used instead.
+func New(log lg.Log, fields ...lg.Field) *Logger {
+	return &Logger{log: lg.OrDiscard(log), fields: fields}
+}
+
+// Printf implements go-redis's internal.Logging interface.
+func (l *Logger) Printf(ctx context.Context, format string, v ...any) {
+	log := l.log
+	if ctx != nil {
+		if ctxLog := lg.FromContext(ctx); ctxLog != nil {
+			log = ctxLog
+		}
+	}
+
+	lg.AddCallerSkip(lg.WithFields(log, l.fields...), 1).Warn(fmt.Sprintf(format, v...))
+}
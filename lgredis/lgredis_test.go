@@ -0,0 +1,41 @@
+package lgredis_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgredis"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// logging is go-redis's internal.Logging interface.
+type logging interface {
+	Printf(ctx context.Context, format string, v ...any)
+}
+
+var _ logging = (*lgredis.Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+
+	l := lgredis.New(log, lg.Field{Key: "redis.addr", Val: "localhost:6379"})
+	l.Printf(context.Background(), "redis: discarding bad PubSub connection: %s", "EOF")
+
+	got := buf.String()
+	require.Contains(t, got, `"level":"warn"`)
+	require.Contains(t, got, `"message":"redis: discarding bad PubSub connection: EOF"`)
+	require.Contains(t, got, `"redis.addr":"localhost:6379"`)
+	require.Contains(t, got, "lgredis_test.go")
+
+	ctxBuf := &bytes.Buffer{}
+	ctx := lg.NewContext(context.Background(), zaplg.NewWith(ctxBuf, "json", false, false, true, false, 0))
+	l.Printf(ctx, "redis: pool timeout")
+	require.Contains(t, ctxBuf.String(), `"redis.addr":"localhost:6379"`, "log carried by ctx should be preferred")
+
+	lgredis.New(nil).Printf(context.Background(), "discarded")
+}
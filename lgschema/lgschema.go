@@ -0,0 +1,398 @@
+// Package lgschema validates JSON log entries against a JSON Schema,
+// catching accidental field-type drift that would break downstream log
+// pipelines. It can be used in tests, to validate captured output:
+//
+//	schema, err := lgschema.Compile(schemaJSON)
+//	require.NoError(t, err)
+//	// ... exercise code that logs to buf
+//	require.NoError(t, schema.ValidateAll(buf))
+//
+// Or, in a strict mode, as a sink that validates each entry:
+//
+//	w := lgschema.NewWriter(os.Stderr, schema, lgschema.WithStrict())
+//	log := zaplg.NewWith(w, "json", true, true, true, true, 0)
+//
+// Only a subset of JSON Schema is supported: the keywords type,
+// properties, required, additionalProperties, items, enum, const,
+// minimum, maximum, minLength, maxLength and pattern. Other keywords
+// are ignored.
+package lgschema
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	types      []string
+	properties map[string]*Schema
+	required   []string
+
+	// additional is the schema of additional properties; if
+	// noAdditional is true, additional properties are invalid.
+	additional   *Schema
+	noAdditional bool
+
+	items    *Schema
+	enum     []any
+	constVal *any
+
+	minimum, maximum     *float64
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+}
+
+// schemaDoc is the JSON form of a schema.
+type schemaDoc struct {
+	Type                 json.RawMessage            `json:"type"`
+	Properties           map[string]json.RawMessage `json:"properties"`
+	Required             []string                   `json:"required"`
+	AdditionalProperties json.RawMessage            `json:"additionalProperties"`
+	Items                json.RawMessage            `json:"items"`
+	Enum                 []any                      `json:"enum"`
+	Const                json.RawMessage            `json:"const"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	Pattern              *string                    `json:"pattern"`
+}
+
+// Compile compiles the JSON Schema in data.
+func Compile(data []byte) (*Schema, error) {
+	s, err := compile(data, "")
+	if err != nil {
+		return nil, fmt.Errorf("lgschema: %w", err)
+	}
+
+	return s, nil
+}
+
+// MustCompile is like Compile, but panics on error.
+func MustCompile(data []byte) *Schema {
+	s, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+//nolint:cyclop,funlen,gocognit,gocyclo // a flat list of keywords
+func compile(data []byte, path string) (*Schema, error) {
+	if b := bytes.TrimSpace(data); bytes.Equal(b, []byte("true")) {
+		return &Schema{}, nil
+	}
+
+	var doc schemaDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("schema %s: %w", pointer(path), err)
+	}
+
+	s := &Schema{
+		required:  doc.Required,
+		enum:      doc.Enum,
+		minimum:   doc.Minimum,
+		maximum:   doc.Maximum,
+		minLength: doc.MinLength,
+		maxLength: doc.MaxLength,
+	}
+
+	if len(doc.Type) > 0 {
+		if err := json.Unmarshal(doc.Type, &s.types); err != nil {
+			var typ string
+			if err = json.Unmarshal(doc.Type, &typ); err != nil {
+				return nil, fmt.Errorf("schema %s: invalid type: %s", pointer(path), doc.Type)
+			}
+			s.types = []string{typ}
+		}
+	}
+
+	if len(doc.Const) > 0 {
+		var v any
+		if err := json.Unmarshal(doc.Const, &v); err != nil {
+			return nil, fmt.Errorf("schema %s: invalid const: %w", pointer(path), err)
+		}
+		s.constVal = &v
+	}
+
+	if doc.Pattern != nil {
+		rx, err := regexp.Compile(*doc.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: invalid pattern: %w", pointer(path), err)
+		}
+		s.pattern = rx
+	}
+
+	for name, raw := range doc.Properties {
+		prop, err := compile(raw, path+"/properties/"+name)
+		if err != nil {
+			return nil, err
+		}
+		if s.properties == nil {
+			s.properties = map[string]*Schema{}
+		}
+		s.properties[name] = prop
+	}
+
+	if len(doc.AdditionalProperties) > 0 {
+		if bytes.Equal(bytes.TrimSpace(doc.AdditionalProperties), []byte("false")) {
+			s.noAdditional = true
+		} else {
+			add, err := compile(doc.AdditionalProperties, path+"/additionalProperties")
+			if err != nil {
+				return nil, err
+			}
+			s.additional = add
+		}
+	}
+
+	if len(doc.Items) > 0 {
+		items, err := compile(doc.Items, path+"/items")
+		if err != nil {
+			return nil, err
+		}
+		s.items = items
+	}
+
+	return s, nil
+}
+
+// ValidationError is returned by Validate if an entry is invalid.
+type ValidationError struct {
+	// Problems describes each problem, prefixed with the
+	// JSON pointer of the offending value, e.g.
+	// `/retries: got string, want integer`.
+	Problems []string
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	return "lgschema: invalid entry: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate validates the JSON entry. If entry is not valid
+// against s, the returned error is a *ValidationError.
+func (s *Schema) Validate(entry []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(entry))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("lgschema: invalid JSON entry: %w", err)
+	}
+
+	var problems []string
+	s.validate(v, "", &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// ValidateAll validates each line of r as a JSON entry, as per
+// Validate, returning an error that reports the line number of the
+// first invalid entry. Blank lines are ignored.
+func (s *Schema) ValidateAll(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+
+		if err := s.Validate(sc.Bytes()); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return sc.Err()
+}
+
+//nolint:cyclop,gocognit,gocyclo // a flat list of keywords
+func (s *Schema) validate(v any, path string, problems *[]string) {
+	addf := func(format string, a ...any) {
+		*problems = append(*problems, pointer(path)+": "+fmt.Sprintf(format, a...))
+	}
+
+	if len(s.types) > 0 && !s.typeMatches(v) {
+		addf("got %s, want %s", typeOf(v), strings.Join(s.types, " or "))
+		return
+	}
+
+	if s.constVal != nil && !equal(v, *s.constVal) {
+		addf("got %s, want const %v", compact(v), *s.constVal)
+	}
+
+	if len(s.enum) > 0 {
+		found := false
+		for _, e := range s.enum {
+			if equal(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			addf("got %s, not in enum", compact(v))
+		}
+	}
+
+	switch v := v.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		if s.minimum != nil && f < *s.minimum {
+			addf("got %s, want minimum %v", v, *s.minimum)
+		}
+		if s.maximum != nil && f > *s.maximum {
+			addf("got %s, want maximum %v", v, *s.maximum)
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.minLength != nil && n < *s.minLength {
+			addf("got length %d, want minLength %d", n, *s.minLength)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			addf("got length %d, want maxLength %d", n, *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			addf("got %q, want match of pattern %q", v, s.pattern)
+		}
+	case []any:
+		if s.items != nil {
+			for i, item := range v {
+				s.items.validate(item, path+"/"+strconv.Itoa(i), problems)
+			}
+		}
+	case map[string]any:
+		s.validateObject(v, path, problems)
+	}
+}
+
+func (s *Schema) validateObject(obj map[string]any, path string, problems *[]string) {
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			*problems = append(*problems, pointer(path+"/"+name)+": required property is missing")
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if prop, ok := s.properties[name]; ok {
+			prop.validate(obj[name], path+"/"+name, problems)
+			continue
+		}
+
+		switch {
+		case s.noAdditional:
+			*problems = append(*problems, pointer(path+"/"+name)+": additional property is not allowed")
+		case s.additional != nil:
+			s.additional.validate(obj[name], path+"/"+name, problems)
+		}
+	}
+}
+
+func (s *Schema) typeMatches(v any) bool {
+	got := typeOf(v)
+	for _, want := range s.types {
+		if want == got {
+			return true
+		}
+
+		if want == "number" && got == "integer" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// typeOf returns the JSON Schema type of v, as decoded
+// with json.Decoder.UseNumber.
+func typeOf(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) && !strings.ContainsAny(string(v), ".eE") {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// equal reports whether v (decoded with UseNumber) equals
+// want (decoded without UseNumber).
+func equal(v, want any) bool {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		w, ok := want.(float64)
+		return err == nil && ok && f == w
+	}
+
+	return reflect.DeepEqual(normalize(v), want)
+}
+
+// normalize converts the json.Number values in v to float64.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = normalize(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func compact(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// pointer returns path as a JSON pointer; the root is "/".
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,117 @@
+package lgschema_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgschema"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["level", "message"],
+  "properties": {
+    "level": {"enum": ["debug", "warn", "error"]},
+    "message": {"type": "string", "minLength": 1},
+    "retries": {"type": "integer", "minimum": 0},
+    "latency": {"type": "number"},
+    "request_id": {"type": "string", "pattern": "^[0-9a-f]{8}$"},
+    "tags": {"type": "array", "items": {"type": "string"}}
+  },
+  "additionalProperties": false
+}`
+
+func TestSchema_Validate(t *testing.T) {
+	schema := lgschema.MustCompile([]byte(testSchema))
+
+	testCases := []struct {
+		entry string
+		want  []string
+	}{
+		{entry: `{"level":"debug","message":"ok","retries":3,"latency":1.5,"tags":["a"]}`},
+		{entry: `{"level":"debug","message":"ok","latency":2}`},
+		{entry: `{"level":"debug","message":"ok","retries":"3"}`, want: []string{"/retries: got string, want integer"}},
+		{entry: `{"level":"debug","message":"ok","retries":1.5}`, want: []string{"/retries: got number, want integer"}},
+		{entry: `{"level":"debug","message":"ok","retries":-1}`, want: []string{"/retries: got -1, want minimum 0"}},
+		{entry: `{"level":"info","message":""}`, want: []string{
+			`/level: got "info", not in enum`,
+			"/message: got length 0, want minLength 1",
+		}},
+		{entry: `{"message":"ok","user":"x"}`, want: []string{
+			"/level: required property is missing",
+			"/user: additional property is not allowed",
+		}},
+		{entry: `{"level":"warn","message":"ok","request_id":"xyz","tags":["a",1]}`, want: []string{
+			`/request_id: got "xyz", want match of pattern "^[0-9a-f]{8}$"`,
+			"/tags/1: got integer, want string",
+		}},
+		{entry: `[]`, want: []string{"/: got array, want object"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.entry, func(t *testing.T) {
+			err := schema.Validate([]byte(tc.entry))
+			if tc.want == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr *lgschema.ValidationError
+			require.True(t, errors.As(err, &verr), "got %v", err)
+			require.Equal(t, tc.want, verr.Problems)
+		})
+	}
+}
+
+func TestCompile_Error(t *testing.T) {
+	_, err := lgschema.Compile([]byte(`{"type": 1}`))
+	require.Error(t, err)
+
+	_, err = lgschema.Compile([]byte(`{"properties": {"a": {"pattern": "("}}}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "/properties/a")
+}
+
+func TestSchema_ValidateAll(t *testing.T) {
+	schema := lgschema.MustCompile([]byte(testSchema))
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log.With("retries", 3).Debug("retrying")
+	require.NoError(t, schema.ValidateAll(bytes.NewReader(buf.Bytes())))
+
+	log.With("retries", "three").Warn("drifted")
+	err := schema.ValidateAll(buf)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 2: ")
+	require.Contains(t, err.Error(), "/retries: got string, want integer")
+}
+
+func TestWriter(t *testing.T) {
+	schema := lgschema.MustCompile([]byte(testSchema))
+
+	buf := &bytes.Buffer{}
+	var invalid int
+	w := lgschema.NewWriter(buf, schema, lgschema.WithOnInvalid(func(entry []byte, err error) {
+		invalid++
+	}))
+	log := zaplg.NewWith(w, "json", false, false, true, false, 0)
+	log.With("retries", "x").Debug("written anyway")
+	require.Equal(t, 1, invalid)
+	require.Contains(t, buf.String(), "written anyway")
+
+	buf.Reset()
+	w = lgschema.NewWriter(buf, schema, lgschema.WithStrict())
+	_, err := w.Write([]byte(`{"level":"debug"}` + "\n"))
+	require.ErrorIs(t, err, lgschema.ErrInvalidEntry)
+	require.Empty(t, buf.String())
+
+	_, err = w.Write([]byte(`{"level":"debug","message":"ok"}` + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Sync())
+}
@@ -0,0 +1,79 @@
+package lgschema
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidEntry is wrapped by the error returned by Writer.Write
+// in strict mode, if the entry is invalid.
+var ErrInvalidEntry = errors.New("lgschema: invalid entry")
+
+// WriterOption is a functional option for NewWriter.
+type WriterOption func(w *Writer)
+
+// WithStrict returns a WriterOption that causes an invalid entry to
+// be rejected: it is not written, and Write returns an error that
+// wraps ErrInvalidEntry.
+func WithStrict() WriterOption {
+	return func(w *Writer) {
+		w.strict = true
+	}
+}
+
+// WithOnInvalid returns a WriterOption that sets a func that is
+// invoked with each invalid entry, and its validation error. The
+// func must not retain entry.
+func WithOnInvalid(fn func(entry []byte, err error)) WriterOption {
+	return func(w *Writer) {
+		w.onInvalid = fn
+	}
+}
+
+// Writer is an io.Writer that validates each write, which must be a
+// single JSON entry (as is the case with zaplg), against a Schema,
+// before writing it to an underlying writer. By default, invalid
+// entries are written: configure WithOnInvalid or WithStrict to
+// act on them.
+type Writer struct {
+	w         io.Writer
+	schema    *Schema
+	strict    bool
+	onInvalid func(entry []byte, err error)
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer, schema *Schema, opts ...WriterOption) *Writer {
+	sw := &Writer{w: w, schema: schema}
+	for _, opt := range opts {
+		opt(sw)
+	}
+
+	return sw
+}
+
+// Write implements io.Writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	if err := w.schema.Validate(p); err != nil {
+		if w.onInvalid != nil {
+			w.onInvalid(p, err)
+		}
+
+		if w.strict {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidEntry, err)
+		}
+	}
+
+	return w.w.Write(p)
+}
+
+// Sync invokes the Sync method of the underlying
+// writer, if it has one.
+func (w *Writer) Sync() error {
+	if s, ok := w.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package lgsink
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// DefaultQueueSize is the default queue size for Async.
+const DefaultQueueSize = 1024
+
+// AsyncOption is a functional option for NewAsync.
+type AsyncOption func(a *Async)
+
+// WithQueueSize returns an AsyncOption that sets the number of
+// entries that can be queued. The default is DefaultQueueSize.
+func WithQueueSize(n int) AsyncOption {
+	return func(a *Async) {
+		if n > 0 {
+			a.queueSize = n
+		}
+	}
+}
+
+// WithOnDrop returns an AsyncOption that sets a func that is invoked
+// with each entry that is dropped because the queue is full. The
+// func is invoked synchronously by Write, and must not retain p.
+func WithOnDrop(fn func(p []byte)) AsyncOption {
+	return func(a *Async) {
+		a.onDrop = fn
+	}
+}
+
+// WithDropSummary returns an AsyncOption that, every interval, logs
+// a summary entry at WARN level to log if entries have been dropped
+// since the previous summary. The entry has fields "dropped" (the
+// count since the previous summary) and "dropped_total".
+func WithDropSummary(log lg.Log, interval time.Duration) AsyncOption {
+	return func(a *Async) {
+		if log != nil && interval > 0 {
+			a.summaryLog = log
+			a.summaryInterval = interval
+		}
+	}
+}
+
+// AsyncStats holds the counters of an Async.
+type AsyncStats struct {
+	// Enqueued is the number of entries added to the queue.
+	Enqueued uint64
+
+	// Written is the number of entries successfully
+	// written to the underlying writer.
+	Written uint64
+
+	// Dropped is the number of entries dropped
+	// because the queue was full.
+	Dropped uint64
+
+	// Errors is the number of entries for which the
+	// underlying writer returned an error.
+	Errors uint64
+}
+
+// Async is an io.Writer that writes to an underlying writer via a
+// bounded queue and a background goroutine, so that logging doesn't
+// block on slow output. If the queue is full, the entry is dropped:
+// Write never blocks. Drops are observable via Stats, WithOnDrop
+// and WithDropSummary. Async is safe for concurrent use.
+type Async struct {
+	w         io.Writer
+	queueSize int
+	onDrop    func(p []byte)
+
+	summaryLog      lg.Log
+	summaryInterval time.Duration
+
+	// mu guards closed: Write holds a read lock while it
+	// enqueues, so that no entry is enqueued after Close.
+	mu     sync.RWMutex
+	closed bool
+	queue  chan asyncItem
+	quit   chan struct{}
+	done   chan struct{}
+
+	// directMu serializes the writes made directly to
+	// the underlying writer after Close.
+	directMu sync.Mutex
+
+	stopSummary chan struct{}
+
+	enqueued, written, dropped, errors atomic.Uint64
+}
+
+// asyncItem is an entry, or, if sync is non-nil, a
+// marker that is closed when the preceding entries
+// have been written.
+type asyncItem struct {
+	p    []byte
+	sync chan struct{}
+}
+
+// NewAsync returns an Async that writes to w. Invoke
+// Close to drain the queue and stop the goroutine.
+func NewAsync(w io.Writer, opts ...AsyncOption) *Async {
+	a := &Async{w: w, queueSize: DefaultQueueSize}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	a.queue = make(chan asyncItem, a.queueSize)
+	a.quit = make(chan struct{})
+	a.done = make(chan struct{})
+	a.stopSummary = make(chan struct{})
+	go a.run()
+	if a.summaryLog != nil {
+		go a.summarize()
+	}
+
+	return a
+}
+
+// run writes the queued entries to the underlying writer. After
+// Close, it drains the queue and exits. The queue is never closed,
+// so that Sync can safely send to it without holding a.mu.
+func (a *Async) run() {
+	defer close(a.done)
+
+	for {
+		select {
+		case item := <-a.queue:
+			a.write(item)
+		case <-a.quit:
+			for {
+				select {
+				case item := <-a.queue:
+					a.write(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write writes item to the underlying writer.
+func (a *Async) write(item asyncItem) {
+	if item.sync != nil {
+		close(item.sync)
+		return
+	}
+
+	if _, err := a.w.Write(item.p); err != nil {
+		a.errors.Add(1)
+	} else {
+		a.written.Add(1)
+	}
+}
+
+// summarize periodically logs a summary of dropped entries. It runs
+// in its own goroutine, so that drops are reported even while the
+// underlying writer is blocked.
+func (a *Async) summarize() {
+	ticker := time.NewTicker(a.summaryInterval)
+	defer ticker.Stop()
+
+	var reported uint64
+	for {
+		select {
+		case <-a.stopSummary:
+			return
+		case <-ticker.C:
+			total := a.dropped.Load()
+			if total > reported {
+				a.summaryLog.With("dropped", total-reported).With("dropped_total", total).
+					Warn("lgsink: log entries dropped: async queue full")
+				reported = total
+			}
+		}
+	}
+}
+
+// Write implements io.Writer. If the queue is full, p is dropped,
+// and Write returns a nil error. After Close, Write writes
+// directly to the underlying writer, once the queue is drained.
+func (a *Async) Write(p []byte) (int, error) {
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		<-a.done
+
+		a.directMu.Lock()
+		defer a.directMu.Unlock()
+		return a.w.Write(p)
+	}
+	defer a.mu.RUnlock()
+
+	// The caller may reuse p, e.g. zap's pooled buffers.
+	item := asyncItem{p: append([]byte(nil), p...)}
+	select {
+	case a.queue <- item:
+		a.enqueued.Add(1)
+	default:
+		a.dropped.Add(1)
+		if a.onDrop != nil {
+			a.onDrop(p)
+		}
+	}
+
+	return len(p), nil
+}
+
+// Sync blocks until the entries queued before the call have been
+// written, and then invokes the Sync method of the underlying
+// writer, if it has one.
+func (a *Async) Sync() error {
+	// The marker is sent without holding a.mu, so that a full
+	// queue doesn't block Close, and thus Write.
+	marker := make(chan struct{})
+	select {
+	case a.queue <- asyncItem{sync: marker}:
+		// If run exits before reaching the marker, the
+		// preceding entries have been written anyway.
+		select {
+		case <-marker:
+		case <-a.done:
+		}
+	case <-a.done:
+	}
+
+	if s, ok := a.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Stats returns the counters of a.
+func (a *Async) Stats() AsyncStats {
+	return AsyncStats{
+		Enqueued: a.enqueued.Load(),
+		Written:  a.written.Load(),
+		Dropped:  a.dropped.Load(),
+		Errors:   a.errors.Load(),
+	}
+}
+
+// Close writes the queued entries, and stops the background
+// goroutine. It does not close the underlying writer.
+func (a *Async) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.quit)
+	close(a.stopSummary)
+	a.mu.Unlock()
+
+	<-a.done
+	return nil
+}
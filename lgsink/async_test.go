@@ -0,0 +1,165 @@
+package lgsink_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgsink"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// blockWriter is an io.Writer that blocks until unblocked.
+// Channel entered is closed when Write is first invoked.
+type blockWriter struct {
+	countWriter
+	entered     chan struct{}
+	enteredOnce sync.Once
+	unblock     chan struct{}
+	once        sync.Once
+}
+
+func newBlockWriter() *blockWriter {
+	return &blockWriter{entered: make(chan struct{}), unblock: make(chan struct{})}
+}
+
+func (w *blockWriter) Write(p []byte) (int, error) {
+	w.enteredOnce.Do(func() { close(w.entered) })
+	<-w.unblock
+	return w.countWriter.Write(p)
+}
+
+func (w *blockWriter) release() {
+	w.once.Do(func() { close(w.unblock) })
+}
+
+func TestAsync(t *testing.T) {
+	cw := &countWriter{}
+	a := lgsink.NewAsync(cw)
+	log := zaplg.NewWith(a, "json", false, false, true, false, 0)
+
+	for i := 0; i < 100; i++ {
+		log.Debugf("msg %d", i)
+	}
+
+	require.NoError(t, log.Sync())
+	s, _, syncs := cw.stats()
+	require.Equal(t, 100, bytes.Count([]byte(s), []byte("\n")))
+	require.Equal(t, 1, syncs)
+	require.Equal(t, lgsink.AsyncStats{Enqueued: 100, Written: 100}, a.Stats())
+
+	require.NoError(t, a.Close())
+	require.NoError(t, a.Close())
+	log.Debug("after close")
+	s, _, _ = cw.stats()
+	require.Contains(t, s, "after close")
+}
+
+func TestAsync_Drop(t *testing.T) {
+	bw := newBlockWriter()
+	defer bw.release()
+
+	var dropped []string
+	a := lgsink.NewAsync(bw, lgsink.WithQueueSize(2),
+		lgsink.WithOnDrop(func(p []byte) { dropped = append(dropped, string(p)) }))
+
+	// The first entry is taken by the goroutine, which then blocks;
+	// the next two fill the queue; the remainder are dropped.
+	_, err := a.Write([]byte("0"))
+	require.NoError(t, err)
+	<-bw.entered
+
+	for _, s := range []string{"1", "2", "3", "4"} {
+		_, err = a.Write([]byte(s))
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"3", "4"}, dropped)
+	bw.release()
+	require.NoError(t, a.Close())
+	require.Equal(t, lgsink.AsyncStats{Enqueued: 3, Written: 3, Dropped: 2}, a.Stats())
+}
+
+func TestAsync_DropSummary(t *testing.T) {
+	bw := newBlockWriter()
+	defer bw.release()
+
+	summaryBuf := &syncBuffer{}
+	summaryLog := zaplg.NewWith(summaryBuf, "json", false, false, true, false, 0)
+	a := lgsink.NewAsync(bw, lgsink.WithQueueSize(1),
+		lgsink.WithDropSummary(summaryLog, 10*time.Millisecond))
+
+	for i := 0; i < 10; i++ {
+		_, _ = a.Write([]byte("x"))
+	}
+
+	require.Eventually(t, func() bool {
+		return bytes.Contains(summaryBuf.Bytes(), []byte(`"dropped_total"`))
+	}, time.Second, 5*time.Millisecond)
+	require.Contains(t, summaryBuf.String(), `"level":"warn"`)
+
+	bw.release()
+	require.NoError(t, a.Close())
+}
+
+// syncBuffer is a concurrency-safe bytes.Buffer.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func (b *syncBuffer) String() string {
+	return string(b.Bytes())
+}
+
+func TestAsync_WriteDuringClose(t *testing.T) {
+	bw := newBlockWriter()
+	a := lgsink.NewAsync(bw, lgsink.WithQueueSize(1))
+
+	_, _ = a.Write([]byte("a\n"))
+	<-bw.entered // run is blocked writing "a"
+	_, _ = a.Write([]byte("b\n"))
+
+	// Sync blocks on the full queue, but must not block Close or Write.
+	synced := make(chan struct{})
+	go func() {
+		_ = a.Sync()
+		close(synced)
+	}()
+
+	closed := make(chan struct{})
+	go func() {
+		_ = a.Close()
+		close(closed)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	wrote := make(chan struct{})
+	go func() {
+		_, _ = a.Write([]byte("c\n"))
+		close(wrote)
+	}()
+
+	bw.release()
+	<-closed
+	<-wrote
+	<-synced
+
+	s, _, _ := bw.stats()
+	require.Equal(t, "a\nb\nc\n", s)
+}
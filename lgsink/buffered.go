@@ -0,0 +1,146 @@
+package lgsink
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+)
+
+// Default values for Buffered.
+const (
+	DefaultBufferSize    = 256 * 1024
+	DefaultFlushInterval = time.Second
+)
+
+// BufferedOption is a functional option for NewBuffered.
+type BufferedOption func(b *Buffered)
+
+// WithBufferSize returns a BufferedOption that sets the buffer size.
+// When the buffer is full, it is flushed. The default
+// is DefaultBufferSize.
+func WithBufferSize(size int) BufferedOption {
+	return func(b *Buffered) {
+		if size > 0 {
+			b.size = size
+		}
+	}
+}
+
+// WithFlushInterval returns a BufferedOption that sets the interval
+// at which the buffer is periodically flushed. If d is zero, the
+// buffer is flushed only when full, or when Sync or Close is invoked.
+// The default is DefaultFlushInterval.
+func WithFlushInterval(d time.Duration) BufferedOption {
+	return func(b *Buffered) {
+		if d >= 0 {
+			b.interval = d
+		}
+	}
+}
+
+// Buffered is an io.Writer that batches writes to an underlying writer,
+// greatly reducing the number of syscalls for high-volume logging.
+// Buffered entries are flushed when the buffer is full, periodically,
+// and when Sync or Close is invoked. Each Write is passed to the buffer
+// whole, so entries from concurrent writers are not interleaved.
+// Buffered is safe for concurrent use.
+type Buffered struct {
+	mu       sync.Mutex
+	w        io.Writer
+	bw       *bufio.Writer
+	size     int
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	closed   bool
+}
+
+// NewBuffered returns a Buffered that writes to w. Invoke Close
+// to stop the periodic flush and flush any buffered entries.
+func NewBuffered(w io.Writer, opts ...BufferedOption) *Buffered {
+	b := &Buffered{w: w, size: DefaultBufferSize, interval: DefaultFlushInterval}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	b.bw = bufio.NewWriterSize(w, b.size)
+	if b.interval > 0 {
+		b.stop = make(chan struct{})
+		b.done = make(chan struct{})
+		go b.flushLoop()
+	}
+
+	return b
+}
+
+func (b *Buffered) flushLoop() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			// An error is retained by the bufio.Writer, and
+			// returned by subsequent calls to Write or Sync.
+			_ = b.bw.Flush()
+			b.mu.Unlock()
+		}
+	}
+}
+
+// Write implements io.Writer. After Close, Write
+// writes directly to the underlying writer.
+func (b *Buffered) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return b.w.Write(p)
+	}
+
+	return b.bw.Write(p)
+}
+
+// Sync flushes buffered entries, and then invokes the Sync
+// method of the underlying writer, if it has one (e.g. *os.File).
+func (b *Buffered) Sync() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.bw.Flush(); err != nil {
+		return err
+	}
+
+	if s, ok := b.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Close stops the periodic flush, and flushes buffered
+// entries. It does not close the underlying writer.
+func (b *Buffered) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	if b.stop != nil {
+		close(b.stop)
+		<-b.done
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.bw.Flush()
+}
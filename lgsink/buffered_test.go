@@ -0,0 +1,117 @@
+package lgsink_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgsink"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// countWriter is a concurrency-safe io.Writer that
+// counts the calls to Write and Sync.
+type countWriter struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+	syncs  int
+	err    error
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.writes++
+	return w.buf.Write(p)
+}
+
+func (w *countWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.syncs++
+	return nil
+}
+
+func (w *countWriter) stats() (s string, writes, syncs int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String(), w.writes, w.syncs
+}
+
+func TestBuffered(t *testing.T) {
+	cw := &countWriter{}
+	b := lgsink.NewBuffered(cw, lgsink.WithFlushInterval(0))
+	log := zaplg.NewWith(b, "json", false, false, true, false, 0)
+
+	for i := 0; i < 100; i++ {
+		log.Debugf("msg %d", i)
+	}
+
+	s, writes, _ := cw.stats()
+	require.Empty(t, s)
+	require.Zero(t, writes)
+
+	require.NoError(t, log.Sync())
+	s, writes, syncs := cw.stats()
+	require.Equal(t, 100, bytes.Count([]byte(s), []byte("\n")))
+	require.Equal(t, 1, writes)
+	require.Equal(t, 1, syncs)
+
+	require.NoError(t, b.Close())
+	require.NoError(t, b.Close())
+
+	// After Close, writes go directly to the underlying writer.
+	log.Debug("after close")
+	s, _, _ = cw.stats()
+	require.Contains(t, s, "after close")
+}
+
+func TestBuffered_Size(t *testing.T) {
+	cw := &countWriter{}
+	b := lgsink.NewBuffered(cw, lgsink.WithBufferSize(64), lgsink.WithFlushInterval(0))
+	defer b.Close()
+
+	for i := 0; i < 10; i++ {
+		_, err := b.Write([]byte("0123456789012345678901234567890\n"))
+		require.NoError(t, err)
+	}
+
+	// 320 bytes written: four full buffers are flushed,
+	// and the final 64 bytes remain buffered.
+	_, writes, _ := cw.stats()
+	require.Equal(t, 4, writes, "buffer should be flushed when full")
+}
+
+func TestBuffered_Interval(t *testing.T) {
+	cw := &countWriter{}
+	b := lgsink.NewBuffered(cw, lgsink.WithFlushInterval(10*time.Millisecond))
+	defer b.Close()
+
+	_, err := b.Write([]byte("hello\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		s, _, _ := cw.stats()
+		return s == "hello\n"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBuffered_Error(t *testing.T) {
+	wantErr := errors.New("disk full")
+	cw := &countWriter{err: wantErr}
+	b := lgsink.NewBuffered(cw, lgsink.WithFlushInterval(0))
+
+	_, err := b.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	require.ErrorIs(t, b.Sync(), wantErr)
+	require.ErrorIs(t, b.Close(), wantErr)
+}
@@ -0,0 +1,287 @@
+package lgsink
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// encryptedMagic begins the header of an encrypted stream.
+const encryptedMagic = "LGEN\x01"
+
+// maxEncryptedRecord is the maximum size of a record accepted by Decrypt.
+// It is less than the size prefix that encryptedMagic would be read as,
+// so that Decrypt can distinguish a record from the next stream's header.
+const maxEncryptedRecord = 64 << 20
+
+// The additional data of entry records and of the final record that
+// Close writes, so that a final record can't be forged from an entry.
+var (
+	entryAAD = []byte{0}
+	finalAAD = []byte{1}
+)
+
+// ErrDecrypt is returned by Decrypt if the stream can't be decrypted.
+var ErrDecrypt = errors.New("lgsink: failed to decrypt")
+
+// Encrypted is an io.Writer that encrypts each entry before writing it
+// to an underlying writer, for capturing logs that contain sensitive
+// data. Only the holder of the private key corresponding to the public
+// key passed to NewEncrypted can read the entries, via Decrypt.
+//
+// A random AES-256 key is generated for each Encrypted, and written at
+// the start of the stream, encrypted with RSA-OAEP (SHA-256). Each Write
+// is then written as a length-prefixed AES-GCM record, whose nonce is
+// the record's sequence number, so that records can't be reordered.
+// Close writes a final record, so that Decrypt can detect a stream
+// that is truncated at a record boundary. Encrypted is safe for
+// concurrent use.
+type Encrypted struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pub     *rsa.PublicKey
+	aead    cipher.AEAD
+	header  []byte
+	seq     uint64
+	started bool
+	closed  bool
+}
+
+// NewEncrypted returns an Encrypted that writes to w, encrypting
+// entries such that they can be decrypted with the private key
+// corresponding to pub.
+func NewEncrypted(w io.Writer, pub *rsa.PublicKey) (*Encrypted, error) {
+	e := &Encrypted{w: w, pub: pub}
+	if err := e.newStream(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// newStream generates a new key, and resets e
+// to begin a new stream with the next Write.
+func (e *Encrypted) newStream() error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+
+	wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, e.pub, key, nil)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, 0, len(encryptedMagic)+2+len(wrapped))
+	header = append(header, encryptedMagic...)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(wrapped)))
+	header = append(header, wrapped...)
+
+	e.aead, e.header, e.seq, e.started, e.closed = aead, header, 0, false, false
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// nonce returns the nonce for record seq.
+func nonce(aead cipher.AEAD, seq uint64) []byte {
+	n := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(n[len(n)-8:], seq)
+	return n
+}
+
+// Write implements io.Writer. The header is written with the
+// first entry. After Close, Write begins a new stream, with a new
+// key, which Decrypt decrypts following the closed stream.
+func (e *Encrypted) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(p) == 0 {
+		// An empty entry would be indistinguishable
+		// by size from the final record.
+		return 0, nil
+	}
+
+	if e.closed {
+		if err := e.newStream(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := e.writeRecord(p, entryAAD); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// writeRecord writes p as a record, preceded
+// by the header if this is the first record.
+func (e *Encrypted) writeRecord(p, aad []byte) error {
+	out := make([]byte, 0, len(e.header)+4+len(p)+e.aead.Overhead())
+	if !e.started {
+		out = append(out, e.header...)
+	}
+
+	out = binary.BigEndian.AppendUint32(out, uint32(len(p)+e.aead.Overhead()))
+	out = e.aead.Seal(out, nonce(e.aead, e.seq), p, aad)
+	if _, err := e.w.Write(out); err != nil {
+		return err
+	}
+
+	e.started = true
+	e.seq++
+	return nil
+}
+
+// Sync invokes the Sync method of the underlying
+// writer, if it has one (e.g. *os.File).
+func (e *Encrypted) Sync() error {
+	if s, ok := e.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Close writes the final record of the stream, which marks the stream
+// as complete. It does not close the underlying writer.
+func (e *Encrypted) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return nil
+	}
+
+	if err := e.writeRecord(nil, finalAAD); err != nil {
+		return err
+	}
+
+	e.closed = true
+	return nil
+}
+
+// Decrypt writes the decrypted entries of the streams read from src,
+// as written by Encrypted, to dst. Multiple streams, such as those
+// appended to a file by successive processes, are decrypted in turn.
+//
+// Decrypt returns an error wrapping ErrDecrypt if a stream is truncated
+// or has been tampered with, or if priv is not the correct key. Entries
+// decrypted before the error are written to dst. A stream that lacks
+// its final record, because it was not closed (see Encrypted.Close) or
+// was truncated at a record boundary, does not prevent the decryption
+// of the streams that follow it: the error is returned after them.
+func Decrypt(dst io.Writer, src io.Reader, priv *rsa.PrivateKey) error {
+	r := bufio.NewReader(src)
+
+	var incomplete error
+	for stream := 0; ; stream++ {
+		if _, err := r.Peek(1); errors.Is(err, io.EOF) {
+			return incomplete
+		}
+
+		closed, err := decryptStream(dst, r, priv, stream)
+		if err != nil {
+			return err
+		}
+
+		if !closed && incomplete == nil {
+			incomplete = fmt.Errorf("%w: stream %d: missing final record", ErrDecrypt, stream)
+		}
+	}
+}
+
+// decryptStream decrypts the stream that begins at the head of r,
+// writing its entries to dst. It returns when it reads the stream's
+// final record, the next stream's header, or EOF. The returned bool
+// is true if the stream's final record was read.
+func decryptStream(dst io.Writer, r *bufio.Reader, priv *rsa.PrivateKey, stream int) (closed bool, err error) {
+	fail := func(format string, a ...any) error {
+		return fmt.Errorf("%w: stream %d: %s", ErrDecrypt, stream, fmt.Sprintf(format, a...))
+	}
+
+	header := make([]byte, len(encryptedMagic)+2)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return false, fail("read header: %v", err)
+	}
+
+	if string(header[:len(encryptedMagic)]) != encryptedMagic {
+		return false, fail("invalid header")
+	}
+
+	wrapped := make([]byte, binary.BigEndian.Uint16(header[len(encryptedMagic):]))
+	if _, err = io.ReadFull(r, wrapped); err != nil {
+		return false, fail("read key: %v", err)
+	}
+
+	key, err := rsa.DecryptOAEP(sha256.New(), nil, priv, wrapped, nil)
+	if err != nil {
+		return false, fail("decrypt key: %v", err)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return false, err
+	}
+
+	var size [4]byte
+	for seq := uint64(0); ; seq++ {
+		if next, _ := r.Peek(len(encryptedMagic)); len(next) == 0 || string(next) == encryptedMagic {
+			return false, nil // EOF, or the next stream.
+		}
+
+		if _, err = io.ReadFull(r, size[:]); err != nil {
+			return false, fail("record %d: %v", seq, err)
+		}
+
+		n := binary.BigEndian.Uint32(size[:])
+		if n > maxEncryptedRecord {
+			return false, fail("record %d: size %d exceeds max", seq, n)
+		}
+
+		rec := make([]byte, n)
+		if _, err = io.ReadFull(r, rec); err != nil {
+			return false, fail("record %d: %v", seq, err)
+		}
+
+		aad := entryAAD
+		if int(n) == aead.Overhead() {
+			aad = finalAAD
+		}
+
+		plain, err := aead.Open(rec[:0], nonce(aead, seq), rec, aad)
+		if err != nil {
+			return false, fail("record %d: %v", seq, err)
+		}
+
+		if int(n) == aead.Overhead() {
+			return true, nil
+		}
+
+		if _, err = dst.Write(plain); err != nil {
+			return false, err
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package lgsink_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgsink"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestEncrypted(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	sink, err := lgsink.NewEncrypted(buf, &priv.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, sink.Sync())
+
+	log := zaplg.NewWith(sink, "text", false, false, false, false, 0)
+	log.Debug("ssn=123-45-6789")
+	log.Debug("two")
+	require.NotContains(t, buf.String(), "123-45-6789")
+	require.NoError(t, sink.Close())
+	require.NoError(t, sink.Close())
+
+	got := &bytes.Buffer{}
+	require.NoError(t, lgsink.Decrypt(got, bytes.NewReader(buf.Bytes()), priv))
+	require.Equal(t, "ssn=123-45-6789\ntwo\n", got.String())
+
+	// Empty stream.
+	require.NoError(t, lgsink.Decrypt(got, &bytes.Buffer{}, priv))
+
+	// Wrong key.
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	require.ErrorIs(t, lgsink.Decrypt(got, bytes.NewReader(buf.Bytes()), other), lgsink.ErrDecrypt)
+
+	// Tampered final record.
+	b := append([]byte(nil), buf.Bytes()...)
+	b[len(b)-1] ^= 1
+	got.Reset()
+	require.ErrorIs(t, lgsink.Decrypt(got, bytes.NewReader(b), priv), lgsink.ErrDecrypt)
+	require.Equal(t, "ssn=123-45-6789\ntwo\n", got.String())
+
+	// Truncated mid-record.
+	got.Reset()
+	require.ErrorIs(t, lgsink.Decrypt(got, bytes.NewReader(buf.Bytes()[:buf.Len()-3]), priv), lgsink.ErrDecrypt)
+
+	// Truncated at a record boundary: the final record
+	// is 4 bytes of size, plus the GCM tag.
+	got.Reset()
+	require.ErrorIs(t, lgsink.Decrypt(got, bytes.NewReader(buf.Bytes()[:buf.Len()-20]), priv), lgsink.ErrDecrypt)
+	require.Equal(t, "ssn=123-45-6789\ntwo\n", got.String())
+}
+
+func TestEncrypted_streams(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// Two streams appended to the same file, as by successive
+	// processes, the first of which did not close its stream.
+	buf := &bytes.Buffer{}
+	sink, err := lgsink.NewEncrypted(buf, &priv.PublicKey)
+	require.NoError(t, err)
+	_, err = sink.Write([]byte("one\n"))
+	require.NoError(t, err)
+
+	sink, err = lgsink.NewEncrypted(buf, &priv.PublicKey)
+	require.NoError(t, err)
+	_, err = sink.Write([]byte("two\n"))
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	got := &bytes.Buffer{}
+	require.ErrorIs(t, lgsink.Decrypt(got, bytes.NewReader(buf.Bytes()), priv), lgsink.ErrDecrypt)
+	require.Equal(t, "one\ntwo\n", got.String())
+
+	// Write after Close begins a new stream.
+	_, err = sink.Write([]byte("three\n"))
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	got.Reset()
+	require.ErrorIs(t, lgsink.Decrypt(got, bytes.NewReader(buf.Bytes()), priv), lgsink.ErrDecrypt)
+	require.Equal(t, "one\ntwo\nthree\n", got.String())
+
+	// All streams closed.
+	buf.Reset()
+	for _, s := range []string{"a\n", "b\n"} {
+		sink, err = lgsink.NewEncrypted(buf, &priv.PublicKey)
+		require.NoError(t, err)
+		_, err = sink.Write([]byte(s))
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+	}
+
+	got.Reset()
+	require.NoError(t, lgsink.Decrypt(got, bytes.NewReader(buf.Bytes()), priv))
+	require.Equal(t, "a\nb\n", got.String())
+}
@@ -0,0 +1,132 @@
+package lgsink
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// FileOption is a functional option for NewFile.
+type FileOption func(f *File)
+
+// WithFileMode returns a FileOption that sets the permissions with
+// which the file is created. The default is 0o644.
+func WithFileMode(perm os.FileMode) FileOption {
+	return func(f *File) {
+		f.perm = perm
+	}
+}
+
+// WithFileLock returns a FileOption that causes File to take an
+// exclusive advisory lock (flock) on the file for each write, so
+// that its writes are not interleaved with those of other processes
+// that also lock the file. Advisory locking is not supported on
+// non-unix platforms, where the option has no effect.
+func WithFileLock() FileOption {
+	return func(f *File) {
+		f.lock = true
+	}
+}
+
+// File is an io.Writer that appends to a log file, which multiple
+// processes (e.g. of the same service) can share: the file is opened
+// with O_APPEND, and each entry is written with a single write. Most
+// local filesystems don't interleave such writes, but POSIX doesn't
+// guarantee it (nor do network filesystems such as NFS): use
+// WithFileLock in each process to guarantee that the entries of one
+// process are not interleaved with partial lines of another.
+//
+// For integration with external rotation tools such as logrotate,
+// File can reopen its path (see Reopen and ReopenOnSIGHUP), so that
+// after the file is moved, entries are written to a new file at the
+// path. File is safe for concurrent use.
+type File struct {
+	mu   sync.Mutex
+	path string
+	perm os.FileMode
+	lock bool
+	f    *os.File
+}
+
+// NewFile returns a File that appends to the file at path, which is
+// created if it doesn't exist. Invoke Close to close the file.
+func NewFile(path string, opts ...FileOption) (*File, error) {
+	f := &File{path: path, perm: 0o644}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	var err error
+	if f.f, err = f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// open opens the file at f.path for appending.
+func (f *File) open() (*os.File, error) {
+	return os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, f.perm)
+}
+
+// Reopen closes the file and reopens its path, creating the file if
+// it doesn't exist. If the path can't be opened, the current file is
+// retained, and an error is returned.
+func (f *File) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	nf, err := f.open()
+	if err != nil {
+		return err
+	}
+
+	old := f.f
+	f.f = nf
+	return old.Close()
+}
+
+// ReopenOnSIGHUP arranges for the file to be reopened (see Reopen)
+// on receipt of SIGHUP, as is conventional for use with logrotate.
+// If reopening fails, the error is passed to onErr, if non-nil. The
+// returned func stops the handling of SIGHUP. On platforms without
+// SIGHUP, such as Windows and js/wasm, ReopenOnSIGHUP is a no-op.
+func (f *File) ReopenOnSIGHUP(onErr func(error)) (stop func()) {
+	return reopenOnSIGHUP(f, onErr)
+}
+
+// Write implements io.Writer. Each invocation
+// of Write should be a complete entry.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.lock {
+		if err := lockFile(f.f); err != nil {
+			return 0, err
+		}
+		defer func() { _ = unlockFile(f.f) }()
+	}
+
+	n, err := f.f.Write(p)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
+
+// Sync commits the file's contents to stable storage.
+func (f *File) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.f.Sync()
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.f.Close()
+}
@@ -0,0 +1,22 @@
+//go:build !unix
+
+package lgsink
+
+import "os"
+
+// lockFile is a no-op: advisory locking is
+// not supported on this platform.
+func lockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op.
+func unlockFile(*os.File) error {
+	return nil
+}
+
+// reopenOnSIGHUP is a no-op: SIGHUP is not
+// received on this platform.
+func reopenOnSIGHUP(*File, func(error)) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,110 @@
+package lgsink_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgsink"
+)
+
+func TestFile_Shared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	// Each File simulates a separate process sharing the log file.
+	const writers, entries, large = 4, 100, 64 << 10
+	files := make([]*lgsink.File, writers)
+	for i := range files {
+		f, err := lgsink.NewFile(path, lgsink.WithFileLock())
+		require.NoError(t, err)
+		files[i] = f
+	}
+
+	wg := &sync.WaitGroup{}
+	for i, f := range files {
+		wg.Add(1)
+		go func(i int, f *lgsink.File) {
+			defer wg.Done()
+			for j := 0; j < entries; j++ {
+				// Alternate small and large entries.
+				pad := strings.Repeat(fmt.Sprint(i), 10+(j%2)*large)
+				_, err := fmt.Fprintf(f, "%d %d %s\n", i, j, pad)
+				require.NoError(t, err)
+			}
+		}(i, f)
+	}
+	wg.Wait()
+
+	for _, f := range files {
+		require.NoError(t, f.Sync())
+		require.NoError(t, f.Close())
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var count int
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, 2*large)
+	for sc.Scan() {
+		var i, j int
+		var pad string
+		_, err = fmt.Sscanf(sc.Text(), "%d %d %s", &i, &j, &pad)
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat(fmt.Sprint(i), 10+(j%2)*large), pad)
+		count++
+	}
+	require.NoError(t, sc.Err())
+	require.Equal(t, writers*entries, count)
+}
+
+func TestFile_Mode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := lgsink.NewFile(path, lgsink.WithFileMode(0o600))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+}
+
+func TestFile_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := lgsink.NewFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = f.Write([]byte("before\n"))
+	require.NoError(t, err)
+
+	// Simulate rotation.
+	require.NoError(t, os.Rename(path, path+".1"))
+	_, err = f.Write([]byte("rotating\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Reopen())
+	_, err = f.Write([]byte("after\n"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "before\nrotating\n", string(data))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "after\n", string(data))
+
+	// If the path can't be opened, the current file is retained.
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, os.Mkdir(path, 0o700))
+	require.Error(t, f.Reopen())
+	_, err = f.Write([]byte("retained\n"))
+	require.NoError(t, err)
+}
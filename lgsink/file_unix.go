@@ -0,0 +1,53 @@
+//go:build unix
+
+package lgsink
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// lockFile takes an exclusive advisory lock on f, blocking
+// until the lock is available.
+func lockFile(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR { //nolint:errorlint // syscall errors are compared by identity
+			return err
+		}
+	}
+}
+
+// unlockFile releases the lock taken by lockFile.
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
+
+// reopenOnSIGHUP implements File.ReopenOnSIGHUP.
+func reopenOnSIGHUP(f *File, onErr func(error)) (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigs:
+				if err := f.Reopen(); err != nil && onErr != nil {
+					onErr(err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+		})
+	}
+}
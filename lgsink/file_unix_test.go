@@ -0,0 +1,39 @@
+//go:build unix
+
+package lgsink_test
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgsink"
+)
+
+func TestFile_ReopenOnSIGHUP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := lgsink.NewFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	stop := f.ReopenOnSIGHUP(func(err error) { t.Error(err) })
+	defer stop()
+
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = f.Write([]byte("after\n"))
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "after\n", string(data))
+}
@@ -0,0 +1,159 @@
+package lgsink
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultGzipFlushInterval is the default flush interval of Gzip.
+const DefaultGzipFlushInterval = time.Second
+
+// GzipOption is a functional option for NewGzip.
+type GzipOption func(g *Gzip)
+
+// WithGzipLevel returns a GzipOption that sets the compression level,
+// e.g. gzip.BestSpeed. The default is gzip.DefaultCompression.
+func WithGzipLevel(level int) GzipOption {
+	return func(g *Gzip) {
+		g.level = level
+	}
+}
+
+// WithGzipFlushInterval returns a GzipOption that sets the interval at
+// which flush points are written to the compressed stream. If d is zero,
+// flush points are written only when Sync is invoked. The default is
+// DefaultGzipFlushInterval.
+func WithGzipFlushInterval(d time.Duration) GzipOption {
+	return func(g *Gzip) {
+		if d >= 0 {
+			g.interval = d
+		}
+	}
+}
+
+// Gzip is an io.Writer that gzip-compresses the stream written to an
+// underlying writer. Flush points are written periodically, and when
+// Sync is invoked, so that a reader tailing the compressed stream
+// (e.g. "tail -f app.log.gz | zcat") can decode all entries written
+// before the flush point. Gzip is safe for concurrent use.
+type Gzip struct {
+	mu       sync.Mutex
+	w        io.Writer
+	zw       *gzip.Writer
+	level    int
+	interval time.Duration
+	dirty    bool
+	stop     chan struct{}
+	done     chan struct{}
+	closed   bool
+}
+
+// NewGzip returns a Gzip that writes to w. Invoke Close to stop
+// the periodic flush, and to complete the compressed stream.
+// NewGzip panics if the level set via WithGzipLevel is invalid.
+func NewGzip(w io.Writer, opts ...GzipOption) *Gzip {
+	g := &Gzip{w: w, level: gzip.DefaultCompression, interval: DefaultGzipFlushInterval}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	zw, err := gzip.NewWriterLevel(w, g.level)
+	if err != nil {
+		panic(err)
+	}
+
+	g.zw = zw
+	if g.interval > 0 {
+		g.stop = make(chan struct{})
+		g.done = make(chan struct{})
+		go g.flushLoop()
+	}
+
+	return g
+}
+
+func (g *Gzip) flushLoop() {
+	defer close(g.done)
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+			g.mu.Lock()
+			if g.dirty {
+				// An error is returned by subsequent calls to Write or Sync.
+				_ = g.zw.Flush()
+				g.dirty = false
+			}
+			g.mu.Unlock()
+		}
+	}
+}
+
+// Write implements io.Writer. After Close, each Write is written
+// to the underlying writer as a complete gzip member: a stream of
+// concatenated members is valid gzip, so entries logged after
+// Close are not lost.
+func (g *Gzip) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		g.zw.Reset(g.w)
+		n, err := g.zw.Write(p)
+		if err != nil {
+			return n, err
+		}
+		return n, g.zw.Close()
+	}
+
+	g.dirty = true
+	return g.zw.Write(p)
+}
+
+// Sync writes a flush point, and then invokes the Sync method
+// of the underlying writer, if it has one (e.g. *os.File).
+func (g *Gzip) Sync() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.closed {
+		if err := g.zw.Flush(); err != nil {
+			return err
+		}
+		g.dirty = false
+	}
+
+	if s, ok := g.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Close stops the periodic flush, and completes the compressed
+// stream. It does not close the underlying writer.
+func (g *Gzip) Close() error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil
+	}
+	g.closed = true
+	g.mu.Unlock()
+
+	if g.stop != nil {
+		close(g.stop)
+		<-g.done
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.zw.Close()
+}
@@ -0,0 +1,89 @@
+package lgsink_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgsink"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// gunzip returns the decompressed contents of b, which
+// must be a complete gzip stream.
+func gunzip(t *testing.T, b []byte) string {
+	t.Helper()
+
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	require.NoError(t, err)
+	got, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	return string(got)
+}
+
+// written returns the bytes written to w.
+func written(w *countWriter) []byte {
+	s, _, _ := w.stats()
+	return []byte(s)
+}
+
+func TestGzip(t *testing.T) {
+	w := &countWriter{}
+	sink := lgsink.NewGzip(w, lgsink.WithGzipFlushInterval(0))
+	log := zaplg.NewWith(sink, "text", false, false, false, false, 0)
+
+	log.Debug("one")
+	log.Debug("two")
+	require.NoError(t, sink.Sync())
+	_, _, syncs := w.stats()
+	require.Equal(t, 1, syncs)
+
+	// The stream is not complete, but a tailing reader can
+	// decode the entries written before the flush point.
+	zr, err := gzip.NewReader(bytes.NewReader(written(w)))
+	require.NoError(t, err)
+	got := make([]byte, len("one\ntwo\n"))
+	_, err = io.ReadFull(zr, got)
+	require.NoError(t, err)
+	require.Equal(t, "one\ntwo\n", string(got))
+
+	log.Debug("three")
+	require.NoError(t, sink.Close())
+	require.NoError(t, sink.Close())
+	require.Equal(t, "one\ntwo\nthree\n", gunzip(t, written(w)))
+
+	// Entries logged after Close are written as additional gzip members.
+	log.Debug("four")
+	log.Debug("five")
+	require.Equal(t, "one\ntwo\nthree\nfour\nfive\n", gunzip(t, written(w)))
+}
+
+func TestGzip_FlushInterval(t *testing.T) {
+	w := &countWriter{}
+	sink := lgsink.NewGzip(w, lgsink.WithGzipFlushInterval(10*time.Millisecond),
+		lgsink.WithGzipLevel(gzip.BestSpeed))
+	defer sink.Close()
+
+	_, err := sink.Write([]byte("hello\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		zr, err := gzip.NewReader(bytes.NewReader(written(w)))
+		if err != nil {
+			return false
+		}
+		got := make([]byte, len("hello\n"))
+		_, err = io.ReadFull(zr, got)
+		return err == nil && string(got) == "hello\n"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestGzip_InvalidLevel(t *testing.T) {
+	require.Panics(t, func() {
+		lgsink.NewGzip(io.Discard, lgsink.WithGzipLevel(42))
+	})
+}
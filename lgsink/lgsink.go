@@ -0,0 +1,15 @@
+// Package lgsink provides io.Writer sinks for use with log impls
+// such as zaplg, for example:
+//
+//	f, err := os.OpenFile("app.log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+//	// handle err
+//	sink := lgsink.NewBuffered(f)
+//	defer sink.Close()
+//	log := zaplg.NewWith(sink, "json", true, true, true, true, 0)
+//
+// Custom formats implemented via lgcore.Encoder can be written to
+// a sink using lgcore.New, or zaplg's WithEncoder option.
+//
+// The sinks implement Sync, so a log impl's Sync method (e.g. that
+// of zaplg.Log) flushes buffered entries.
+package lgsink
@@ -0,0 +1,281 @@
+package lgsink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Default values for Spool.
+const (
+	DefaultMaxSpoolSize  = 64 * 1024 * 1024
+	DefaultRetryInterval = 5 * time.Second
+)
+
+// ErrSpoolFull is returned by Spool.Write when an entry is dropped
+// because the remote writer is failing and the spool file is full.
+var ErrSpoolFull = errors.New("lgsink: spool full: entry dropped")
+
+// SpoolOption is a functional option for NewSpool.
+type SpoolOption func(s *Spool)
+
+// WithMaxSpoolSize returns a SpoolOption that sets the maximum size
+// in bytes of the spool file. The default is DefaultMaxSpoolSize.
+func WithMaxSpoolSize(size int64) SpoolOption {
+	return func(s *Spool) {
+		if size > 0 {
+			s.maxSize = size
+		}
+	}
+}
+
+// WithRetryInterval returns a SpoolOption that sets the minimum
+// interval between attempts to write to the remote writer after
+// a failure. Until the interval has elapsed, entries are written
+// directly to the spool. The default is DefaultRetryInterval.
+func WithRetryInterval(d time.Duration) SpoolOption {
+	return func(s *Spool) {
+		if d >= 0 {
+			s.retry = d
+		}
+	}
+}
+
+// frameMagic marks the start of each frame in the spool file.
+var frameMagic = []byte("LGSP")
+
+// frameHeaderLen is the length of a frame header: the
+// magic, the payload length, and the payload's CRC-32.
+const frameHeaderLen = 12
+
+// Spool is an io.Writer that writes to a remote writer, such as a
+// network connection or an HTTP log shipper, with a write-ahead
+// disk spool: when a write to the remote writer fails, the entry is
+// appended to a local spool file, and spooled entries are replayed,
+// in order, when the remote writer next succeeds. Entries may be
+// written more than once if replay is interrupted.
+//
+// Each spooled entry is framed with a checksum: on replay, corrupt
+// or truncated frames (e.g. due to a crash) are skipped. The spool
+// file persists across restarts, so entries spooled by a previous
+// process are also replayed. Spool is safe for concurrent use.
+type Spool struct {
+	mu       sync.Mutex
+	w        io.Writer
+	path     string
+	f        *os.File
+	size     int64
+	maxSize  int64
+	retry    time.Duration
+	failedAt time.Time
+	dropped  int64
+}
+
+// NewSpool returns a Spool that writes to w, spooling to the file at
+// path, which is created if it doesn't exist. Invoke Close to close
+// the spool file.
+func NewSpool(w io.Writer, path string, opts ...SpoolOption) (*Spool, error) {
+	s := &Spool{w: w, path: path, maxSize: DefaultMaxSpoolSize, retry: DefaultRetryInterval}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// open opens the spool file for appending.
+func (s *Spool) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	s.f, s.size = f, fi.Size()
+	return nil
+}
+
+// Write implements io.Writer. If the entry can't be written to the
+// remote writer, it is spooled, and Write returns a nil error. If
+// the spool is full, the entry is dropped and ErrSpoolFull is returned.
+func (s *Spool) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.retryDue() || (s.size > 0 && s.replay() != nil) {
+		return s.spool(p)
+	}
+
+	if _, err := s.w.Write(p); err != nil {
+		s.failedAt = time.Now()
+		return s.spool(p)
+	}
+
+	return len(p), nil
+}
+
+// Sync attempts to replay spooled entries, returning an error if
+// entries remain spooled. It then invokes the Sync method of the
+// remote writer, if it has one, and syncs the spool file.
+func (s *Spool) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.size > 0 {
+		err = s.replay()
+	}
+
+	if syncer, ok := s.w.(interface{ Sync() error }); ok && err == nil {
+		err = syncer.Sync()
+	}
+
+	if syncErr := s.f.Sync(); err == nil {
+		err = syncErr
+	}
+
+	return err
+}
+
+// Spooled returns the size in bytes of the spool file.
+func (s *Spool) Spooled() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// Dropped returns the number of entries dropped
+// because the spool was full.
+func (s *Spool) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Close closes the spool file. Spooled entries remain in the
+// file. It does not close the remote writer.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+func (s *Spool) retryDue() bool {
+	return s.failedAt.IsZero() || time.Since(s.failedAt) >= s.retry
+}
+
+// spool appends p to the spool file as a frame.
+func (s *Spool) spool(p []byte) (int, error) {
+	frameLen := int64(frameHeaderLen + len(p))
+	if s.size+frameLen > s.maxSize {
+		s.dropped++
+		return 0, ErrSpoolFull
+	}
+
+	frame := make([]byte, frameLen)
+	copy(frame, frameMagic)
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(p)))
+	binary.BigEndian.PutUint32(frame[8:12], crc32.ChecksumIEEE(p))
+	copy(frame[frameHeaderLen:], p)
+
+	n, err := s.f.Write(frame)
+	s.size += int64(n)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// replay writes the spooled entries to the remote writer. If a write
+// fails, the remaining entries are retained in the spool file.
+func (s *Spool) replay() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+
+	for pos := 0; pos < len(data); {
+		payload, n, ok := nextFrame(data[pos:])
+		if !ok {
+			break
+		}
+
+		if _, err = s.w.Write(payload); err != nil {
+			s.failedAt = time.Now()
+			if compactErr := s.compact(data[pos:]); compactErr != nil {
+				return compactErr
+			}
+			return err
+		}
+
+		pos += n
+	}
+
+	if err = s.f.Truncate(0); err != nil {
+		return err
+	}
+
+	s.size = 0
+	return nil
+}
+
+// compact replaces the spool file with one containing only rest.
+func (s *Spool) compact(rest []byte) error {
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, rest, 0o600); err != nil {
+		return err
+	}
+
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+// nextFrame returns the payload of the first valid frame in b, and
+// the number of bytes of b consumed. Invalid data preceding the
+// frame is skipped. If there's no valid frame, ok is false.
+func nextFrame(b []byte) (payload []byte, n int, ok bool) {
+	for off := 0; ; off++ {
+		i := bytes.Index(b[off:], frameMagic)
+		if i < 0 {
+			return nil, len(b), false
+		}
+
+		off += i
+		h := b[off:]
+		if len(h) < frameHeaderLen {
+			return nil, len(b), false
+		}
+
+		size := int64(binary.BigEndian.Uint32(h[4:8]))
+		if size <= int64(len(h)-frameHeaderLen) {
+			p := h[frameHeaderLen : frameHeaderLen+size]
+			if crc32.ChecksumIEEE(p) == binary.BigEndian.Uint32(h[8:12]) {
+				return p, off + frameHeaderLen + int(size), true
+			}
+		}
+
+		// Corrupt or truncated frame: resync at the next magic.
+	}
+}
@@ -0,0 +1,136 @@
+package lgsink_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgsink"
+)
+
+// remote is an io.Writer that fails while down is true.
+type remote struct {
+	mu   sync.Mutex
+	down bool
+	got  []string
+}
+
+func (r *remote) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.down {
+		return 0, errors.New("connection refused")
+	}
+
+	r.got = append(r.got, string(p))
+	return len(p), nil
+}
+
+func (r *remote) setDown(down bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.down = down
+}
+
+func write(t *testing.T, s *lgsink.Spool, entries ...string) {
+	t.Helper()
+	for _, e := range entries {
+		_, err := s.Write([]byte(e))
+		require.NoError(t, err)
+	}
+}
+
+func TestSpool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool")
+	r := &remote{}
+	s, err := lgsink.NewSpool(r, path, lgsink.WithRetryInterval(0))
+	require.NoError(t, err)
+	defer s.Close()
+
+	write(t, s, "a\n")
+	r.setDown(true)
+	write(t, s, "b\n", "c\n")
+	require.NotZero(t, s.Spooled())
+	require.Equal(t, []string{"a\n"}, r.got)
+
+	r.setDown(false)
+	write(t, s, "d\n")
+	require.Equal(t, []string{"a\n", "b\n", "c\n", "d\n"}, r.got)
+	require.Zero(t, s.Spooled())
+}
+
+func TestSpool_Restart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool")
+	r := &remote{down: true}
+	s, err := lgsink.NewSpool(r, path)
+	require.NoError(t, err)
+	write(t, s, "a\n", "b\n")
+	require.NoError(t, s.Close())
+
+	// Simulate a crash during a write: append a truncated frame.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString("LGSP\x00\x00")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	r.setDown(false)
+	s, err = lgsink.NewSpool(r, path)
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.Sync())
+	require.Equal(t, []string{"a\n", "b\n"}, r.got)
+	require.Zero(t, s.Spooled())
+}
+
+func TestSpool_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool")
+	r := &remote{down: true}
+	s, err := lgsink.NewSpool(r, path)
+	require.NoError(t, err)
+	write(t, s, "aaaa\n", "bbbb\n", "cccc\n")
+	require.NoError(t, s.Close())
+
+	// Corrupt the payload of the second frame.
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	i := strings.Index(string(data), "bbbb")
+	data[i] = 'X'
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	r.setDown(false)
+	s, err = lgsink.NewSpool(r, path)
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.Sync())
+	require.Equal(t, []string{"aaaa\n", "cccc\n"}, r.got)
+}
+
+func TestSpool_Full(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool")
+	r := &remote{down: true}
+	s, err := lgsink.NewSpool(r, path, lgsink.WithMaxSpoolSize(40))
+	require.NoError(t, err)
+	defer s.Close()
+
+	write(t, s, "0123456789\n") // 12 byte header + 11 bytes
+	_, err = s.Write([]byte("0123456789\n"))
+	require.ErrorIs(t, err, lgsink.ErrSpoolFull)
+	require.Equal(t, int64(1), s.Dropped())
+
+	// While the retry interval has not elapsed, the remote
+	// writer is not retried.
+	r.setDown(false)
+	write(t, s, "x")
+	require.Empty(t, r.got)
+
+	// Sync replays regardless of the retry interval.
+	require.NoError(t, s.Sync())
+	require.Equal(t, []string{"0123456789\n", "x"}, r.got)
+}
@@ -0,0 +1,231 @@
+package lgsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// wrapConn wraps driver.Conn. It implements the optional driver
+// interfaces, returning driver.ErrSkip or falling back to the
+// basic methods when the wrapped conn doesn't implement them.
+type wrapConn struct {
+	driver.Conn
+	l *logger
+}
+
+var (
+	_ driver.ConnPrepareContext = (*wrapConn)(nil)
+	_ driver.ConnBeginTx        = (*wrapConn)(nil)
+	_ driver.ExecerContext      = (*wrapConn)(nil)
+	_ driver.QueryerContext     = (*wrapConn)(nil)
+	_ driver.Pinger             = (*wrapConn)(nil)
+	_ driver.SessionResetter    = (*wrapConn)(nil)
+	_ driver.Validator          = (*wrapConn)(nil)
+	_ driver.NamedValueChecker  = (*wrapConn)(nil)
+)
+
+func (c *wrapConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *wrapConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := time.Now()
+
+	var stmt driver.Stmt
+	var err error
+	if cp, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = cp.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+
+	c.l.logOp("prepare", query, nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wrapStmt{Stmt: stmt, query: query, l: c.l}, nil
+}
+
+func (c *wrapConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *wrapConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+
+	var tx driver.Tx
+	var err error
+	if cb, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = cb.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin() //nolint:staticcheck // fallback for old drivers
+	}
+
+	c.l.logOp("begin", "", nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wrapTx{Tx: tx, l: c.l}, nil
+}
+
+func (c *wrapConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	res, err := ec.ExecContext(ctx, query, args)
+	c.l.logOp("exec", query, args, start, err)
+	return res, err
+}
+
+func (c *wrapConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	rows, err := qc.QueryContext(ctx, query, args)
+	c.l.logOp("query", query, args, start, err)
+	return rows, err
+}
+
+func (c *wrapConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *wrapConn) ResetSession(ctx context.Context) error {
+	if sr, ok := c.Conn.(driver.SessionResetter); ok {
+		return sr.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *wrapConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+
+	return true
+}
+
+func (c *wrapConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+type wrapStmt struct {
+	driver.Stmt
+	query string
+	l     *logger
+}
+
+var (
+	_ driver.StmtExecContext   = (*wrapStmt)(nil)
+	_ driver.StmtQueryContext  = (*wrapStmt)(nil)
+	_ driver.NamedValueChecker = (*wrapStmt)(nil)
+)
+
+func (s *wrapStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *wrapStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+
+	var res driver.Result
+	var err error
+	if sec, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = sec.ExecContext(ctx, args)
+	} else {
+		var vals []driver.Value
+		if vals, err = values(args); err == nil {
+			res, err = s.Stmt.Exec(vals) //nolint:staticcheck // fallback for old drivers
+		}
+	}
+
+	s.l.logOp("exec", s.query, args, start, err)
+	return res, err
+}
+
+func (s *wrapStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *wrapStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+
+	var rows driver.Rows
+	var err error
+	if sqc, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = sqc.QueryContext(ctx, args)
+	} else {
+		var vals []driver.Value
+		if vals, err = values(args); err == nil {
+			rows, err = s.Stmt.Query(vals) //nolint:staticcheck // fallback for old drivers
+		}
+	}
+
+	s.l.logOp("query", s.query, args, start, err)
+	return rows, err
+}
+
+func (s *wrapStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+type wrapTx struct {
+	driver.Tx
+	l *logger
+}
+
+func (tx *wrapTx) Commit() error {
+	start := time.Now()
+	err := tx.Tx.Commit()
+	tx.l.logOp("commit", "", nil, start, err)
+	return err
+}
+
+func (tx *wrapTx) Rollback() error {
+	start := time.Now()
+	err := tx.Tx.Rollback()
+	tx.l.logOp("rollback", "", nil, start, err)
+	return err
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+func values(args []driver.NamedValue) ([]driver.Value, error) {
+	vals := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("lgsql: driver does not support named parameters")
+		}
+		vals[i] = arg.Value
+	}
+	return vals, nil
+}
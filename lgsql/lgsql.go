@@ -0,0 +1,232 @@
+// Package lgsql wraps a database/sql driver, logging queries,
+// args, durations and errors to a lg.Log.
+//
+// Use WrapConnector with sql.OpenDB:
+//
+//	db := sql.OpenDB(lgsql.WrapConnector(connector, log))
+//
+// Or use Wrap to register a wrapped driver:
+//
+//	sql.Register("lgsql-postgres", lgsql.Wrap(&pq.Driver{}, log))
+//	db, err := sql.Open("lgsql-postgres", dsn)
+//
+// By default, successful statements are logged at DEBUG level, and
+// failed statements at WARN level (because the error is returned
+// to the caller, who decides whether the operation failed). String
+// and []byte args are redacted by default; see WithRedact.
+package lgsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// Redacted is the value logged in place of a redacted arg.
+const Redacted = "[REDACTED]"
+
+// RedactFunc returns the value to log for arg.
+type RedactFunc func(arg driver.NamedValue) any
+
+// RedactNone logs each arg as-is.
+func RedactNone(arg driver.NamedValue) any {
+	return arg.Value
+}
+
+// RedactAll redacts every arg.
+func RedactAll(arg driver.NamedValue) any {
+	return Redacted
+}
+
+// RedactStrings redacts string and []byte args, which are the
+// usual carriers of sensitive data. Other values, such as numbers,
+// bools and times, are logged as-is. This is the default.
+func RedactStrings(arg driver.NamedValue) any {
+	switch arg.Value.(type) {
+	case string, []byte:
+		return Redacted
+	default:
+		return arg.Value
+	}
+}
+
+// Option is a functional option for Wrap and WrapConnector.
+type Option func(o *options)
+
+type options struct {
+	okLevel     lg.Level
+	failedLevel lg.Level
+	redact      RedactFunc
+}
+
+// WithLevels returns an Option that sets the level at which successful
+// and failed statements are logged. The defaults are lg.LevelDebug
+// and lg.LevelWarn.
+func WithLevels(ok, failed lg.Level) Option {
+	return func(o *options) {
+		o.okLevel = ok
+		o.failedLevel = failed
+	}
+}
+
+// WithRedact returns an Option that sets the func used to redact
+// args. The default is RedactStrings.
+func WithRedact(fn RedactFunc) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.redact = fn
+		}
+	}
+}
+
+// logger holds the Log and options shared by the wrapper types.
+type logger struct {
+	log  lg.Log
+	opts options
+}
+
+func newLogger(log lg.Log, opts []Option) *logger {
+	l := &logger{
+		log:  lg.OrDiscard(log),
+		opts: options{okLevel: lg.LevelDebug, failedLevel: lg.LevelWarn, redact: RedactStrings},
+	}
+
+	for _, opt := range opts {
+		opt(&l.opts)
+	}
+
+	return l
+}
+
+// logOp logs a database operation. If err is driver.ErrSkip,
+// nothing is logged: database/sql will retry via another path.
+func (l *logger) logOp(op, query string, args []driver.NamedValue, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+
+	level := l.opts.okLevel
+	if err != nil {
+		level = l.opts.failedLevel
+	}
+
+	if !lg.Enabled(l.log, level) {
+		return
+	}
+
+	log := l.log.With("sql.op", op)
+	if query != "" {
+		log = log.With("sql.query", query)
+	}
+
+	if len(args) > 0 {
+		vals := make([]any, len(args))
+		for i, arg := range args {
+			vals[i] = l.opts.redact(arg)
+		}
+		log = log.With("sql.args", vals)
+	}
+
+	log = log.With("sql.duration", time.Since(start))
+	if err != nil {
+		log = lg.WithError(log, err)
+	}
+
+	switch level {
+	case lg.LevelError:
+		log.Error("sql " + op)
+	case lg.LevelWarn:
+		log.Warn("sql " + op)
+	default:
+		log.Debug("sql " + op)
+	}
+}
+
+// Wrap returns a driver.Driver that wraps d, logging to log.
+func Wrap(d driver.Driver, log lg.Log, opts ...Option) driver.Driver {
+	return &wrapDriver{Driver: d, l: newLogger(log, opts)}
+}
+
+// WrapConnector returns a driver.Connector that wraps c,
+// logging to log. Use it with sql.OpenDB.
+func WrapConnector(c driver.Connector, log lg.Log, opts ...Option) driver.Connector {
+	return &wrapConnector{Connector: c, l: newLogger(log, opts)}
+}
+
+type wrapDriver struct {
+	driver.Driver
+	l *logger
+}
+
+func (d *wrapDriver) Open(name string) (driver.Conn, error) {
+	start := time.Now()
+	conn, err := d.Driver.Open(name)
+	d.l.logOp("connect", "", nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wrapConn{Conn: conn, l: d.l}, nil
+}
+
+// OpenConnector implements driver.DriverContext.
+func (d *wrapDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.Driver.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &wrapConnector{Connector: c, l: d.l}, nil
+	}
+
+	return &dsnConnector{name: name, d: d}, nil
+}
+
+// dsnConnector is a driver.Connector for drivers that
+// don't implement driver.DriverContext.
+type dsnConnector struct {
+	name string
+	d    *wrapDriver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.d.Open(c.name)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.d
+}
+
+type wrapConnector struct {
+	driver.Connector
+	l *logger
+}
+
+func (c *wrapConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	start := time.Now()
+	conn, err := c.Connector.Connect(ctx)
+	c.l.logOp("connect", "", nil, start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wrapConn{Conn: conn, l: c.l}, nil
+}
+
+func (c *wrapConnector) Driver() driver.Driver {
+	return &wrapDriver{Driver: c.Connector.Driver(), l: c.l}
+}
+
+// Close closes the wrapped connector, if it implements io.Closer.
+func (c *wrapConnector) Close() error {
+	if closer, ok := c.Connector.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
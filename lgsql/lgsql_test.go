@@ -0,0 +1,210 @@
+package lgsql_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgsql"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+// entriesByOp returns the entries in buf, excluding "connect" entries.
+func entriesByOp(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var ms []map[string]any
+	for _, m := range testlg.DecodeJSON(t, buf) {
+		if m["sql.op"] != "connect" {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+func openDB(t *testing.T, buf *bytes.Buffer, opts ...lgsql.Option) *sql.DB {
+	t.Helper()
+
+	log := testlg.NewJSON(buf)
+	db := sql.OpenDB(lgsql.WrapConnector(fakeConnector{}, log, opts...))
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+	return db
+}
+
+func TestExec(t *testing.T) {
+	buf := &bytes.Buffer{}
+	db := openDB(t, buf)
+
+	_, err := db.Exec("INSERT INTO users VALUES (?, ?)", 7, "alice")
+	require.NoError(t, err)
+
+	_, err = db.Exec("fail", 1)
+	require.Error(t, err)
+
+	ms := entriesByOp(t, buf)
+	require.Len(t, ms, 2)
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "exec", ms[0]["sql.op"])
+	require.Equal(t, "INSERT INTO users VALUES (?, ?)", ms[0]["sql.query"])
+	require.Equal(t, []any{float64(7), lgsql.Redacted}, ms[0]["sql.args"])
+	require.Contains(t, ms[0], "sql.duration")
+	require.NotContains(t, ms[0], "error")
+
+	require.Equal(t, "warn", ms[1]["level"])
+	require.Equal(t, "exec failed", ms[1]["error"])
+}
+
+func TestQuery(t *testing.T) {
+	buf := &bytes.Buffer{}
+	db := openDB(t, buf, lgsql.WithRedact(lgsql.RedactNone), lgsql.WithLevels(lg.LevelWarn, lg.LevelError))
+
+	// fakeConn doesn't implement QueryerContext, so the
+	// query is performed via a prepared statement.
+	rows, err := db.Query("SELECT name FROM users WHERE name = ?", "alice")
+	require.NoError(t, err)
+	require.False(t, rows.Next())
+	require.NoError(t, rows.Close())
+
+	ms := entriesByOp(t, buf)
+	require.Len(t, ms, 2)
+	require.Equal(t, "prepare", ms[0]["sql.op"])
+	require.Equal(t, "warn", ms[1]["level"])
+	require.Equal(t, "query", ms[1]["sql.op"])
+	require.Equal(t, []any{"alice"}, ms[1]["sql.args"])
+}
+
+func TestTx(t *testing.T) {
+	buf := &bytes.Buffer{}
+	db := openDB(t, buf, lgsql.WithRedact(lgsql.RedactAll))
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	_, err = tx.Exec("UPDATE users SET admin = ?", true)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	tx, err = db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	ms := entriesByOp(t, buf)
+	var ops []any
+	for _, m := range ms {
+		ops = append(ops, m["sql.op"])
+	}
+	require.Equal(t, []any{"begin", "exec", "commit", "begin", "rollback"}, ops)
+	require.Equal(t, []any{lgsql.Redacted}, ms[1]["sql.args"])
+}
+
+func TestWrap(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := testlg.NewJSON(buf)
+	d := lgsql.Wrap(fakeDriver{}, log)
+
+	// sql.Register panics if invoked twice with the same name (e.g.
+	// with -count=2), so open the DB via the connector, as sql.Open does.
+	dc, ok := d.(driver.DriverContext)
+	require.True(t, ok)
+	c, err := dc.OpenConnector("dsn")
+	require.NoError(t, err)
+	db := sql.OpenDB(c)
+	defer db.Close()
+
+	_, err = db.Exec("DELETE FROM users")
+	require.NoError(t, err)
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	require.Equal(t, "connect", ms[0]["sql.op"])
+	require.Equal(t, "exec", ms[1]["sql.op"])
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{}, nil
+}
+
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{}, nil
+}
+
+func (fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+// fakeConn implements driver.ExecerContext,
+// but not driver.QueryerContext.
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if query == "fail" {
+		return nil, errors.New("exec failed")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeStmt struct {
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string {
+	return []string{"name"}
+}
+
+func (fakeRows) Close() error {
+	return nil
+}
+
+func (fakeRows) Next(dest []driver.Value) error {
+	return io.EOF
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
@@ -0,0 +1,44 @@
+// Package lgtemporal adapts a lg.Log for use by the Temporal
+// Go SDK, so that workflow workers log via the application's
+// logging configuration.
+//
+//	c, err := client.Dial(client.Options{Logger: lgtemporal.New(log)})
+//
+// The Temporal log.Logger interface is satisfied structurally,
+// so this package does not depend on the Temporal SDK.
+package lgtemporal
+
+import (
+	"github.com/neilotoole/lg/v2"
+)
+
+// Logger implements the Temporal SDK's log.Logger interface.
+// lg has no INFO level: Info messages are logged at DEBUG level.
+type Logger struct {
+	log lg.Log
+}
+
+// New returns a Logger that logs to log.
+func New(log lg.Log) *Logger {
+	return &Logger{log: lg.AddCallerSkip(lg.OrDiscard(log), 1)}
+}
+
+// Debug implements log.Logger.
+func (l *Logger) Debug(msg string, keyvals ...any) {
+	lg.WithKeyVals(l.log, keyvals...).Debug(msg)
+}
+
+// Info implements log.Logger.
+func (l *Logger) Info(msg string, keyvals ...any) {
+	lg.WithKeyVals(l.log, keyvals...).Debug(msg)
+}
+
+// Warn implements log.Logger.
+func (l *Logger) Warn(msg string, keyvals ...any) {
+	lg.WithKeyVals(l.log, keyvals...).Warn(msg)
+}
+
+// Error implements log.Logger.
+func (l *Logger) Error(msg string, keyvals ...any) {
+	lg.WithKeyVals(l.log, keyvals...).Error(msg)
+}
@@ -0,0 +1,54 @@
+package lgtemporal_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgtemporal"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// logger is the Temporal SDK's log.Logger interface.
+type logger interface {
+	Debug(msg string, keyvals ...any)
+	Info(msg string, keyvals ...any)
+	Warn(msg string, keyvals ...any)
+	Error(msg string, keyvals ...any)
+}
+
+var _ logger = (*lgtemporal.Logger)(nil)
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := lgtemporal.New(zaplg.NewWith(buf, "json", false, false, true, true, 0))
+
+	l.Debug("debug msg", "WorkflowID", "wf1")
+	l.Info("Started Worker", "Namespace", "default", "TaskQueue", "q1")
+	l.Warn("warn msg")
+	l.Error("Activity error.", "Attempt", 3)
+
+	var ms []map[string]any
+	sc := bufio.NewScanner(buf)
+	for sc.Scan() {
+		m := map[string]any{}
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &m))
+		ms = append(ms, m)
+	}
+	require.Len(t, ms, 4)
+
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "wf1", ms[0]["WorkflowID"])
+	require.Contains(t, ms[0]["caller"], "lgtemporal_test.go")
+
+	require.Equal(t, "debug", ms[1]["level"])
+	require.Equal(t, "Started Worker", ms[1]["message"])
+	require.Equal(t, "q1", ms[1]["TaskQueue"])
+
+	require.Equal(t, "warn", ms[2]["level"])
+	require.Equal(t, "error", ms[3]["level"])
+	require.Equal(t, float64(3), ms[3]["Attempt"])
+}
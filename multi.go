@@ -0,0 +1,156 @@
+package lg
+
+import "io"
+
+// Dest is a destination of a Multi log: entries at
+// Level or above are logged to Log.
+type Dest struct {
+	Log   Log
+	Level Level
+}
+
+// Multi returns a Log that logs each entry to every dest whose
+// minimum level the entry meets, for example:
+//
+//	log := lg.Multi(
+//	  lg.Dest{Log: consoleLog, Level: lg.LevelWarn},
+//	  lg.Dest{Log: fileLog, Level: lg.LevelDebug},
+//	  lg.Dest{Log: alertLog, Level: lg.LevelError},
+//	)
+//
+// This works the same for any Log impl. Each dest's own level (see
+// Enabled) still applies. Fields added via With are added to every
+// dest. The fn of WarnIfFuncError and c of WarnIfCloseError are
+// invoked once. Dests whose Log is nil are ignored.
+func Multi(dests ...Dest) Log {
+	m := &multiLog{}
+	for _, d := range dests {
+		if d.Log != nil {
+			m.dests = append(m.dests, Dest{Log: AddCallerSkip(d.Log, 1), Level: d.Level})
+		}
+	}
+
+	return m
+}
+
+type multiLog struct {
+	dests []Dest
+}
+
+// Enabled reports whether any dest is enabled for level.
+func (m *multiLog) Enabled(level Level) bool {
+	for _, d := range m.dests {
+		if level >= d.Level && Enabled(d.Log, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddCallerSkip implements the optional interface used by AddCallerSkip.
+func (m *multiLog) AddCallerSkip(skip int) Log {
+	child := &multiLog{dests: make([]Dest, len(m.dests))}
+	for i, d := range m.dests {
+		child.dests[i] = Dest{Log: AddCallerSkip(d.Log, skip), Level: d.Level}
+	}
+
+	return child
+}
+
+func (m *multiLog) Debug(a ...any) {
+	for _, d := range m.dests {
+		if d.Level <= LevelDebug {
+			d.Log.Debug(a...)
+		}
+	}
+}
+
+func (m *multiLog) Debugf(format string, a ...any) {
+	for _, d := range m.dests {
+		if d.Level <= LevelDebug {
+			d.Log.Debugf(format, a...)
+		}
+	}
+}
+
+func (m *multiLog) Warn(a ...any) {
+	for _, d := range m.dests {
+		if d.Level <= LevelWarn {
+			d.Log.Warn(a...)
+		}
+	}
+}
+
+func (m *multiLog) Warnf(format string, a ...any) {
+	for _, d := range m.dests {
+		if d.Level <= LevelWarn {
+			d.Log.Warnf(format, a...)
+		}
+	}
+}
+
+func (m *multiLog) WarnIfError(err error) {
+	if err == nil {
+		return
+	}
+
+	for _, d := range m.dests {
+		if d.Level <= LevelWarn {
+			d.Log.WarnIfError(err)
+		}
+	}
+}
+
+func (m *multiLog) WarnIfFuncError(fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	if err := fn(); err != nil {
+		for _, d := range m.dests {
+			if d.Level <= LevelWarn {
+				d.Log.WarnIfError(err)
+			}
+		}
+	}
+}
+
+func (m *multiLog) WarnIfCloseError(c io.Closer) {
+	if c == nil {
+		return
+	}
+
+	if err := c.Close(); err != nil {
+		for _, d := range m.dests {
+			if d.Level <= LevelWarn {
+				d.Log.WarnIfError(err)
+			}
+		}
+	}
+}
+
+func (m *multiLog) Error(a ...any) {
+	for _, d := range m.dests {
+		if d.Level <= LevelError {
+			d.Log.Error(a...)
+		}
+	}
+}
+
+func (m *multiLog) Errorf(format string, a ...any) {
+	for _, d := range m.dests {
+		if d.Level <= LevelError {
+			d.Log.Errorf(format, a...)
+		}
+	}
+}
+
+func (m *multiLog) With(key string, val any) Log {
+	child := &multiLog{dests: make([]Dest, len(m.dests))}
+	for i, d := range m.dests {
+		child.dests[i] = Dest{Log: d.Log.With(key, val), Level: d.Level}
+	}
+
+	return child
+}
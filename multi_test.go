@@ -0,0 +1,64 @@
+package lg_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestMulti(t *testing.T) {
+	console, file, alert := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	newLog := func(buf *bytes.Buffer) lg.Log {
+		return zaplg.NewWith(buf, "json", false, false, true, true, 0)
+	}
+
+	log := lg.Multi(
+		lg.Dest{Log: newLog(console), Level: lg.LevelWarn},
+		lg.Dest{Log: newLog(file), Level: lg.LevelDebug},
+		lg.Dest{Log: newLog(alert), Level: lg.LevelError},
+		lg.Dest{Log: nil, Level: lg.LevelDebug},
+	).With("k", "v")
+
+	log.Debugf("debug %d", 1)
+	log.Warn("warn")
+	calls := 0
+	log.WarnIfFuncError(func() error { calls++; return errors.New("func err") })
+	log.Error("error")
+	lg.AddCallerSkip(log, 0).Errorf("errorf")
+
+	require.Equal(t, 1, calls)
+
+	fileEntries := testlg.DecodeJSON(t, file)
+	require.Len(t, fileEntries, 5)
+	require.Equal(t, "debug 1", fileEntries[0]["message"])
+	require.Equal(t, "v", fileEntries[0]["k"])
+	require.Contains(t, fileEntries[0]["caller"], "multi_test.go:")
+	require.Contains(t, fileEntries[4]["caller"], "multi_test.go:")
+
+	consoleEntries := testlg.DecodeJSON(t, console)
+	require.Len(t, consoleEntries, 4)
+	require.Equal(t, "warn", consoleEntries[0]["message"])
+	require.Equal(t, "func err", consoleEntries[1]["message"])
+
+	alertEntries := testlg.DecodeJSON(t, alert)
+	require.Len(t, alertEntries, 2)
+	require.Equal(t, "error", alertEntries[0]["message"])
+}
+
+func TestMulti_Enabled(t *testing.T) {
+	log := lg.Multi(
+		lg.Dest{Log: zaplg.NewWith(&bytes.Buffer{}, "json", false, false, true, false, 0,
+			zaplg.WithLevel(lg.LevelError)), Level: lg.LevelDebug},
+		lg.Dest{Log: lg.Discard(), Level: lg.LevelDebug},
+	)
+
+	require.False(t, lg.Enabled(log, lg.LevelDebug))
+	require.True(t, lg.Enabled(log, lg.LevelError))
+	require.False(t, lg.Enabled(lg.Multi(), lg.LevelError))
+}
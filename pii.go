@@ -0,0 +1,154 @@
+package lg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// PIIPolicy determines how the values of PII fields are logged.
+type PIIPolicy int32
+
+const (
+	// PIIHash logs a hash of the value, so that entries concerning
+	// the same value can be correlated without revealing it. This
+	// is the default policy.
+	PIIHash PIIPolicy = iota
+
+	// PIIAllow logs the value as is, e.g. in development.
+	PIIAllow
+
+	// PIIDrop omits the field.
+	PIIDrop
+)
+
+// String returns the name of the policy, e.g. "hash".
+func (p PIIPolicy) String() string {
+	switch p {
+	case PIIHash:
+		return "hash"
+	case PIIAllow:
+		return "allow"
+	case PIIDrop:
+		return "drop"
+	default:
+		return fmt.Sprintf("PIIPolicy(%d)", int32(p))
+	}
+}
+
+// ParsePIIPolicy parses a policy name: "allow", "hash" or "drop".
+// Parsing is case-insensitive.
+func ParsePIIPolicy(s string) (PIIPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "hash":
+		return PIIHash, nil
+	case "allow":
+		return PIIAllow, nil
+	case "drop":
+		return PIIDrop, nil
+	default:
+		return PIIHash, fmt.Errorf("lg: invalid PII policy: %q", s)
+	}
+}
+
+var (
+	piiPolicy  atomic.Int32
+	piiHashKey atomic.Pointer[[]byte]
+)
+
+// SetPIIPolicy sets the policy applied to PII fields, typically once
+// at startup, per environment:
+//
+//	policy, err := lg.ParsePIIPolicy(os.Getenv("LOG_PII"))
+//	// handle err
+//	lg.SetPIIPolicy(policy)
+//
+// The default is PIIHash.
+func SetPIIPolicy(p PIIPolicy) {
+	piiPolicy.Store(int32(p))
+}
+
+// SetPIIHashKey sets the key used to hash PII values via HMAC-SHA256
+// under PIIHash. Without a key, values are hashed via plain SHA256,
+// which does not resist guessing of low-entropy values, such as email
+// addresses. The key should be secret, and stable across restarts,
+// so that hashes can be correlated. An empty key clears the key.
+func SetPIIHashKey(key []byte) {
+	if len(key) == 0 {
+		piiHashKey.Store(nil)
+		return
+	}
+
+	key = append([]byte(nil), key...)
+	piiHashKey.Store(&key)
+}
+
+// PIIValue is the value of a PII field, which is rendered according to
+// the policy set via SetPIIPolicy at the time it is rendered. Under
+// PIIDrop, a PIIValue that is not omitted (i.e. that was not added
+// via WithFields) is rendered as "[PII]". Use PII to create a
+// PIIValue field.
+type PIIValue struct {
+	v any
+}
+
+// PII returns a Field whose value v is personally identifiable
+// information, for use with WithFields:
+//
+//	log = lg.WithFields(log, lg.PII("email", user.Email))
+//
+// The value is rendered according to the policy set via SetPIIPolicy;
+// under PIIDrop, WithFields omits the field.
+func PII(key string, v any) Field {
+	return Field{Key: key, Val: PIIValue{v: v}}
+}
+
+// String implements fmt.Stringer.
+func (p PIIValue) String() string {
+	switch PIIPolicy(piiPolicy.Load()) {
+	case PIIAllow:
+		return fmt.Sprint(p.v)
+	case PIIDrop:
+		return "[PII]"
+	default:
+		return p.hash()
+	}
+}
+
+// MarshalJSON implements json.Marshaler. Under PIIAllow,
+// the value is marshalled as is.
+func (p PIIValue) MarshalJSON() ([]byte, error) {
+	if PIIPolicy(piiPolicy.Load()) == PIIAllow {
+		return json.Marshal(p.v)
+	}
+
+	return json.Marshal(p.String())
+}
+
+// hash returns "sha256:" followed by the first 16
+// hex digits of the hash of the value.
+func (p PIIValue) hash() string {
+	data := []byte(fmt.Sprint(p.v))
+	var sum []byte
+	if key := piiHashKey.Load(); key != nil {
+		mac := hmac.New(sha256.New, *key)
+		mac.Write(data)
+		sum = mac.Sum(nil)
+	} else {
+		s := sha256.Sum256(data)
+		sum = s[:]
+	}
+
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// isDroppedPII reports whether val is a PIIValue
+// that is omitted under the current policy.
+func isDroppedPII(val any) bool {
+	_, ok := val.(PIIValue)
+	return ok && PIIPolicy(piiPolicy.Load()) == PIIDrop
+}
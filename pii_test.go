@@ -0,0 +1,71 @@
+package lg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestPII(t *testing.T) {
+	defer lg.SetPIIPolicy(lg.PIIHash)
+	defer lg.SetPIIHashKey(nil)
+
+	logEntry := func() map[string]any {
+		buf := &bytes.Buffer{}
+		log := zaplg.NewWith(buf, "json", false, false, false, false, 0)
+		lg.WithFields(log, lg.PII("email", "alice@example.com"), lg.Field{Key: "id", Val: 7}).Debug("signup")
+
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+		return m
+	}
+
+	m := logEntry()
+	require.Equal(t, "sha256:ff8d9819fc0e12bf", m["email"])
+	require.EqualValues(t, 7, m["id"])
+
+	lg.SetPIIHashKey([]byte("secret"))
+	m = logEntry()
+	require.Regexp(t, `^sha256:[0-9a-f]{16}$`, m["email"])
+	require.NotEqual(t, "sha256:ff8d9819fc0e12bf", m["email"])
+
+	lg.SetPIIPolicy(lg.PIIAllow)
+	m = logEntry()
+	require.Equal(t, "alice@example.com", m["email"])
+
+	lg.SetPIIPolicy(lg.PIIDrop)
+	m = logEntry()
+	require.NotContains(t, m, "email")
+	require.EqualValues(t, 7, m["id"])
+}
+
+func TestPIIValue_MarshalJSON(t *testing.T) {
+	defer lg.SetPIIPolicy(lg.PIIHash)
+
+	val := lg.PII("age", 42).Val
+	lg.SetPIIPolicy(lg.PIIAllow)
+	b, err := json.Marshal(val)
+	require.NoError(t, err)
+	require.Equal(t, "42", string(b))
+
+	lg.SetPIIPolicy(lg.PIIDrop)
+	b, err = json.Marshal(val)
+	require.NoError(t, err)
+	require.Equal(t, `"[PII]"`, string(b))
+}
+
+func TestParsePIIPolicy(t *testing.T) {
+	for _, p := range []lg.PIIPolicy{lg.PIIHash, lg.PIIAllow, lg.PIIDrop} {
+		got, err := lg.ParsePIIPolicy(p.String())
+		require.NoError(t, err)
+		require.Equal(t, p, got)
+	}
+
+	_, err := lg.ParsePIIPolicy("encrypt")
+	require.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package lg
+
+import (
+	"context"
+	"runtime/pprof"
+	"sort"
+)
+
+// PprofLabels returns the runtime/pprof labels carried by ctx, as
+// fields sorted by key. This allows logs and profiles to be correlated
+// by the same label set. It is a ContextFieldsFunc, so the labels can
+// be added to the Log returned by Ctx or WithContext:
+//
+//	lg.RegisterContextFields(lg.PprofLabels)
+//
+//	pprof.Do(ctx, pprof.Labels("endpoint", "/users"), func(ctx context.Context) {
+//	  lg.Ctx(ctx).Debug("fetching") // Has field endpoint=/users.
+//	})
+func PprofLabels(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+
+	var fields []Field
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		fields = append(fields, Field{Key: key, Val: value})
+		return true
+	})
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields
+}
+
+// WithPprofLabels returns a child of log that has
+// the runtime/pprof labels carried by ctx as fields.
+// See PprofLabels.
+func WithPprofLabels(ctx context.Context, log Log) Log {
+	return WithFields(log, PprofLabels(ctx)...)
+}
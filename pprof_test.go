@@ -0,0 +1,32 @@
+package lg_test
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestPprofLabels(t *testing.T) {
+	require.Nil(t, lg.PprofLabels(context.Background()))
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+
+	labels := pprof.Labels("tenant", "acme", "endpoint", "/users")
+	pprof.Do(context.Background(), labels, func(ctx context.Context) {
+		require.Equal(t, []lg.Field{
+			{Key: "endpoint", Val: "/users"},
+			{Key: "tenant", Val: "acme"},
+		}, lg.PprofLabels(ctx))
+
+		lg.WithPprofLabels(ctx, log).Debug("fetching")
+	})
+
+	require.Equal(t, `{"level":"debug","message":"fetching","endpoint":"/users","tenant":"acme"}`+"\n", buf.String())
+}
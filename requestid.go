@@ -0,0 +1,62 @@
+package lg
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// RequestIDKey is the key of the request ID field. See ContextWithRequestID.
+const RequestIDKey = "request_id"
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewRequestID returns a new request ID, in ULID format: 26 characters
+// encoding a millisecond timestamp and 80 random bits, e.g.
+// "01HF8X5J3V9N6C4T2Q7RZKBM0D". ULIDs sort by creation time, and
+// are URL-safe.
+func NewRequestID() string {
+	var id [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	if _, err := rand.Read(id[6:]); err != nil {
+		// crypto/rand doesn't fail on supported platforms.
+		panic(err)
+	}
+
+	// Encode the 128 bits as 26 base32 chars, the first of
+	// which holds only the 3 most significant bits.
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+	var b [26]byte
+	for i := 25; i >= 0; i-- {
+		b[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(b[:])
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx that carries request ID
+// id. ContextFields returns the ID as field RequestIDKey, so that
+// every entry logged via Ctx or WithContext for the request has it.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or the empty
+// string if ctx does not carry one. See ContextWithRequestID.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
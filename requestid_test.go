@@ -0,0 +1,39 @@
+package lg_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+func TestNewRequestID(t *testing.T) {
+	ulid := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+
+	seen := map[string]bool{}
+	prev := lg.NewRequestID()
+	for i := 0; i < 100; i++ {
+		time.Sleep(time.Millisecond / 10)
+		id := lg.NewRequestID()
+		require.Regexp(t, ulid, id)
+		require.False(t, seen[id])
+		seen[id] = true
+
+		// The timestamp prefix sorts by creation time.
+		require.LessOrEqual(t, prev[:10], id[:10])
+		prev = id
+	}
+}
+
+func TestContextWithRequestID(t *testing.T) {
+	require.Empty(t, lg.RequestID(context.Background()))
+	require.Empty(t, lg.ContextFields(context.Background()))
+
+	ctx := lg.ContextWithRequestID(context.Background(), "abc")
+	require.Equal(t, "abc", lg.RequestID(ctx))
+	require.Equal(t, []lg.Field{{Key: lg.RequestIDKey, Val: "abc"}}, lg.ContextFields(ctx))
+}
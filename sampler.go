@@ -0,0 +1,383 @@
+package lg
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default values for Sampler.
+const (
+	DefaultSampleInterval   = time.Second
+	DefaultHotSpotThreshold = 100
+	DefaultHotSpotEvery     = 100
+
+	// maxSampleKeys is the maximum number of distinct call
+	// sites tracked by a Sampler per interval.
+	maxSampleKeys = 10000
+)
+
+// SamplerOption is a functional option for NewSampler.
+type SamplerOption func(s *samplerState)
+
+// WithSampleRate returns a SamplerOption that sets the fraction, from
+// 0 to 1, of entries at level that are kept. The default is 1 (all
+// entries are kept). ERROR entries are always kept: the rate of
+// LevelError cannot be set.
+func WithSampleRate(level Level, rate float64) SamplerOption {
+	return func(s *samplerState) {
+		if level == LevelError || !level.valid() {
+			return
+		}
+
+		switch {
+		case rate < 0:
+			rate = 0
+		case rate > 1:
+			rate = 1
+		}
+		s.rates[level] = rate
+	}
+}
+
+// WithHotSpots returns a SamplerOption that configures hot-spot
+// detection: once a call site has logged threshold entries in an
+// interval, only every every-th subsequent entry from that call site
+// in the interval is kept (subject also to the level's sample rate).
+// The defaults are DefaultHotSpotThreshold and DefaultHotSpotEvery.
+// If threshold is zero, hot-spot detection is disabled.
+func WithHotSpots(threshold, every int) SamplerOption {
+	return func(s *samplerState) {
+		if threshold >= 0 {
+			s.hotThreshold = threshold
+		}
+		if every > 0 {
+			s.hotEvery = every
+		}
+	}
+}
+
+// WithSampleInterval returns a SamplerOption that sets the interval
+// over which call sites are counted for hot-spot detection. The
+// default is DefaultSampleInterval.
+func WithSampleInterval(d time.Duration) SamplerOption {
+	return func(s *samplerState) {
+		if d > 0 {
+			s.interval = d
+		}
+	}
+}
+
+// Sampler is a Log that samples entries, so that the cost of logging
+// scales sub-linearly with traffic. ERROR entries are never dropped;
+// WARN and DEBUG entries are kept at the rates set via WithSampleRate,
+// and entries from a hot call site are further sampled (see
+// WithHotSpots):
+//
+//	log = lg.NewSampler(log,
+//	  lg.WithSampleRate(lg.LevelWarn, 0.5),
+//	  lg.WithSampleRate(lg.LevelDebug, 0.01),
+//	)
+//
+// Sampling is deterministic: a rate of 0.25 keeps exactly every
+// fourth entry at that level.
+//
+// The first entry from each call site in an interval is always kept,
+// so that a new failure mode is never invisible; subsequent entries
+// from the call site are sampled, even if their messages differ. For
+// each call site with entries dropped in an interval, a summary entry
+// with fields "sampled=true" and "suppressed=N" is logged, at the
+// highest level dropped, when the interval ends (on the next logging
+// call) or when Sync is invoked. The summary's message is the format
+// string (for Debugf and Warnf), or the message of the first dropped
+// entry. The child logs returned by With share
+// the state of their parent. Sampler is safe for concurrent use.
+type Sampler struct {
+	log Log
+	st  *samplerState
+
+	// callerSkip is the caller skip added via AddCallerSkip,
+	// used to determine the call site of an entry.
+	callerSkip int
+}
+
+// samplerState is the state shared by a Sampler and its children.
+type samplerState struct {
+	rates        [LevelError + 1]float64
+	hotThreshold int
+	hotEvery     int
+	interval     time.Duration
+
+	// log is the root log, to which summaries are written.
+	log Log
+
+	mu          sync.Mutex
+	levelCounts [LevelError + 1]uint64
+	windowStart time.Time
+	keyCounts   map[sampleKey]int
+	suppressed  map[sampleKey]*suppression
+
+	dropped atomic.Uint64
+}
+
+// NewSampler returns a Sampler that writes to log.
+func NewSampler(log Log, opts ...SamplerOption) *Sampler {
+	st := &samplerState{
+		rates:        [LevelError + 1]float64{1, 1, 1},
+		hotThreshold: DefaultHotSpotThreshold,
+		hotEvery:     DefaultHotSpotEvery,
+		interval:     DefaultSampleInterval,
+		keyCounts:    map[sampleKey]int{},
+		suppressed:   map[sampleKey]*suppression{},
+	}
+	for _, opt := range opts {
+		opt(st)
+	}
+
+	// The caller skip accounts for the frame of Sampler's methods.
+	st.log = AddCallerSkip(OrDiscard(log), 1)
+	return &Sampler{log: st.log, st: st}
+}
+
+// Dropped returns the number of entries dropped by sampling.
+func (s *Sampler) Dropped() uint64 {
+	return s.st.dropped.Load()
+}
+
+// Sync logs the summaries of entries dropped in the current interval
+// and, if the underlying log has a Sync method, invokes it. Sync is
+// invoked by FlushOnExit.
+func (s *Sampler) Sync() error {
+	s.st.mu.Lock()
+	pending := s.st.takeSuppressed()
+	s.st.mu.Unlock()
+	s.st.logSummaries(pending)
+
+	if syncer, ok := s.st.log.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// sampleKey identifies the call site of an entry.
+type sampleKey uintptr
+
+// callSite returns the sampleKey of the caller of the exported
+// method that invokes Sampler.keep, adjusted by skip.
+func callSite(skip int) sampleKey {
+	var pcs [1]uintptr
+	// Skip runtime.Callers, callSite, Sampler.keep,
+	// and the exported method.
+	runtime.Callers(4+skip, pcs[:])
+	return sampleKey(pcs[0])
+}
+
+// keep reports whether the entry at level should be logged: level
+// must be enabled for the underlying log, and the entry must be kept
+// by sampling (see samplerState.keep). It must be invoked directly
+// by the exported method, for the call site to be correct.
+func (s *Sampler) keep(level Level, format string, a []any) bool {
+	if !Enabled(s.log, level) {
+		return false
+	}
+
+	return s.st.keep(level, callSite(s.callerSkip), format, a)
+}
+
+// suppression records the entries dropped for a
+// call site in an interval.
+type suppression struct {
+	msg   string
+	level Level
+	n     int
+}
+
+// takeSuppressed returns and resets the suppressions of the
+// current interval, sorted by message. The caller must hold st.mu.
+func (st *samplerState) takeSuppressed() []*suppression {
+	if len(st.suppressed) == 0 {
+		return nil
+	}
+
+	pending := make([]*suppression, 0, len(st.suppressed))
+	for _, sup := range st.suppressed {
+		pending = append(pending, sup)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].msg < pending[j].msg })
+	st.suppressed = map[sampleKey]*suppression{}
+	return pending
+}
+
+// logSummaries logs a summary entry for each of pending.
+func (st *samplerState) logSummaries(pending []*suppression) {
+	for _, sup := range pending {
+		log := st.log.With("sampled", true).With("suppressed", sup.n)
+		if sup.level == LevelWarn {
+			log.Warn(sup.msg)
+		} else {
+			log.Debug(sup.msg)
+		}
+	}
+}
+
+// keep reports whether the entry at level from call site key should
+// be kept. The entry's message is format or, if format is empty,
+// fmt.Sprint(a...); it is only rendered if the entry is the first
+// dropped for key in the interval. If an interval has ended, the
+// summaries of its dropped entries are logged first.
+func (st *samplerState) keep(level Level, key sampleKey, format string, a []any) bool {
+	keep, pending := st.sample(level, key, format, a)
+	st.logSummaries(pending)
+	return keep
+}
+
+// sample implements keep. If an interval has
+// ended, sample returns its suppressions.
+func (st *samplerState) sample(level Level, key sampleKey, format string, a []any) (
+	keep bool, pending []*suppression,
+) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(st.windowStart) >= st.interval {
+		st.windowStart = now
+		st.keyCounts = map[sampleKey]int{}
+		pending = st.takeSuppressed()
+	}
+
+	n, ok := st.keyCounts[key]
+	if ok || len(st.keyCounts) < maxSampleKeys {
+		n++
+		st.keyCounts[key] = n
+	}
+
+	if n == 1 {
+		// The first entry from key in the interval is always kept.
+		return true, pending
+	}
+
+	keep = true
+	if st.hotThreshold > 0 && n > st.hotThreshold && (n-st.hotThreshold)%st.hotEvery != 0 {
+		keep = false
+	}
+
+	if keep && st.rates[level] < 1 {
+		// Keep the entry if it causes n*rate to cross an integer.
+		n := st.levelCounts[level]
+		st.levelCounts[level]++
+		rate := st.rates[level]
+		keep = uint64(float64(n+1)*rate) > uint64(float64(n)*rate)
+	}
+
+	if !keep {
+		st.dropped.Add(1)
+		sup, ok := st.suppressed[key]
+		if !ok {
+			if len(st.suppressed) >= maxSampleKeys {
+				return false, pending
+			}
+			msg := format
+			if msg == "" {
+				msg = fmt.Sprint(a...)
+			}
+			sup = &suppression{msg: msg, level: level}
+			st.suppressed[key] = sup
+		}
+		sup.n++
+		if level > sup.level {
+			sup.level = level
+		}
+	}
+
+	return keep, pending
+}
+
+// Enabled reports whether level is enabled for the underlying log.
+// Note that a sample rate of zero doesn't disable a level, because
+// the first entry from each call site is kept.
+func (s *Sampler) Enabled(level Level) bool {
+	return Enabled(s.log, level)
+}
+
+// AddCallerSkip implements the optional interface used by AddCallerSkip.
+func (s *Sampler) AddCallerSkip(skip int) Log {
+	return &Sampler{log: AddCallerSkip(s.log, skip), st: s.st, callerSkip: s.callerSkip + skip}
+}
+
+// Debug implements Log.Debug.
+func (s *Sampler) Debug(a ...any) {
+	if s.keep(LevelDebug, "", a) {
+		s.log.Debug(a...)
+	}
+}
+
+// Debugf implements Log.Debugf.
+func (s *Sampler) Debugf(format string, a ...any) {
+	if s.keep(LevelDebug, format, nil) {
+		s.log.Debugf(format, a...)
+	}
+}
+
+// Warn implements Log.Warn.
+func (s *Sampler) Warn(a ...any) {
+	if s.keep(LevelWarn, "", a) {
+		s.log.Warn(a...)
+	}
+}
+
+// Warnf implements Log.Warnf.
+func (s *Sampler) Warnf(format string, a ...any) {
+	if s.keep(LevelWarn, format, nil) {
+		s.log.Warnf(format, a...)
+	}
+}
+
+// WarnIfError implements Log.WarnIfError.
+func (s *Sampler) WarnIfError(err error) {
+	if err != nil && s.keep(LevelWarn, "", []any{err}) {
+		s.log.WarnIfError(err)
+	}
+}
+
+// WarnIfFuncError implements Log.WarnIfFuncError.
+func (s *Sampler) WarnIfFuncError(fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	if err := fn(); err != nil && s.keep(LevelWarn, "", []any{err}) {
+		s.log.WarnIfError(err)
+	}
+}
+
+// WarnIfCloseError implements Log.WarnIfCloseError.
+func (s *Sampler) WarnIfCloseError(c io.Closer) {
+	if c == nil {
+		return
+	}
+
+	if err := c.Close(); err != nil && s.keep(LevelWarn, "", []any{err}) {
+		s.log.WarnIfError(err)
+	}
+}
+
+// Error implements Log.Error. ERROR entries are never dropped.
+func (s *Sampler) Error(a ...any) {
+	s.log.Error(a...)
+}
+
+// Errorf implements Log.Errorf. ERROR entries are never dropped.
+func (s *Sampler) Errorf(format string, a ...any) {
+	s.log.Errorf(format, a...)
+}
+
+// With implements Log.With. The returned
+// log shares the state of s.
+func (s *Sampler) With(key string, val any) Log {
+	return &Sampler{log: s.log.With(key, val), st: s.st, callerSkip: s.callerSkip}
+}
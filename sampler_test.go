@@ -0,0 +1,185 @@
+package lg_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestSampler_Rates(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+	log := lg.NewSampler(zlog,
+		lg.WithSampleRate(lg.LevelWarn, 0.5),
+		lg.WithSampleRate(lg.LevelDebug, 0.1),
+		lg.WithSampleRate(lg.LevelError, 0), // ignored
+		lg.WithHotSpots(0, 0),
+	)
+
+	for i := 0; i < 100; i++ {
+		log.Debugf("debug %d", i)
+		log.With("i", i).Warn("warn")
+		log.Errorf("error %d", i)
+	}
+
+	counts := map[any]int{}
+	for _, m := range testlg.DecodeJSON(t, buf) {
+		counts[m["level"]]++
+		require.Contains(t, m["caller"], "sampler_test.go")
+	}
+
+	require.Equal(t, 10, counts["debug"])
+	require.Equal(t, 50, counts["warn"])
+	require.Equal(t, 100, counts["error"])
+	require.EqualValues(t, 140, log.Dropped())
+}
+
+func TestSampler_HotSpots(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log := lg.NewSampler(zlog, lg.WithHotSpots(10, 5))
+
+	for i := 0; i < 30; i++ {
+		log.Debugf("hot %d", i)
+		if i%10 == 0 {
+			log.Debug("cold")
+		}
+	}
+
+	var hot, cold int
+	for _, m := range testlg.DecodeJSON(t, buf) {
+		if m["message"] == "cold" {
+			cold++
+		} else {
+			hot++
+		}
+	}
+
+	// The first 10, and then every 5th of the remaining 20.
+	require.Equal(t, 14, hot)
+	require.Equal(t, 3, cold)
+}
+
+func TestSampler_Enabled(t *testing.T) {
+	log := lg.NewSampler(zaplg.NewWith(&bytes.Buffer{}, "json", false, false, true, false, 0),
+		lg.WithSampleRate(lg.LevelDebug, 0))
+	// The first entry from each call site is kept, even at rate zero.
+	require.True(t, lg.Enabled(log, lg.LevelDebug))
+	require.True(t, lg.Enabled(log, lg.LevelWarn))
+	require.True(t, lg.Enabled(log, lg.LevelError))
+}
+
+func TestSampler_FirstOccurrence(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log := lg.NewSampler(zlog, lg.WithSampleRate(lg.LevelDebug, 0), lg.WithSampleRate(lg.LevelWarn, 0))
+
+	for i := 0; i < 5; i++ {
+		log.Debugf("retry %d", i)
+		log.Warn("disk full")
+	}
+	log.Warn("new failure")
+
+	entries := testlg.DecodeJSON(t, buf)
+	require.Len(t, entries, 3)
+	require.Equal(t, "retry 0", entries[0]["message"])
+	require.Equal(t, "disk full", entries[1]["message"])
+	require.Equal(t, "new failure", entries[2]["message"])
+
+	require.NoError(t, log.Sync())
+	entries = testlg.DecodeJSON(t, buf)
+	require.Len(t, entries, 2)
+	require.Equal(t, "disk full", entries[0]["message"])
+	require.Equal(t, "warn", entries[0]["level"])
+	require.Equal(t, true, entries[0]["sampled"])
+	require.EqualValues(t, 4, entries[0]["suppressed"])
+	require.Equal(t, "retry %d", entries[1]["message"])
+	require.Equal(t, "debug", entries[1]["level"])
+	require.EqualValues(t, 4, entries[1]["suppressed"])
+
+	// Nothing is pending.
+	require.NoError(t, log.Sync())
+	require.Empty(t, testlg.DecodeJSON(t, buf))
+}
+
+func TestSampler_IntervalSummary(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log := lg.NewSampler(zlog, lg.WithSampleRate(lg.LevelDebug, 0),
+		lg.WithSampleInterval(50*time.Millisecond))
+
+	for i := 0; i < 4; i++ {
+		if i == 3 {
+			time.Sleep(60 * time.Millisecond)
+		}
+		log.Debug("tick")
+	}
+
+	entries := testlg.DecodeJSON(t, buf)
+	require.Len(t, entries, 3)
+	require.Nil(t, entries[0]["sampled"])
+	require.Equal(t, true, entries[1]["sampled"])
+	require.EqualValues(t, 2, entries[1]["suppressed"])
+	require.Nil(t, entries[2]["sampled"])
+}
+
+func TestSampler_UniqueMessages(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log := lg.NewSampler(zlog, lg.WithSampleRate(lg.LevelDebug, 0.01), lg.WithHotSpots(0, 0))
+
+	// Messages that vary are sampled per call site, not per message.
+	for i := 0; i < 1000; i++ {
+		log.Debug("request ", i)
+	}
+
+	entries := testlg.DecodeJSON(t, buf)
+	require.Len(t, entries, 10)
+	require.Equal(t, "request 0", entries[0]["message"])
+	require.EqualValues(t, 990, log.Dropped())
+
+	require.NoError(t, log.Sync())
+	entries = testlg.DecodeJSON(t, buf)
+	require.Len(t, entries, 1)
+	require.Equal(t, "request 1", entries[0]["message"])
+	require.EqualValues(t, 990, entries[0]["suppressed"])
+}
+
+// logVia logs msg via log, as a logging helper would.
+func logVia(log lg.Log, msg string) {
+	lg.AddCallerSkip(log, 1).Debug(msg)
+}
+
+func TestSampler_CallerSkip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log := lg.NewSampler(zlog, lg.WithSampleRate(lg.LevelDebug, 0))
+
+	// The call sites are the callers of logVia,
+	// so the first entry from each is kept.
+	logVia(log, "a")
+	logVia(log, "b")
+	require.Len(t, testlg.DecodeJSON(t, buf), 2)
+	require.Zero(t, log.Dropped())
+}
+
+func TestSampler_Disabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, false, 0, zaplg.WithLevel(lg.LevelWarn))
+	log := lg.NewSampler(zlog, lg.WithSampleRate(lg.LevelDebug, 0))
+
+	// Entries at a disabled level are not counted as dropped,
+	// and do not produce a summary.
+	for i := 0; i < 3; i++ {
+		log.Debug("disabled")
+	}
+	require.Zero(t, log.Dropped())
+	require.NoError(t, log.Sync())
+	require.Empty(t, testlg.DecodeJSON(t, buf))
+}
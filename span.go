@@ -0,0 +1,51 @@
+package lg
+
+import "time"
+
+// Span is a lightweight operation trace: it logs an entry when the
+// operation begins, and when it ends. Use StartSpan to create a Span.
+type Span struct {
+	log   Log
+	name  string
+	start time.Time
+	ended bool
+}
+
+// StartSpan logs a begin entry at DEBUG level for the operation
+// name, and returns a Span, which the caller must end via Span.End:
+//
+//	sp := lg.StartSpan(log, "sync-users")
+//	err := syncUsers(sp.Log())
+//	sp.End(err)
+//
+// Entries have field "span" with value name.
+func StartSpan(log Log, name string) *Span {
+	sp := &Span{log: OrDiscard(log).With("span", name), name: name, start: time.Now()}
+	AddCallerSkip(sp.log, 1).Debugf("begin %s", name)
+	return sp
+}
+
+// Log returns the Span's log, which has field "span".
+func (sp *Span) Log() Log {
+	return sp.log
+}
+
+// End logs an end entry with fields "span.duration" and
+// "span.outcome". If err is nil, the outcome is "ok", and the entry
+// is logged at DEBUG level; otherwise the outcome is "error", and err
+// is logged (see WithError) at WARN level. Invocations of End after
+// the first are no-op.
+func (sp *Span) End(err error) {
+	if sp.ended {
+		return
+	}
+	sp.ended = true
+
+	log := AddCallerSkip(sp.log, 1).With("span.duration", time.Since(sp.start))
+	if err != nil {
+		WithError(log.With("span.outcome", "error"), err).Warnf("end %s", sp.name)
+		return
+	}
+
+	log.With("span.outcome", "ok").Debugf("end %s", sp.name)
+}
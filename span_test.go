@@ -0,0 +1,54 @@
+package lg_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestSpan(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+
+	sp := lg.StartSpan(log, "sync-users")
+	sp.Log().Debug("working")
+	sp.End(nil)
+	sp.End(errors.New("ignored"))
+
+	sp = lg.StartSpan(log, "sync-groups")
+	sp.End(errors.New("timeout"))
+
+	var ms []map[string]any
+	sc := bufio.NewScanner(buf)
+	for sc.Scan() {
+		m := map[string]any{}
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &m))
+		ms = append(ms, m)
+	}
+	require.Len(t, ms, 5)
+
+	require.Equal(t, "debug", ms[0]["level"])
+	require.Equal(t, "begin sync-users", ms[0]["message"])
+	require.Equal(t, "sync-users", ms[0]["span"])
+	require.Contains(t, ms[0]["caller"], "span_test.go")
+
+	require.Equal(t, "working", ms[1]["message"])
+	require.Equal(t, "sync-users", ms[1]["span"])
+
+	require.Equal(t, "debug", ms[2]["level"])
+	require.Equal(t, "end sync-users", ms[2]["message"])
+	require.Equal(t, "ok", ms[2]["span.outcome"])
+	require.Contains(t, ms[2], "span.duration")
+	require.Contains(t, ms[2]["caller"], "span_test.go")
+
+	require.Equal(t, "warn", ms[4]["level"])
+	require.Equal(t, "error", ms[4]["span.outcome"])
+	require.Equal(t, "timeout", ms[4]["error"])
+}
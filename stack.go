@@ -0,0 +1,227 @@
+package lg
+
+import (
+	"io"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Stack excerpt defaults and keys.
+const (
+	// StackKey is the key of the field that holds a stack excerpt.
+	StackKey = "stack"
+
+	// DefaultStackFrames is the default number of
+	// frames in a stack excerpt.
+	DefaultStackFrames = 5
+)
+
+// StackOption is a functional option for NewStackExcerpts.
+type StackOption func(o *stackOptions)
+
+type stackOptions struct {
+	triggers []Field
+	frames   int
+	level    Level
+}
+
+// WithStackTrigger returns a StackOption that flags logs having the
+// field key with value val: entries logged to a flagged log get a stack
+// excerpt. If val is nil, any value of key flags the log. The option
+// can be supplied multiple times.
+func WithStackTrigger(key string, val any) StackOption {
+	return func(o *stackOptions) {
+		o.triggers = append(o.triggers, Field{Key: key, Val: val})
+	}
+}
+
+// WithStackFrames returns a StackOption that sets the number of frames
+// in a stack excerpt. The default is DefaultStackFrames.
+func WithStackFrames(n int) StackOption {
+	return func(o *stackOptions) {
+		if n > 0 {
+			o.frames = n
+		}
+	}
+}
+
+// WithStackLevel returns a StackOption that sets the minimum level of
+// entries that get a stack excerpt. The default is LevelWarn.
+func WithStackLevel(level Level) StackOption {
+	return func(o *stackOptions) {
+		if level.valid() {
+			o.level = level
+		}
+	}
+}
+
+// StackExcerpts is a Log that attaches a short stack excerpt (the top
+// frames of the logging goroutine's stack) as field StackKey to the
+// entries of logs carrying a flagged field (see WithStackTrigger). This
+// gives targeted diagnostics, without the cost of a stack on every
+// warning:
+//
+//	log = lg.NewStackExcerpts(log, lg.WithStackTrigger("category", "lockcontention"))
+//	// ...
+//	log.With("category", "lockcontention").Warnf("waited %s for lock", d)
+//
+// Only fields added via With (e.g. via WithFields) are considered.
+// StackExcerpts is safe for concurrent use.
+type StackExcerpts struct {
+	log     Log
+	opts    *stackOptions
+	skip    int
+	flagged bool
+}
+
+// NewStackExcerpts returns a StackExcerpts that writes to log.
+func NewStackExcerpts(log Log, opts ...StackOption) *StackExcerpts {
+	o := &stackOptions{frames: DefaultStackFrames, level: LevelWarn}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// The caller skip accounts for the frame of StackExcerpts' methods.
+	return &StackExcerpts{log: AddCallerSkip(OrDiscard(log), 1), opts: o}
+}
+
+// flags reports whether the field key=val flags a log.
+func (o *stackOptions) flags(key string, val any) bool {
+	for _, f := range o.triggers {
+		if f.Key != key {
+			continue
+		}
+
+		if f.Val == nil {
+			return true
+		}
+
+		if val != nil && reflect.TypeOf(val).Comparable() && f.Val == val {
+			return true
+		}
+	}
+
+	return false
+}
+
+// target returns the log to which an entry at level is written:
+// if s is flagged, the log has a stack excerpt field. It must be
+// invoked directly by the exported methods.
+func (s *StackExcerpts) target(level Level) Log {
+	if !s.flagged || level < s.opts.level {
+		return s.log
+	}
+
+	// Skip runtime.Callers, target, and the exported method.
+	return s.log.With(StackKey, stackExcerpt(3+s.skip, s.opts.frames))
+}
+
+// stackExcerpt returns the top n frames of the stack, after skipping
+// skip frames, with each frame rendered on one line, as
+// "pkg.Func file.go:42".
+func stackExcerpt(skip, n int) string {
+	pcs := make([]uintptr, n)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(skip+1, pcs)])
+
+	sb := &strings.Builder{}
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			if sb.Len() > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(frame.Function[strings.LastIndexByte(frame.Function, '/')+1:])
+			sb.WriteByte(' ')
+			sb.WriteString(frame.File[strings.LastIndexByte(frame.File, '/')+1:])
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(frame.Line))
+		}
+
+		if !more {
+			return sb.String()
+		}
+	}
+}
+
+// Enabled reports whether level is enabled for the underlying log.
+func (s *StackExcerpts) Enabled(level Level) bool {
+	return Enabled(s.log, level)
+}
+
+// AddCallerSkip implements the optional interface used by AddCallerSkip.
+func (s *StackExcerpts) AddCallerSkip(skip int) Log {
+	clone := *s
+	clone.log = AddCallerSkip(s.log, skip)
+	clone.skip += skip
+	return &clone
+}
+
+// Debug implements Log.Debug.
+func (s *StackExcerpts) Debug(a ...any) {
+	s.target(LevelDebug).Debug(a...)
+}
+
+// Debugf implements Log.Debugf.
+func (s *StackExcerpts) Debugf(format string, a ...any) {
+	s.target(LevelDebug).Debugf(format, a...)
+}
+
+// Warn implements Log.Warn.
+func (s *StackExcerpts) Warn(a ...any) {
+	s.target(LevelWarn).Warn(a...)
+}
+
+// Warnf implements Log.Warnf.
+func (s *StackExcerpts) Warnf(format string, a ...any) {
+	s.target(LevelWarn).Warnf(format, a...)
+}
+
+// WarnIfError implements Log.WarnIfError.
+func (s *StackExcerpts) WarnIfError(err error) {
+	if err != nil {
+		s.target(LevelWarn).WarnIfError(err)
+	}
+}
+
+// WarnIfFuncError implements Log.WarnIfFuncError.
+func (s *StackExcerpts) WarnIfFuncError(fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	if err := fn(); err != nil {
+		s.target(LevelWarn).WarnIfError(err)
+	}
+}
+
+// WarnIfCloseError implements Log.WarnIfCloseError.
+func (s *StackExcerpts) WarnIfCloseError(c io.Closer) {
+	if c == nil {
+		return
+	}
+
+	if err := c.Close(); err != nil {
+		s.target(LevelWarn).WarnIfError(err)
+	}
+}
+
+// Error implements Log.Error.
+func (s *StackExcerpts) Error(a ...any) {
+	s.target(LevelError).Error(a...)
+}
+
+// Errorf implements Log.Errorf.
+func (s *StackExcerpts) Errorf(format string, a ...any) {
+	s.target(LevelError).Errorf(format, a...)
+}
+
+// With implements Log.With. The returned log is
+// flagged if s is flagged, or if key=val is a trigger.
+func (s *StackExcerpts) With(key string, val any) Log {
+	clone := *s
+	clone.log = s.log.With(key, val)
+	clone.flagged = s.flagged || s.opts.flags(key, val)
+	return &clone
+}
@@ -0,0 +1,65 @@
+package lg_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestStackExcerpts(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+	log := lg.NewStackExcerpts(zlog,
+		lg.WithStackTrigger("category", "lockcontention"),
+		lg.WithStackTrigger("slow", nil),
+		lg.WithStackFrames(2),
+	)
+
+	log.Warn("unflagged")
+	log.With("category", "io").Warn("other category")
+	flagged := log.With("category", "lockcontention").With("k", "v")
+	flagged.Debug("debug")
+	flagged.Warnf("waited %dms", 42)
+	flagged.Error("error")
+	log.With("slow", []int{1}).Warn("any value")
+	log.With("category", []string{"lockcontention"}).Warn("not comparable")
+
+	entries := testlg.DecodeJSON(t, buf)
+	require.Len(t, entries, 7)
+	for _, m := range entries {
+		require.Contains(t, m["caller"], "stack_test.go", m["message"])
+
+		switch m["message"] {
+		case "waited 42ms", "error", "any value":
+			stack, ok := m[lg.StackKey].(string)
+			require.True(t, ok, m["message"])
+			frames := strings.Split(stack, "\n")
+			require.Len(t, frames, 2)
+			require.Contains(t, frames[0], "_test.TestStackExcerpts stack_test.go:")
+			require.True(t, strings.HasPrefix(frames[1], "testing.tRunner "), frames[1])
+		default:
+			require.Nil(t, m[lg.StackKey], m["message"])
+		}
+	}
+}
+
+func TestStackExcerpts_Level(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zlog := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log := lg.NewStackExcerpts(zlog, lg.WithStackTrigger("category", "db"),
+		lg.WithStackLevel(lg.LevelError)).With("category", "db")
+
+	log.Warn("warn")
+	lg.AddCallerSkip(log, 0).Error("error")
+
+	entries := testlg.DecodeJSON(t, buf)
+	require.Len(t, entries, 2)
+	require.Nil(t, entries[0][lg.StackKey])
+	require.Contains(t, entries[1][lg.StackKey], "_test.TestStackExcerpts_Level stack_test.go:")
+}
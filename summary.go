@@ -0,0 +1,217 @@
+package lg
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// maxDistinctErrors is the maximum number of distinct
+// error messages tracked by a Summarizer.
+const maxDistinctErrors = 1000
+
+// MessageCount is a message and the number of
+// times it was logged. See Summarizer.
+type MessageCount struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// Summarizer is a Log that counts the entries logged at each level,
+// and the distinct messages logged at ERROR level, and logs a summary
+// entry on Close. This is useful for batch jobs and CLIs:
+//
+//	sum := lg.NewSummarizer(log, 5)
+//	defer sum.Close()
+//	run(sum)
+//
+// The child logs returned by With share the counts of their parent.
+// Summarizer is safe for concurrent use.
+type Summarizer struct {
+	log Log
+	st  *summaryState
+}
+
+type summaryState struct {
+	topN                  int
+	debug, warn, errCount atomic.Int64
+
+	mu     sync.Mutex
+	errors map[string]int
+	other  int
+	closed bool
+}
+
+// NewSummarizer returns a Summarizer that writes to log, and
+// that reports the topN most frequent ERROR messages.
+func NewSummarizer(log Log, topN int) *Summarizer {
+	return &Summarizer{
+		// The caller skip accounts for the frame of Summarizer's methods.
+		log: AddCallerSkip(OrDiscard(log), 1),
+		st:  &summaryState{topN: topN, errors: map[string]int{}},
+	}
+}
+
+// Close logs the summary entry, with fields "summary.debug",
+// "summary.warn" and "summary.error" (the count of entries at each
+// level), and "summary.top_errors" (a list of MessageCount). If any
+// ERROR entries were logged, the summary is logged at WARN level;
+// otherwise at DEBUG level. Invocations of Close after the first
+// are no-op. Close always returns nil.
+func (s *Summarizer) Close() error {
+	s.st.mu.Lock()
+	if s.st.closed {
+		s.st.mu.Unlock()
+		return nil
+	}
+	s.st.closed = true
+	top := s.st.topErrors()
+	other := s.st.other
+	s.st.mu.Unlock()
+
+	log := s.log.
+		With("summary.debug", s.st.debug.Load()).
+		With("summary.warn", s.st.warn.Load()).
+		With("summary.error", s.st.errCount.Load())
+	if len(top) > 0 {
+		log = log.With("summary.top_errors", top)
+	}
+	if other > 0 {
+		log = log.With("summary.other_errors", other)
+	}
+
+	if s.st.errCount.Load() > 0 {
+		log.Warn("log summary")
+	} else {
+		log.Debug("log summary")
+	}
+
+	return nil
+}
+
+// topErrors returns the topN most frequent error messages,
+// most frequent first. The caller must hold st.mu.
+func (st *summaryState) topErrors() []MessageCount {
+	counts := make([]MessageCount, 0, len(st.errors))
+	for msg, n := range st.errors {
+		counts = append(counts, MessageCount{Message: msg, Count: n})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Message < counts[j].Message
+	})
+
+	if len(counts) > st.topN {
+		counts = counts[:st.topN]
+	}
+
+	return counts
+}
+
+func (st *summaryState) countError(msg string) {
+	st.errCount.Add(1)
+	if st.topN <= 0 {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, ok := st.errors[msg]; ok || len(st.errors) < maxDistinctErrors {
+		st.errors[msg]++
+		return
+	}
+
+	st.other++
+}
+
+// Enabled reports whether level is enabled for the underlying log.
+func (s *Summarizer) Enabled(level Level) bool {
+	return Enabled(s.log, level)
+}
+
+// AddCallerSkip implements the optional interface used by AddCallerSkip.
+func (s *Summarizer) AddCallerSkip(skip int) Log {
+	return &Summarizer{log: AddCallerSkip(s.log, skip), st: s.st}
+}
+
+// Debug implements Log.Debug.
+func (s *Summarizer) Debug(a ...any) {
+	s.st.debug.Add(1)
+	s.log.Debug(a...)
+}
+
+// Debugf implements Log.Debugf.
+func (s *Summarizer) Debugf(format string, a ...any) {
+	s.st.debug.Add(1)
+	s.log.Debugf(format, a...)
+}
+
+// Warn implements Log.Warn.
+func (s *Summarizer) Warn(a ...any) {
+	s.st.warn.Add(1)
+	s.log.Warn(a...)
+}
+
+// Warnf implements Log.Warnf.
+func (s *Summarizer) Warnf(format string, a ...any) {
+	s.st.warn.Add(1)
+	s.log.Warnf(format, a...)
+}
+
+// WarnIfError implements Log.WarnIfError.
+func (s *Summarizer) WarnIfError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.st.warn.Add(1)
+	s.log.WarnIfError(err)
+}
+
+// WarnIfFuncError implements Log.WarnIfFuncError.
+func (s *Summarizer) WarnIfFuncError(fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	if err := fn(); err != nil {
+		s.st.warn.Add(1)
+		s.log.WarnIfError(err)
+	}
+}
+
+// WarnIfCloseError implements Log.WarnIfCloseError.
+func (s *Summarizer) WarnIfCloseError(c io.Closer) {
+	if c == nil {
+		return
+	}
+
+	if err := c.Close(); err != nil {
+		s.st.warn.Add(1)
+		s.log.WarnIfError(err)
+	}
+}
+
+// Error implements Log.Error.
+func (s *Summarizer) Error(a ...any) {
+	s.st.countError(fmt.Sprint(a...))
+	s.log.Error(a...)
+}
+
+// Errorf implements Log.Errorf.
+func (s *Summarizer) Errorf(format string, a ...any) {
+	s.st.countError(fmt.Sprintf(format, a...))
+	s.log.Errorf(format, a...)
+}
+
+// With implements Log.With. The returned log
+// shares the counts of s.
+func (s *Summarizer) With(key string, val any) Log {
+	return &Summarizer{log: s.log.With(key, val), st: s.st}
+}
@@ -0,0 +1,64 @@
+package lg_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestSummarizer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sum := lg.NewSummarizer(zaplg.NewWith(buf, "json", false, false, true, true, 0), 2)
+
+	sum.Debug("a")
+	sum.With("k", "v").Debugf("b %d", 1)
+	sum.Warn("c")
+	sum.WarnIfError(nil)
+	sum.WarnIfFuncError(func() error { return errors.New("d") })
+	for i := 0; i < 3; i++ {
+		sum.Errorf("row %d: invalid", 7)
+	}
+	sum.Error("connection reset")
+	sum.Error("connection reset")
+	sum.Error("timeout")
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 10)
+	require.Contains(t, ms[0]["caller"], "summary_test.go")
+	require.Contains(t, ms[3]["caller"], "summary_test.go")
+
+	require.NoError(t, sum.Close())
+	require.NoError(t, sum.Close())
+
+	ms = testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 1)
+	m := ms[0]
+	require.Equal(t, "warn", m["level"])
+	require.Equal(t, "log summary", m["message"])
+	require.Contains(t, m["caller"], "summary_test.go")
+	require.Equal(t, float64(2), m["summary.debug"])
+	require.Equal(t, float64(2), m["summary.warn"])
+	require.Equal(t, float64(6), m["summary.error"])
+	require.Equal(t, []any{
+		map[string]any{"message": "row 7: invalid", "count": float64(3)},
+		map[string]any{"message": "connection reset", "count": float64(2)},
+	}, m["summary.top_errors"])
+}
+
+func TestSummarizer_NoErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sum := lg.NewSummarizer(zaplg.NewWith(buf, "json", false, false, true, false, 0), 5)
+	sum.Debug("a")
+	require.NoError(t, sum.Close())
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	require.Equal(t, "debug", ms[1]["level"])
+	require.NotContains(t, ms[1], "summary.top_errors")
+}
@@ -0,0 +1,63 @@
+package testlg
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// watches holds a *warnings for each test registered
+// via AssertNoWarnings.
+var watches sync.Map // map[testing.TB]*warnings
+
+// warnings holds the WARN and ERROR entries logged for a test.
+type warnings struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+// AssertNoWarnings registers a t.Cleanup func that marks the test as
+// failed if any WARN or ERROR entries were logged to t via a testlg
+// Log during the test. This is useful for enforcing clean shutdown
+// paths. Entries logged by cleanup funcs that are registered after
+// AssertNoWarnings (and thus run before its cleanup func) are also
+// checked, so invoke AssertNoWarnings at the start of the test:
+//
+//	func TestServer(t *testing.T) {
+//	  testlg.AssertNoWarnings(t)
+//	  srv := NewServer(testlg.New(t))
+//	  t.Cleanup(srv.Shutdown)
+//	  // ...
+//	}
+//
+// Entries logged to subtests are not checked, unless
+// AssertNoWarnings is also invoked for the subtest.
+func AssertNoWarnings(t testing.TB) {
+	t.Helper()
+
+	w := &warnings{}
+	watches.Store(t, w)
+	t.Cleanup(func() {
+		watches.Delete(t)
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if len(w.entries) > 0 {
+			t.Errorf("testlg: %d WARN or ERROR entries logged:\n%s",
+				len(w.entries), strings.Join(w.entries, "\n"))
+		}
+	})
+}
+
+// recordWarning records msg if AssertNoWarnings was invoked for t.
+func recordWarning(t testing.TB, msg string) {
+	v, ok := watches.Load(t)
+	if !ok {
+		return
+	}
+
+	w := v.(*warnings) //nolint:errcheck // watches only holds *warnings
+	w.mu.Lock()
+	w.entries = append(w.entries, msg)
+	w.mu.Unlock()
+}
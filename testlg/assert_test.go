@@ -0,0 +1,44 @@
+package testlg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+// cleanupTB is a recordingTB that runs its cleanup
+// funcs when done is invoked.
+type cleanupTB struct {
+	*recordingTB
+	cleanups []func()
+}
+
+func (c *cleanupTB) Cleanup(fn func()) {
+	c.cleanups = append(c.cleanups, fn)
+}
+
+func (c *cleanupTB) done() {
+	for i := len(c.cleanups) - 1; i >= 0; i-- {
+		c.cleanups[i]()
+	}
+}
+
+func TestAssertNoWarnings(t *testing.T) {
+	tb := &cleanupTB{recordingTB: &recordingTB{TB: t}}
+	testlg.AssertNoWarnings(tb)
+	log := testlg.New(tb)
+	log.Debug("fine")
+	tb.done()
+	require.Empty(t, tb.errs)
+
+	tb = &cleanupTB{recordingTB: &recordingTB{TB: t}}
+	testlg.AssertNoWarnings(tb)
+	log = testlg.New(tb).With("k", "v")
+	tb.Cleanup(func() { log.Warn("unclean shutdown") })
+	tb.done()
+	require.Len(t, tb.errs, 1)
+	require.Contains(t, tb.errs[0], "1 WARN or ERROR entries logged")
+	require.Contains(t, tb.errs[0], "unclean shutdown")
+}
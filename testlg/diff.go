@@ -0,0 +1,91 @@
+package testlg
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// timeKeys are the keys of JSON entries that Normalize removes.
+var timeKeys = []string{"timestamp", "time", "ts", "@timestamp", "t"}
+
+var (
+	// timePrefixRx matches a leading timestamp of a text entry: RFC3339,
+	// time of day, or elapsed (see WithElapsed).
+	timePrefixRx = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})` +
+		`|\d{2}:\d{2}:\d{2}(\.\d+)?|\+\d+\.\d+s)\s*`)
+	pointerRx = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	portRx    = regexp.MustCompile(`((?:\d{1,3}\.){3}\d{1,3}|localhost|\[[0-9a-fA-F:]+\]):\d+`)
+)
+
+// Normalize returns log output s with the variable parts of each line
+// removed or masked, such that it can be compared against a golden
+// capture: timestamps are removed; the keys of JSON entries are
+// sorted; pointers such as 0xc000012345 are replaced with "0xPTR";
+// and ports of IP addresses and localhost are replaced with "PORT".
+func Normalize(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = normalizeLine(line)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func normalizeLine(line string) string {
+	var m map[string]any
+	if strings.HasPrefix(line, "{") && json.Unmarshal([]byte(line), &m) == nil {
+		for _, k := range timeKeys {
+			delete(m, k)
+		}
+		// json.Marshal sorts map keys.
+		if b, err := json.Marshal(m); err == nil {
+			line = string(b)
+		}
+	} else {
+		line = timePrefixRx.ReplaceAllString(line, "")
+	}
+
+	line = pointerRx.ReplaceAllString(line, "0xPTR")
+	return portRx.ReplaceAllString(line, "$1:PORT")
+}
+
+// Diff returns a unified diff of the log captures want and got, after
+// each is normalized via Normalize. If they are equal, Diff returns
+// the empty string.
+func Diff(want, got string) string {
+	want, got = Normalize(want), Normalize(got)
+	if want == got {
+		return ""
+	}
+
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(want),
+		B:        difflib.SplitLines(got),
+		FromFile: "want",
+		ToFile:   "got",
+		Context:  3,
+	})
+	return diff
+}
+
+// AssertLogsEqual marks the test as failed, reporting a unified diff,
+// if the log captures want and got differ after normalization. It is
+// typically used to compare log output against a golden file:
+//
+//	want, err := os.ReadFile("testdata/server.golden.log")
+//	require.NoError(t, err)
+//	testlg.AssertLogsEqual(t, string(want), buf.String())
+func AssertLogsEqual(t testing.TB, want, got string) bool {
+	t.Helper()
+
+	if diff := Diff(want, got); diff != "" {
+		t.Errorf("testlg: log captures differ:\n%s", diff)
+		return false
+	}
+
+	return true
+}
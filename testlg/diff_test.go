@@ -0,0 +1,46 @@
+package testlg_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestNormalize(t *testing.T) {
+	in := `{"timestamp":"2026-10-16T12:00:00.000Z","message":"dial 127.0.0.1:54321","level":"debug"}
+2026-10-16T12:00:00.123Z	WARN	conn 0xc000012345 to localhost:8080
++0.042s	DEBUG	hello
+`
+	want := `{"level":"debug","message":"dial 127.0.0.1:PORT"}
+WARN	conn 0xPTR to localhost:PORT
+DEBUG	hello
+`
+	require.Equal(t, want, testlg.Normalize(in))
+}
+
+func TestDiff(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", true, true, true, false, 0)
+	log.With("port", 1).Debug("listening on 127.0.0.1:40001")
+	log.Warn("shutting down")
+
+	golden := `{"message":"listening on 127.0.0.1:9999","port":1,"level":"debug","timestamp":"x"}
+{"level":"warn","message":"shutting down"}
+`
+	require.Empty(t, testlg.Diff(golden, buf.String()))
+	require.True(t, testlg.AssertLogsEqual(t, golden, buf.String()))
+
+	log.Error("unexpected")
+	diff := testlg.Diff(golden, buf.String())
+	require.Contains(t, diff, "--- want")
+	require.Contains(t, diff, "+++ got")
+	require.Contains(t, diff, `+{"level":"error","message":"unexpected"}`)
+
+	tb := &recordingTB{TB: t}
+	require.False(t, testlg.AssertLogsEqual(tb, golden, buf.String()))
+	require.Len(t, tb.errs, 1)
+}
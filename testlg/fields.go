@@ -0,0 +1,71 @@
+package testlg
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// WithJSON returns an Option that causes entries to be generated in
+// JSON format, and recorded, so that their fields are available via
+// Entries. Note that this option uses zaplg as the backing impl,
+// overriding the factoryFn passed to NewWith, or FactoryFn.
+func WithJSON() Option {
+	return func(o *options) {
+		o.json = true
+	}
+}
+
+// Entries returns the entries logged via l, and via the Logs derived
+// from l via With, as maps of key to value, decoded by encoding/json.
+// Thus, numbers are float64, and can be asserted like so:
+//
+//	log := testlg.NewWith(t, testlg.FactoryFn, testlg.WithJSON())
+//	Retry(log, op)
+//	ents := log.Entries()
+//	require.EqualValues(t, 3, ents[len(ents)-1]["retries"])
+//
+// Entries are only recorded with WithJSON; the keys, such as
+// "message" and "level", are determined by the backing impl.
+func (l *Log) Entries() []map[string]any {
+	return l.opts.recorded.entries()
+}
+
+// Entries returns log.Entries if log is a *Log, and nil otherwise.
+func Entries(log lg.Log) []map[string]any {
+	if l, ok := log.(*Log); ok {
+		return l.Entries()
+	}
+
+	return nil
+}
+
+// recorded holds decoded JSON entries.
+type recorded struct {
+	mu   sync.Mutex
+	ents []map[string]any
+}
+
+// add decodes msg, and records it if it is a JSON object.
+func (r *recorded) add(msg string) {
+	if !strings.HasPrefix(msg, "{") {
+		return
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal([]byte(msg), &m); err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.ents = append(r.ents, m)
+	r.mu.Unlock()
+}
+
+func (r *recorded) entries() []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]any(nil), r.ents...)
+}
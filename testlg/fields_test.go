@@ -0,0 +1,50 @@
+package testlg_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestEntries(t *testing.T) {
+	log := testlg.NewWith(t, testlg.FactoryFn, testlg.WithJSON())
+	log.With("retries", 3).With("op", "fetch").Warn("gave up")
+	log.Debugf("hello %s", "world")
+
+	ents := log.Entries()
+	require.Len(t, ents, 2)
+	require.EqualValues(t, 3, ents[0]["retries"])
+	require.Equal(t, "fetch", ents[0]["op"])
+	require.Equal(t, "warn", ents[0]["level"])
+	require.Equal(t, "gave up", ents[0]["message"])
+	require.Equal(t, "hello world", ents[1]["message"])
+	require.Equal(t, ents, testlg.Entries(log))
+
+	// Text format entries are not recorded.
+	require.Empty(t, testlg.Entries(testlg.New(t)))
+
+	// Nor are entries without WithJSON, even if the impl generates JSON.
+	jsonLog := testlg.NewWith(t, func(w io.Writer) lg.Log { return testlg.NewJSON(w) })
+	jsonLog.Debug("not recorded")
+	require.Empty(t, jsonLog.Entries())
+}
+
+func TestDecodeJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := testlg.NewJSON(buf)
+	log.With("retries", 3).Warn("gave up")
+	testlg.NewJSON(buf, zaplg.WithLevel(lg.LevelWarn)).Debug("dropped")
+
+	ents := testlg.DecodeJSON(t, buf)
+	require.Len(t, ents, 1)
+	require.EqualValues(t, 3, ents[0]["retries"])
+	require.Equal(t, "warn", ents[0]["level"])
+	require.Equal(t, "gave up", ents[0]["message"])
+	require.Contains(t, ents[0]["caller"], "fields_test.go")
+}
@@ -0,0 +1,53 @@
+package testlg
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// NewJSON returns a Log that writes entries to w in JSON format,
+// with the level and caller, but not the timestamp. Use DecodeJSON
+// to decode the entries, for example, to assert on the fields logged
+// by middleware:
+//
+//	buf := &bytes.Buffer{}
+//	h := lghttp.Middleware(testlg.NewJSON(buf))(handler)
+//	h.ServeHTTP(rec, req)
+//	ents := testlg.DecodeJSON(t, buf)
+//	require.EqualValues(t, 200, ents[0]["http.status"])
+//
+// Unlike WithJSON, the entries are not passed to the testing
+// framework, and opts configure the zaplg impl.
+func NewJSON(w io.Writer, opts ...zaplg.Option) lg.Log {
+	return zaplg.NewWith(w, "json", false, false, true, true, 0, opts...)
+}
+
+// DecodeJSON reads the JSON entries, one per line, from r (such as
+// the buffer passed to NewJSON), and returns them as maps of key to
+// value, decoded by encoding/json. The test fails immediately if an
+// entry can't be decoded.
+func DecodeJSON(t testing.TB, r io.Reader) []map[string]any {
+	t.Helper()
+
+	var ents []map[string]any
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		m := map[string]any{}
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+			t.Fatalf("testlg: decode entry %q: %v", sc.Text(), err)
+		}
+		ents = append(ents, m)
+	}
+
+	if err := sc.Err(); err != nil {
+		t.Fatalf("testlg: read entries: %v", err)
+	}
+
+	return ents
+}
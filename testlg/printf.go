@@ -0,0 +1,53 @@
+package testlg
+
+import (
+	"sync"
+	"testing"
+)
+
+// PrintfLog adapts Log to the Printf-style logger interface used
+// by libraries such as testcontainers-go, whose Logging interface
+// is satisfied by PrintfLog:
+//
+//	req := testcontainers.GenericContainerRequest{
+//	  ContainerRequest: req,
+//	  Logger:           testlg.NewPrintf(t),
+//	}
+//
+// Messages are logged at DEBUG level. Such libraries often log from
+// background goroutines, which may outlive the test; messages
+// received after the test has completed are discarded, as it is
+// not permitted to invoke t.Log after the test completes.
+type PrintfLog struct {
+	log *Log
+
+	// mu guards done, and is held while logging, so that
+	// the test can't complete between the check of done
+	// and the invocation of t.Log.
+	mu   sync.Mutex
+	done bool
+}
+
+// NewPrintf returns a PrintfLog that pipes output to t.
+func NewPrintf(t testing.TB) *PrintfLog {
+	p := &PrintfLog{log: NewWith(t, FactoryFn)}
+	t.Cleanup(func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.done = true
+	})
+	return p
+}
+
+// Printf logs at DEBUG level to t.Log.
+func (p *PrintfLog) Printf(format string, v ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done {
+		return
+	}
+
+	p.log.t.Helper()
+	p.log.Debugf(format, v...)
+}
@@ -0,0 +1,54 @@
+package testlg_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+// logging is testcontainers-go's Logging interface.
+type logging interface {
+	Printf(format string, v ...any)
+}
+
+var _ logging = (*testlg.PrintfLog)(nil)
+
+// recordTB is a testing.TB that records calls to Log and Cleanup.
+type recordTB struct {
+	testing.TB
+	logs     []string
+	cleanups []func()
+}
+
+func (r *recordTB) Helper() {}
+
+func (r *recordTB) Log(args ...any) {
+	r.logs = append(r.logs, fmt.Sprint(args...))
+}
+
+func (r *recordTB) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func TestNewPrintf(t *testing.T) {
+	tb := &recordTB{TB: t}
+	p := testlg.NewPrintf(tb)
+
+	p.Printf("Creating container for image %s", "redis:7")
+	require.Len(t, tb.logs, 1)
+	require.Contains(t, tb.logs[0], "DEBUG")
+	require.Contains(t, tb.logs[0], "Creating container for image redis:7")
+
+	// After the test completes, output is discarded.
+	for _, fn := range tb.cleanups {
+		fn()
+	}
+	p.Printf("Terminating container")
+	require.Len(t, tb.logs, 1)
+
+	// Real testing.T.
+	testlg.NewPrintf(t).Printf("Container started: %s", "abc123")
+}
@@ -0,0 +1,127 @@
+package testlg
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+// RecorderChanSize is the capacity of the channel returned
+// by Recorder.C.
+const RecorderChanSize = 1024
+
+// Recorder is a lg.Log that records entries in memory, for asserting
+// on the log output of code under test. Child logs created via With
+// record to the same Recorder. For code that logs from other
+// goroutines, use WaitFor or C rather than sleeping:
+//
+//	rec := testlg.NewRecorder()
+//	go worker(rec)
+//	_, ok := rec.WaitFor(func(e lgcore.Entry) bool { return e.Msg == "done" }, time.Second)
+//	require.True(t, ok)
+type Recorder struct {
+	*lgcore.Log
+
+	mu      sync.Mutex
+	entries []lgcore.Entry
+	changed chan struct{}
+	ch      chan lgcore.Entry
+
+	// gen is incremented by Reset, so that WaitFor
+	// can restart its scan of entries.
+	gen uint64
+}
+
+// NewRecorder returns a new Recorder. The opts (such as
+// lgcore.WithLevel) configure the recording log.
+func NewRecorder(opts ...lgcore.Option) *Recorder {
+	r := &Recorder{changed: make(chan struct{}), ch: make(chan lgcore.Entry, RecorderChanSize)}
+	r.Log = lgcore.New(nopWriter{}, lgcore.EncoderFunc(r.record), opts...)
+	return r
+}
+
+// nopWriter discards the (empty) output of the Recorder's encoder.
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// record is the Recorder's encoder: it records ent.
+func (r *Recorder) record(_ *bytes.Buffer, ent lgcore.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, ent)
+	close(r.changed)
+	r.changed = make(chan struct{})
+
+	select {
+	case r.ch <- ent:
+	default:
+	}
+
+	return nil
+}
+
+// Entries returns a copy of the recorded entries.
+func (r *Recorder) Entries() []lgcore.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]lgcore.Entry(nil), r.entries...)
+}
+
+// Reset discards the recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = nil
+	r.gen++
+}
+
+// C returns a channel that receives each recorded entry. The channel
+// has capacity RecorderChanSize: if it is full, entries are not sent
+// to it (but are still recorded), so that logging never blocks.
+func (r *Recorder) C() <-chan lgcore.Entry {
+	return r.ch
+}
+
+// WaitFor blocks until an entry matching pred has been recorded,
+// or until timeout elapses. Entries recorded before the call are
+// also considered. It returns the first matching entry, and true;
+// or false if timeout elapsed.
+func (r *Recorder) WaitFor(pred func(ent lgcore.Entry) bool, timeout time.Duration) (lgcore.Entry, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	r.mu.Lock()
+	gen := r.gen
+	r.mu.Unlock()
+
+	var i int
+	for {
+		r.mu.Lock()
+		if r.gen != gen {
+			gen, i = r.gen, 0
+		}
+		for ; i < len(r.entries); i++ {
+			if pred(r.entries[i]) {
+				ent := r.entries[i]
+				r.mu.Unlock()
+				return ent, true
+			}
+		}
+		changed := r.changed
+		r.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return lgcore.Entry{}, false
+		}
+	}
+}
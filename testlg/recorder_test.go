@@ -0,0 +1,87 @@
+package testlg_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+	"github.com/neilotoole/lg/v2/testlg"
+)
+
+var _ lg.Log = (*testlg.Recorder)(nil)
+
+func TestRecorder(t *testing.T) {
+	rec := testlg.NewRecorder(lgcore.WithLevel(lg.LevelWarn))
+	rec.Debug("disabled")
+	rec.With("k", "v").Warnf("hello %d", 1)
+	rec.WarnIfError(errors.New("boom"))
+
+	ents := rec.Entries()
+	require.Len(t, ents, 2)
+	require.Equal(t, lg.LevelWarn, ents[0].Level)
+	require.Equal(t, "hello 1", ents[0].Msg)
+	require.Equal(t, []lg.Field{{Key: "k", Val: "v"}}, ents[0].Fields)
+	require.Contains(t, ents[0].Caller.String(), "testlg/recorder_test.go:")
+	require.Equal(t, "boom", ents[1].Msg)
+
+	require.Equal(t, "hello 1", (<-rec.C()).Msg)
+	require.Equal(t, "boom", (<-rec.C()).Msg)
+
+	rec.Reset()
+	require.Empty(t, rec.Entries())
+}
+
+func TestRecorder_WaitFor(t *testing.T) {
+	rec := testlg.NewRecorder()
+	rec.Debug("before")
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(5 * time.Millisecond)
+			rec.With("i", i).Debug("tick")
+		}
+		rec.Error("done")
+	}()
+
+	ent, ok := rec.WaitFor(func(e lgcore.Entry) bool { return e.Msg == "before" }, time.Second)
+	require.True(t, ok)
+	require.Equal(t, "before", ent.Msg)
+
+	ent, ok = rec.WaitFor(func(e lgcore.Entry) bool { return e.Level == lg.LevelError }, 5*time.Second)
+	require.True(t, ok)
+	require.Equal(t, "done", ent.Msg)
+	require.Len(t, rec.Entries(), 5)
+
+	_, ok = rec.WaitFor(func(e lgcore.Entry) bool { return e.Msg == "never" }, 10*time.Millisecond)
+	require.False(t, ok)
+}
+
+func TestRecorder_WaitFor_reset(t *testing.T) {
+	rec := testlg.NewRecorder()
+	rec.Debug("a")
+	rec.Debug("b")
+
+	scanned := make(chan struct{}, 1)
+	result := make(chan lgcore.Entry, 1)
+	go func() {
+		ent, _ := rec.WaitFor(func(e lgcore.Entry) bool {
+			if e.Msg == "b" {
+				scanned <- struct{}{}
+			}
+			return e.Msg == "c"
+		}, 5*time.Second)
+		result <- ent
+	}()
+
+	// After WaitFor has scanned the entries, Reset
+	// discards them, and fewer entries are recorded.
+	<-scanned
+	rec.Reset()
+	rec.Debug("c")
+
+	require.Equal(t, "c", (<-result).Msg)
+}
@@ -24,13 +24,30 @@
 // delegated to a backing log impl (zaplg by default).
 // An alternative impl can be set by passing a log factory func
 // to NewWith, or by changing the testlg.FactoryFn package variable.
+//
+// For fuzz tests, derive a Log for each iteration via ForTest,
+// and consider WithMaxEntries to bound the output of each iteration:
+//
+//	func FuzzParse(f *testing.F) {
+//	  log := testlg.NewWith(f, testlg.FactoryFn, testlg.WithMaxEntries(20))
+//	  f.Fuzz(func(t *testing.T, s string) {
+//	    _, _ = Parse(testlg.ForTest(log, t), s)
+//	  })
+//	}
+//
+// For Example functions, whose output must be deterministic, see
+// package exlg.
 package testlg
 
 import (
 	"bytes"
 	"io"
+	"os"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/neilotoole/lg/v2"
 	"github.com/neilotoole/lg/v2/zaplg"
@@ -60,72 +77,225 @@ type Log struct {
 	t    testing.TB
 	mu   sync.Mutex
 	impl lg.Log
-	buf  *bytes.Buffer
+	w    *bufWriter
 
 	factoryFn func(writer io.Writer) lg.Log
 	kvs       []keyVal
+	opts      options
+}
+
+// options holds the configuration set via Option.
+type options struct {
+	failOnError bool
+	maxEntries  int
+	stderr      bool
+	elapsed     bool
+	json        bool
+
+	// entries counts the entries passed to t. It is shared
+	// by a Log and its children created via With.
+	entries *atomic.Int64
+
+	// recorded holds the entries in JSON format, for Entries.
+	// It is shared by a Log and its children created via With.
+	recorded *recorded
+}
+
+// Option is a functional option for New and NewWith.
+type Option func(o *options)
+
+// WithFailOnError returns an Option that causes ERROR entries
+// to be passed to t.Error instead of t.Log, so that the test is
+// marked as failed, and the entries are highlighted by the testing
+// framework. DEBUG and WARN entries are still passed to t.Log.
+func WithFailOnError() Option {
+	return func(o *options) {
+		o.failOnError = true
+	}
+}
+
+// WithMaxEntries returns an Option that limits the number of entries
+// passed to t to n: after that, a single notice is logged, and
+// further entries are discarded. This is useful for fuzz tests, where
+// each iteration may produce verbose output. Logs created via With
+// share the limit; each Log created via ForTest has its own limit.
+// ERROR entries passed to t.Error via WithFailOnError are not
+// discarded. If n is zero (the default), there is no limit.
+func WithMaxEntries(n int) Option {
+	return func(o *options) {
+		if n >= 0 {
+			o.maxEntries = n
+		}
+	}
+}
+
+// EnvStderr is the name of the environment variable that, if set
+// to a true value (as per strconv.ParseBool), has the same effect
+// as passing WithStderr to New or NewWith.
+const EnvStderr = "LG_TEST_STDERR"
+
+// WithStderr returns an Option that causes each entry to also be
+// written immediately to os.Stderr, prefixed with the test name.
+// Output passed to t.Log is only printed when the test completes:
+// this is an escape hatch for debugging tests that never complete,
+// such as when deadlocked. See also EnvStderr.
+func WithStderr() Option {
+	return func(o *options) {
+		o.stderr = true
+	}
+}
+
+// WithElapsed returns an Option that causes timestamps to be rendered
+// as the duration elapsed since the Log was created (typically at the
+// start of the test), e.g. "+0.042s", instead of the wall-clock time.
+// Note that this option uses zaplg as the backing impl, overriding
+// the factoryFn passed to NewWith, or FactoryFn.
+func WithElapsed() Option {
+	return func(o *options) {
+		o.elapsed = true
+	}
+}
+
+// zaplgFactoryFn returns a factory func for the zaplg
+// backing impl, configured as per o.
+func zaplgFactoryFn(o options) func(io.Writer) lg.Log {
+	format := "testing"
+	if o.json {
+		format = "json"
+	}
+
+	var zopts []zaplg.Option
+	if o.elapsed {
+		zopts = append(zopts, zaplg.WithElapsedTime(time.Now()))
+	}
+
+	return func(w io.Writer) lg.Log {
+		return zaplg.NewWith(w, format, true, true, true, true, 1, zopts...)
+	}
 }
 
 // New returns a log that pipes output to t.
-func New(t testing.TB) lg.Log {
-	return NewWith(t, FactoryFn)
+func New(t testing.TB, opts ...Option) lg.Log {
+	return NewWith(t, FactoryFn, opts...)
 }
 
 // NewWith returns a Log that pipes output to t, using
 // the backing lg.Log instances returned by factoryFn
 // to generate log messages.
-func NewWith(t testing.TB, factoryFn func(io.Writer) lg.Log) *Log {
-	tl := &Log{t: t, buf: &bytes.Buffer{}, factoryFn: factoryFn}
-	tl.impl = factoryFn(tl.buf)
+func NewWith(t testing.TB, factoryFn func(io.Writer) lg.Log, opts ...Option) *Log {
+	tl := &Log{t: t, w: &bufWriter{}, factoryFn: factoryFn}
+	if ok, _ := strconv.ParseBool(os.Getenv(EnvStderr)); ok {
+		tl.opts.stderr = true
+	}
+	for _, opt := range opts {
+		opt(&tl.opts)
+	}
+	tl.opts.entries = &atomic.Int64{}
+	tl.opts.recorded = &recorded{}
+	if tl.opts.elapsed || tl.opts.json {
+		tl.factoryFn = zaplgFactoryFn(tl.opts)
+	}
+	tl.impl = tl.factoryFn(tl.w)
 	return tl
 }
 
-// Debug logs at DEBUG level to t.Log.
-func (l *Log) Debug(a ...any) {
+// bufPool is the pool of buffers that the backing
+// log impls write to.
+var bufPool = sync.Pool{New: func() any { return &bytes.Buffer{} }}
+
+// bufWriter is the io.Writer passed to the backing log impl. For
+// the duration of each log call, buf is set to a pooled buffer.
+type bufWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w *bufWriter) Write(p []byte) (int, error) {
+	if w.buf == nil {
+		// Shouldn't happen: the impl wrote outside of a log call.
+		w.buf = bufPool.Get().(*bytes.Buffer) //nolint:errcheck // pool only holds *bytes.Buffer
+	}
+
+	return w.buf.Write(p)
+}
+
+// acquire locks l, and sets l.w to write to a pooled buffer.
+// It must be followed by a call to release.
+func (l *Log) acquire() {
 	l.mu.Lock()
-	defer l.mu.Unlock()
+	l.w.buf = bufPool.Get().(*bytes.Buffer) //nolint:errcheck // pool only holds *bytes.Buffer
+}
 
-	l.impl.Debug(a...)
+// release passes the output written since acquire at level to
+// t.Log (or to t.Error, if level is ERROR and WithFailOnError is set),
+// returns the buffer to the pool, and unlocks l.
+func (l *Log) release(level lg.Level) {
+	l.t.Helper()
+
+	buf := l.w.buf
+	l.w.buf = nil
+	if buf.Len() > 0 {
+		msg := string(stripNewLineEnding(buf.Bytes()))
+		if l.opts.stderr {
+			_, _ = io.WriteString(os.Stderr, l.t.Name()+": "+msg+"\n")
+		}
+
+		if level >= lg.LevelWarn {
+			recordWarning(l.t, msg)
+		}
+		if l.opts.json {
+			l.opts.recorded.add(msg)
+		}
+
+		switch {
+		case level == lg.LevelError && l.opts.failOnError:
+			l.t.Error(msg)
+		case l.opts.maxEntries == 0:
+			l.t.Log(msg)
+		default:
+			n := l.opts.entries.Add(1)
+			if n <= int64(l.opts.maxEntries) {
+				l.t.Log(msg)
+			} else if n == int64(l.opts.maxEntries)+1 {
+				l.t.Log("testlg: max entries (" + strconv.Itoa(l.opts.maxEntries) + ") reached: discarding further entries")
+			}
+		}
+	}
 
+	buf.Reset()
+	bufPool.Put(buf)
+	l.mu.Unlock()
+}
+
+// Debug logs at DEBUG level to t.Log.
+func (l *Log) Debug(a ...any) {
 	l.t.Helper()
-	l.t.Log(string(stripNewLineEnding(l.buf.Bytes())))
-	l.buf.Reset()
+	l.acquire()
+	l.impl.Debug(a...)
+	l.release(lg.LevelDebug)
 }
 
 // Debugf logs at DEBUG level to t.Log.
 func (l *Log) Debugf(format string, a ...any) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	l.impl.Debugf(format, a...)
-
 	l.t.Helper()
-	l.t.Log(string(stripNewLineEnding(l.buf.Bytes())))
-	l.buf.Reset()
+	l.acquire()
+	l.impl.Debugf(format, a...)
+	l.release(lg.LevelDebug)
 }
 
 // Warn implements Log.Warn.
 func (l *Log) Warn(a ...any) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	l.impl.Warn(a...)
-
 	l.t.Helper()
-	l.t.Log(string(stripNewLineEnding(l.buf.Bytes())))
-	l.buf.Reset()
+	l.acquire()
+	l.impl.Warn(a...)
+	l.release(lg.LevelWarn)
 }
 
 // Warnf implements Log.Warnf.
 func (l *Log) Warnf(format string, a ...any) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	l.impl.Warnf(format, a...)
-
 	l.t.Helper()
-	l.t.Log(string(stripNewLineEnding(l.buf.Bytes())))
-	l.buf.Reset()
+	l.acquire()
+	l.impl.Warnf(format, a...)
+	l.release(lg.LevelWarn)
 }
 
 // WarnIfError implements Log.WarnIfError.
@@ -134,14 +304,10 @@ func (l *Log) WarnIfError(err error) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	l.impl.Warn(err)
-
 	l.t.Helper()
-	l.t.Log(string(stripNewLineEnding(l.buf.Bytes())))
-	l.buf.Reset()
+	l.acquire()
+	lg.WithFields(l.impl, lg.ErrorFields(err)...).Warn(err)
+	l.release(lg.LevelWarn)
 }
 
 // WarnIfFuncError implements Log.WarnIfFuncError.
@@ -155,14 +321,10 @@ func (l *Log) WarnIfFuncError(fn func() error) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	l.impl.Warn(err)
-	output, _ := io.ReadAll(l.buf)
-
 	l.t.Helper()
-	l.t.Log(string(stripNewLineEnding(output)))
+	l.acquire()
+	lg.WithFields(l.impl, lg.ErrorFields(err)...).Warn(err)
+	l.release(lg.LevelWarn)
 }
 
 // WarnIfCloseError implements Log.WarnIfCloseError.
@@ -176,42 +338,38 @@ func (l *Log) WarnIfCloseError(c io.Closer) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	l.impl.Warn(err)
-	output, _ := io.ReadAll(l.buf)
-
 	l.t.Helper()
-	l.t.Log(string(stripNewLineEnding(output)))
+	l.acquire()
+	lg.WithFields(l.impl, lg.ErrorFields(err)...).Warn(err)
+	l.release(lg.LevelWarn)
 }
 
 // Error implements Log.Error.
 func (l *Log) Error(a ...any) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	l.impl.Error(a...)
-	output, _ := io.ReadAll(l.buf)
-
 	l.t.Helper()
-	l.t.Log(string(stripNewLineEnding(output)))
+	l.acquire()
+	l.impl.Error(a...)
+	l.release(lg.LevelError)
 }
 
 // Errorf implements Log.Errorf.
 func (l *Log) Errorf(format string, v ...any) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
+	l.t.Helper()
+	l.acquire()
 	l.impl.Errorf(format, v...)
-	output, _ := io.ReadAll(l.buf)
+	l.release(lg.LevelError)
+}
 
-	l.t.Helper()
-	l.t.Log(string(stripNewLineEnding(output)))
+// Enabled reports whether level is enabled for
+// the backing log impl.
+func (l *Log) Enabled(level lg.Level) bool {
+	return lg.Enabled(l.impl, level)
 }
 
 // With implements Log.With.
 func (l *Log) With(key string, val any) lg.Log {
+	key = lg.ValidKey(l, key)
+
 	// We want to prevent duplicate keys. The below code
 	// results in a []keyVal without duplicate keys.
 
@@ -239,8 +397,8 @@ func (l *Log) With(key string, val any) lg.Log {
 
 	// Create a new log instance, and then add each
 	// of kvs using impl.With.
-	buf := &bytes.Buffer{}
-	impl := l.factoryFn(buf)
+	w := &bufWriter{}
+	impl := l.factoryFn(w)
 	for _, kv := range kvs {
 		impl = impl.With(kv.k, kv.v)
 	}
@@ -248,10 +406,48 @@ func (l *Log) With(key string, val any) lg.Log {
 	return &Log{
 		t:         l.t,
 		impl:      impl,
-		buf:       buf,
+		w:         w,
 		factoryFn: l.factoryFn,
 		kvs:       kvs,
+		opts:      l.opts,
+	}
+}
+
+// ForTest returns a Log that pipes output to t, derived from l: it
+// has l's backing log factory, fields and options, but its own backing log
+// impl and buffer. It is intended for parallel subtests, which then
+// share no mutable state with each other, nor with the package-level
+// FactoryFn, which may be changed by other tests:
+//
+//	log := testlg.NewWith(t, factoryFn).With("suite", "db")
+//	for _, tc := range testCases {
+//	  tc := tc
+//	  t.Run(tc.name, func(t *testing.T) {
+//	    t.Parallel()
+//	    log := testlg.ForTest(log, t)
+//	    // ...
+//	  })
+//	}
+func (l *Log) ForTest(t testing.TB) *Log {
+	child := NewWith(t, l.factoryFn)
+	for _, kv := range l.kvs {
+		child.impl = child.impl.With(kv.k, kv.v)
+	}
+	child.kvs = l.kvs
+	child.opts = l.opts
+	child.opts.entries = &atomic.Int64{}
+	child.opts.recorded = &recorded{}
+	return child
+}
+
+// ForTest returns log.ForTest(t) if log is a *Log (such as
+// returned by New or With). Otherwise, it returns New(t).
+func ForTest(log lg.Log, t testing.TB) lg.Log {
+	if l, ok := log.(*Log); ok {
+		return l.ForTest(t)
 	}
+
+	return New(t)
 }
 
 type keyVal struct {
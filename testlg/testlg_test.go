@@ -2,10 +2,18 @@ package testlg_test
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/neilotoole/lg/v2"
 	"github.com/neilotoole/lg/v2/testlg"
 	"github.com/neilotoole/lg/v2/zaplg"
@@ -50,6 +58,165 @@ func TestFactoryFn(t *testing.T) {
 	logItAll(log)
 }
 
+func TestForTest(t *testing.T) {
+	var calls atomic.Int32
+	factoryFn := func(w io.Writer) lg.Log {
+		calls.Add(1)
+		return zaplg.TestingFactoryFn(w)
+	}
+
+	log := testlg.NewWith(t, factoryFn).With("suite", "parallel")
+	calls.Store(0)
+
+	for i := 0; i < 4; i++ {
+		i := i
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			log := testlg.ForTest(log, t).With("i", i)
+			logItAll(log)
+		})
+	}
+
+	t.Cleanup(func() {
+		// Each subtest derives from factoryFn, not from the
+		// package-level FactoryFn: once via ForTest, and once via With.
+		if got := calls.Load(); got != 8 {
+			t.Errorf("factoryFn calls: want 8, got %d", got)
+		}
+	})
+}
+
+func TestForTest_NotTestlg(t *testing.T) {
+	log := testlg.ForTest(lg.Discard(), t)
+	_, ok := log.(*testlg.Log)
+	if !ok {
+		t.Fatalf("want *testlg.Log, got %T", log)
+	}
+	logItAll(log)
+}
+
+// recordingTB is a testing.TB that records the args
+// passed to Log and Error.
+type recordingTB struct {
+	testing.TB
+	mu   sync.Mutex
+	logs []string
+	errs []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Log(args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, fmt.Sprint(args...))
+}
+
+func (r *recordingTB) Error(args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, fmt.Sprint(args...))
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.Error(fmt.Sprintf(format, args...))
+}
+
+func TestWithFailOnError(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	log := testlg.New(tb, testlg.WithFailOnError()).With("k", "v")
+	logItAll(log)
+	testlg.ForTest(log, tb).Errorf("derived")
+
+	require.Len(t, tb.errs, 3)
+	require.Contains(t, tb.errs[0], "Error msg")
+	require.Contains(t, tb.errs[1], "Errorf msg")
+	require.Contains(t, tb.errs[2], "derived")
+	for _, s := range tb.logs {
+		require.NotContains(t, s, "ERROR")
+	}
+
+	tb = &recordingTB{TB: t}
+	logItAll(testlg.New(tb))
+	require.Empty(t, tb.errs)
+}
+
+func TestWithMaxEntries(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	log := testlg.New(tb, testlg.WithMaxEntries(3))
+	log.Debug("1")
+	log.With("k", "v").Debug("2")
+	for i := 0; i < 10; i++ {
+		log.Warn("3+")
+	}
+
+	require.Len(t, tb.logs, 4)
+	require.Contains(t, tb.logs[3], "max entries (3) reached")
+
+	// Each Log derived via ForTest has its own limit.
+	tb2 := &recordingTB{TB: t}
+	testlg.ForTest(log, tb2).Debug("derived")
+	require.Len(t, tb2.logs, 1)
+}
+
+func TestWithStderr(t *testing.T) {
+	got := captureStderr(t, func() {
+		log := testlg.New(t, testlg.WithStderr())
+		log.Debug("mirrored")
+	})
+	require.Contains(t, got, "TestWithStderr: ")
+	require.Contains(t, got, "mirrored")
+
+	t.Setenv(testlg.EnvStderr, "true")
+	got = captureStderr(t, func() {
+		testlg.New(t).Warn("via env")
+	})
+	require.Contains(t, got, "via env")
+
+	t.Setenv(testlg.EnvStderr, "false")
+	got = captureStderr(t, func() {
+		testlg.New(t).Warn("not mirrored")
+	})
+	require.Empty(t, got)
+}
+
+// captureStderr returns the output written to os.Stderr by fn.
+func captureStderr(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	prev := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = prev }()
+
+	fn()
+	require.NoError(t, w.Close())
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestWithElapsed(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	log := testlg.NewWith(tb, testlg.FactoryFn, testlg.WithElapsed()).With("k", "v")
+	log.Debug("hello")
+
+	require.Len(t, tb.logs, 1)
+	require.Regexp(t, regexp.MustCompile(`^\+0\.\d{3}s\tDEBUG\t`), tb.logs[0])
+}
+
+func FuzzForTest(f *testing.F) {
+	log := testlg.NewWith(f, testlg.FactoryFn, testlg.WithMaxEntries(2))
+	f.Add("hello")
+	f.Add("")
+	f.Fuzz(func(t *testing.T, s string) {
+		log := testlg.ForTest(log, t)
+		for i := 0; i < 5; i++ {
+			log.Debugf("%d: %q", i, s)
+		}
+	})
+}
+
 // logItAll executes all the methods of lg.Log.
 func logItAll(log lg.Log) {
 	log.Debug("Debug msg")
@@ -76,3 +243,13 @@ type errCloser struct {
 func (errCloser) Close() error {
 	return errors.New("error: WarnIfCloseError msg")
 }
+
+func BenchmarkLog(b *testing.B) {
+	log := testlg.New(b).With("key", "val")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Errorf("msg %d", i)
+	}
+}
@@ -0,0 +1,152 @@
+package lg
+
+import "io"
+
+// WarnIfErr2 logs err (if non-nil) at WARN level to log, and returns
+// val. The caller of WarnIfErr2 is reported as the caller. If log is
+// nil, err is not logged.
+//
+//	n, err := strconv.Atoi(s)
+//	n = lg.WarnIfErr2(log, n, err)
+//
+// To collapse the call and the check to one line, use Try.
+func WarnIfErr2[T any](log Log, val T, err error) T {
+	if err != nil && log != nil {
+		AddCallerSkip(log, 1).WarnIfError(err)
+	}
+
+	return val
+}
+
+// Must returns val if err is nil. Otherwise, err is logged (see
+// WithError) at ERROR level to log, and Must panics with err.
+// It is intended for initialization that can't sensibly fail.
+// The caller of Must is reported as the caller. If log is nil,
+// Must still panics. To collapse the call and the check to
+// one line, use Try.
+func Must[T any](log Log, val T, err error) T {
+	if err != nil {
+		if log != nil {
+			WithError(AddCallerSkip(log, 1), err).Error(err.Error())
+		}
+		panic(err)
+	}
+
+	return val
+}
+
+// Result holds the results of a value-returning call. Use Try
+// to create a Result.
+type Result[T any] struct {
+	Val T
+	Err error
+}
+
+// Try returns a Result holding val and err. Because Go allows the
+// results of a call to be passed directly as args, Try collapses the
+// common pattern of logging the error of a value-returning call to
+// one line:
+//
+//	n := lg.Try(strconv.Atoi(s)).WarnIf(log)
+//	tmpl := lg.Try(template.New("").Parse(text)).Must(log)
+func Try[T any](val T, err error) Result[T] {
+	return Result[T]{Val: val, Err: err}
+}
+
+// WarnIf is like WarnIfErr2: it logs r.Err (if non-nil) at WARN
+// level to log, and returns r.Val.
+func (r Result[T]) WarnIf(log Log) T {
+	if r.Err != nil && log != nil {
+		AddCallerSkip(log, 1).WarnIfError(r.Err)
+	}
+
+	return r.Val
+}
+
+// Must is like the package func Must: if r.Err is non-nil, it is
+// logged at ERROR level to log, and Must panics with r.Err.
+func (r Result[T]) Must(log Log) T {
+	if r.Err != nil {
+		if log != nil {
+			WithError(AddCallerSkip(log, 1), r.Err).Error(r.Err.Error())
+		}
+		panic(r.Err)
+	}
+
+	return r.Val
+}
+
+// WarnFuncError is like Log.WarnIfFuncError, but it also returns
+// fn's error, so that the caller can both log and propagate it:
+//
+//	if err := lg.WarnFuncError(log, tx.Rollback); err != nil {
+//	  return err
+//	}
+//
+// If fn is nil, WarnFuncError returns nil. The caller of
+// WarnFuncError is reported as the caller.
+func WarnFuncError(log Log, fn func() error) error {
+	if fn == nil {
+		return nil
+	}
+
+	err := fn()
+	if err != nil && log != nil {
+		AddCallerSkip(log, 1).WarnIfError(err)
+	}
+
+	return err
+}
+
+// WarnCloseError is like Log.WarnIfCloseError, but it also returns
+// the error returned by c.Close. If c is nil, WarnCloseError returns
+// nil. The caller of WarnCloseError is reported as the caller.
+func WarnCloseError(log Log, c io.Closer) error {
+	if c == nil {
+		return nil
+	}
+
+	err := c.Close()
+	if err != nil && log != nil {
+		AddCallerSkip(log, 1).WarnIfError(err)
+	}
+
+	return err
+}
+
+// CloseAndLog closes c, logging any error at WARN level to log. If
+// errp is non-nil and *errp is nil, the close error is assigned to
+// *errp. It is designed for use with defer and a named error result,
+// so that a close error (which, for a written file, may mean data
+// loss) is not silently dropped:
+//
+//	func writeFile(log lg.Log, name string) (err error) {
+//	  f, err := os.Create(name)
+//	  if err != nil {
+//	    return err
+//	  }
+//	  defer lg.CloseAndLog(log, f, &err)
+//	  // ...
+//	}
+//
+// If *errp is already non-nil, it is left unchanged: the earlier
+// error takes precedence. If c is nil, CloseAndLog is no-op. The
+// caller of CloseAndLog is reported as the caller.
+func CloseAndLog(log Log, c io.Closer, errp *error) {
+	if c == nil {
+		return
+	}
+
+	err := c.Close()
+	if err == nil {
+		return
+	}
+
+	if log != nil {
+		AddCallerSkip(log, 1).WarnIfError(err)
+	}
+
+	if errp != nil && *errp == nil {
+		*errp = err
+	}
+}
@@ -0,0 +1,113 @@
+package lg_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestWarnIfErr2(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+
+	n, err := strconv.Atoi("42")
+	require.Equal(t, 42, lg.WarnIfErr2(log, n, err))
+	require.Empty(t, buf.String())
+
+	n, err = strconv.Atoi("x")
+	require.Equal(t, 0, lg.WarnIfErr2(log, n, err))
+	require.Equal(t, 0, lg.WarnIfErr2(nil, n, err))
+	require.Equal(t, 0, lg.Try(strconv.Atoi("x")).WarnIf(log))
+	require.Equal(t, 7, lg.Try(strconv.Atoi("7")).WarnIf(nil))
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	for _, m := range ms {
+		require.Equal(t, "warn", m["level"])
+		require.Contains(t, m["message"], "invalid syntax")
+		require.Contains(t, m["caller"], "warnif_test.go:")
+	}
+}
+
+func TestMust(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+
+	require.Equal(t, "ok", lg.Must(log, "ok", nil))
+	require.Equal(t, 7, lg.Try(strconv.Atoi("7")).Must(log))
+
+	err := errors.New("boom")
+	require.PanicsWithError(t, "boom", func() { lg.Must(log, 0, err) })
+	require.PanicsWithError(t, "boom", func() { lg.Must(nil, 0, err) })
+	require.PanicsWithError(t, "boom", func() { lg.Try(0, err).Must(log) })
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	for _, m := range ms {
+		require.Equal(t, "error", m["level"])
+		require.Equal(t, "boom", m["error"])
+		require.Contains(t, m["caller"], "warnif_test.go:")
+	}
+}
+
+func TestWarnFuncError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+	err := errors.New("boom")
+
+	require.NoError(t, lg.WarnFuncError(log, nil))
+	require.NoError(t, lg.WarnFuncError(log, func() error { return nil }))
+	require.Equal(t, err, lg.WarnFuncError(log, func() error { return err }))
+	require.Equal(t, err, lg.WarnFuncError(nil, func() error { return err }))
+
+	require.NoError(t, lg.WarnCloseError(log, nil))
+	calls := 0
+	require.Equal(t, err, lg.WarnCloseError(log, closerFunc(func() error { calls++; return err })))
+	require.Equal(t, 1, calls)
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	for _, m := range ms {
+		require.Equal(t, "warn", m["level"])
+		require.Equal(t, "boom", m["message"])
+		require.Contains(t, m["caller"], "warnif_test.go:")
+	}
+}
+
+func TestCloseAndLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+	closeErr := errors.New("close failed")
+	c := closerFunc(func() error { return closeErr })
+
+	fn := func(retErr error, c io.Closer) (err error) {
+		defer lg.CloseAndLog(log, c, &err)
+		return retErr
+	}
+
+	require.NoError(t, fn(nil, nil))
+	require.NoError(t, fn(nil, closerFunc(func() error { return nil })))
+	require.Equal(t, closeErr, fn(nil, c))
+
+	// The earlier error takes precedence.
+	otherErr := errors.New("other")
+	require.Equal(t, otherErr, fn(otherErr, c))
+
+	lg.CloseAndLog(nil, c, nil)
+
+	ms := testlg.DecodeJSON(t, buf)
+	require.Len(t, ms, 2)
+	for _, m := range ms {
+		require.Equal(t, "warn", m["level"])
+		require.Equal(t, "close failed", m["message"])
+		require.Contains(t, m["caller"], "warnif_test.go:")
+	}
+}
@@ -0,0 +1,94 @@
+package lg
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/neilotoole/lg/v2/internal/goid"
+)
+
+// Watch is a watchdog for slow operations: if the returned done func
+// has not been invoked within threshold, an entry is logged at WARN
+// level with the stack of the goroutine that invoked Watch, which
+// helps diagnose hangs where a debugger isn't available:
+//
+//	done := lg.Watch(ctx, log, 5*time.Second, "sync-users")
+//	defer done()
+//
+// If the operation later completes, done logs a WARN entry with the
+// elapsed time. If ctx is done before threshold, the watch is
+// abandoned. Entries have fields "op" and "elapsed", and the stack
+// is logged as field "stack".
+func Watch(ctx context.Context, log Log, threshold time.Duration, op string) (done func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	log = OrDiscard(log).With("op", op)
+	gid := goid.ID()
+	start := time.Now()
+
+	stop := make(chan struct{})
+	exited := make(chan struct{})
+	var warned, finished atomic.Bool
+
+	go func() {
+		defer close(exited)
+
+		timer := time.NewTimer(threshold)
+		defer timer.Stop()
+
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		warned.Store(true)
+		log.With("elapsed", time.Since(start)).
+			With("stack", goroutineStack(gid)).
+			Warnf("%s has not completed after %s", op, threshold)
+	}()
+
+	return func() {
+		if !finished.CompareAndSwap(false, true) {
+			return
+		}
+
+		close(stop)
+		<-exited
+		if warned.Load() {
+			elapsed := time.Since(start)
+			AddCallerSkip(log, 1).With("elapsed", elapsed).Warnf("%s completed after %s", op, elapsed)
+		}
+	}
+}
+
+// goroutineStack returns the stack of the goroutine with ID gid,
+// or the empty string if that goroutine no longer exists.
+func goroutineStack(gid uint64) string {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	prefix := []byte("goroutine " + strconv.FormatUint(gid, 10) + " ")
+	for _, stanza := range bytes.Split(buf, []byte("\n\n")) {
+		if bytes.HasPrefix(stanza, prefix) {
+			return string(stanza)
+		}
+	}
+
+	return ""
+}
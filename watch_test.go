@@ -0,0 +1,74 @@
+package lg_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/testlg"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+// lockedBuffer is a concurrency-safe bytes.Buffer.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// buffer returns a copy of the buffer.
+func (b *lockedBuffer) buffer() *bytes.Buffer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.NewBuffer(append([]byte(nil), b.buf.Bytes()...))
+}
+
+func TestWatch(t *testing.T) {
+	buf := &lockedBuffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, true, 0)
+
+	// Completes within the threshold: nothing is logged.
+	done := lg.Watch(context.Background(), log, time.Second, "fast-op")
+	done()
+	done()
+	require.Zero(t, buf.buffer().Len())
+
+	// Exceeds the threshold.
+	done = lg.Watch(context.Background(), log, 10*time.Millisecond, "slow-op")
+	require.Eventually(t, func() bool { return buf.buffer().Len() > 0 }, time.Second, 5*time.Millisecond)
+	done()
+
+	ms := testlg.DecodeJSON(t, buf.buffer())
+	require.Len(t, ms, 2)
+	require.Equal(t, "warn", ms[0]["level"])
+	require.Equal(t, "slow-op has not completed after 10ms", ms[0]["message"])
+	require.Equal(t, "slow-op", ms[0]["op"])
+	require.Contains(t, ms[0]["stack"], "TestWatch")
+
+	require.Equal(t, "warn", ms[1]["level"])
+	require.Contains(t, ms[1]["message"], "slow-op completed after")
+	require.Contains(t, ms[1]["caller"], "watch_test.go")
+}
+
+func TestWatch_ContextDone(t *testing.T) {
+	buf := &lockedBuffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := lg.Watch(ctx, log, 10*time.Millisecond, "op")
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+	done()
+
+	require.Zero(t, buf.buffer().Len())
+}
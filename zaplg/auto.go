@@ -0,0 +1,44 @@
+package zaplg
+
+import (
+	"io"
+	"os"
+)
+
+// resolveAutoFormat returns the format for "auto": text, with
+// color unless the NO_COLOR env var is set, if w is a terminal;
+// and json otherwise.
+func resolveAutoFormat(w io.Writer) (format string, color bool) {
+	if !isTerminal(w) {
+		return jsonFormat, false
+	}
+
+	return textFormat, useColor(w)
+}
+
+// useColor reports whether output to w should be colorized: w
+// must be a terminal, and the NO_COLOR env var must not be set.
+func useColor(w io.Writer) bool {
+	if _, noColor := os.LookupEnv("NO_COLOR"); noColor {
+		return false
+	}
+
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a terminal. It is a heuristic: w
+// must be an *os.File that is a character device, which is true of
+// terminals, but also of the likes of /dev/null.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
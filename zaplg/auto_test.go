@@ -0,0 +1,31 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestAutoFormat(t *testing.T) {
+	// A non-terminal writer gets json.
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "auto", false, false, true, false, 0)
+	log.Debug("hello")
+	require.Equal(t, `{"level":"debug","message":"hello"}`+"\n", buf.String())
+
+	// As does a regular file.
+	f, err := os.Create(filepath.Join(t.TempDir(), "log.json"))
+	require.NoError(t, err)
+	log = zaplg.NewWith(f, "auto", false, false, true, false, 0)
+	log.Warn("hello")
+	require.NoError(t, f.Close())
+
+	b, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Equal(t, `{"level":"warn","message":"hello"}`+"\n", string(b))
+}
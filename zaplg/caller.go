@@ -0,0 +1,118 @@
+package zaplg
+
+import (
+	"go.uber.org/zap/zapcore"
+
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+// CallerPath determines how the caller's source file path is
+// rendered. It applies to the "text" and "json" formats, and to
+// templates; the "testing" format does not render the path. Of the
+// paths, lgcore encoders (see WithEncoder) support CallerPathShort,
+// CallerPathModule and CallerPathFuncOnly.
+type CallerPath int
+
+const (
+	// CallerPathShort renders the file's final directory and
+	// name, e.g. "server/http.go". This is the default.
+	CallerPathShort CallerPath = iota
+
+	// CallerPathFull renders the full path, e.g.
+	// "/home/me/app/internal/server/http.go".
+	CallerPathFull
+
+	// CallerPathModule renders the path relative to the root of
+	// the main module (as reported by debug.ReadBuildInfo), e.g.
+	// "internal/server/http.go". Files outside the main module are
+	// rendered relative to the package path, e.g.
+	// "go.uber.org/zap/logger.go". Files in package main fall
+	// back to CallerPathShort.
+	CallerPathModule
+
+	// CallerPathFuncOnly renders neither the path nor the line: only
+	// the calling function, e.g. "server.(*Server).handle", as does the
+	// "testing" format. This suits output viewed via tools that
+	// already show file:line, such as IDEs and the testing package.
+	CallerPathFuncOnly
+)
+
+// WithCallerPath returns an Option that sets how the
+// caller's source file path is rendered.
+func WithCallerPath(p CallerPath) Option {
+	return func(o *options) {
+		o.callerPath = p
+		o.callerDirs = 0
+	}
+}
+
+// WithCallerDirs returns an Option that renders the caller's source
+// file path trimmed to n trailing directories. For example, with
+// n=2, the path is rendered as "internal/server/http.go". A value
+// of 1 is equivalent to CallerPathShort, and a value less than 1
+// renders only the file name.
+func WithCallerDirs(n int) Option {
+	return func(o *options) {
+		o.callerPath = CallerPathShort
+		o.callerDirs = n
+		if n < 1 {
+			// Use -1 to indicate file name only, because
+			// zero indicates that the option is not set.
+			o.callerDirs = -1
+		}
+	}
+}
+
+// callerFn returns the func used to render the caller.
+func (o *options) callerFn() func(caller zapcore.EntryCaller) string {
+	if o.callerDirs == 0 && o.callerPath == CallerPathFuncOnly {
+		return callerPkgFunc
+	}
+
+	pathFn := o.callerPathFn()
+	return func(caller zapcore.EntryCaller) string {
+		return funcCallerString(pathFn, caller)
+	}
+}
+
+// callerPathFn returns the func used to render the caller's path.
+func (o *options) callerPathFn() func(caller zapcore.EntryCaller) string {
+	switch {
+	case o.callerDirs != 0:
+		n := o.callerDirs
+		return func(caller zapcore.EntryCaller) string {
+			return lgcore.TrimDirs(caller.File, n)
+		}
+	case o.callerPath == CallerPathFull:
+		return func(caller zapcore.EntryCaller) string {
+			return caller.File
+		}
+	case o.callerPath == CallerPathModule:
+		return moduleRelPath
+	default:
+		return func(caller zapcore.EntryCaller) string {
+			return lgcore.TrimDirs(caller.File, 1)
+		}
+	}
+}
+
+// moduleRelPath renders the caller's path relative to the main
+// module root. See CallerPathModule.
+func moduleRelPath(caller zapcore.EntryCaller) string {
+	return lgcore.ModulePath(caller.File, caller.Function)
+}
+
+// callerFormat returns the lgcore.CallerFormat corresponding
+// to o's caller path, for use with lgcore encoders.
+func (o *options) callerFormat() lgcore.CallerFormat {
+	if o.callerDirs == 0 {
+		switch o.callerPath {
+		case CallerPathModule:
+			return lgcore.CallerModule
+		case CallerPathFuncOnly:
+			return lgcore.CallerFunc
+		}
+	}
+
+	return lgcore.CallerShort
+}
@@ -0,0 +1,83 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgcore"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestCallerPath(t *testing.T) {
+	testCases := []struct {
+		name      string
+		opt       zaplg.Option
+		wantExact string // expected path, if it is deterministic
+	}{
+		{name: "default", opt: nil, wantExact: "zaplg/caller_test.go"},
+		{name: "short", opt: zaplg.WithCallerPath(zaplg.CallerPathShort), wantExact: "zaplg/caller_test.go"},
+		{name: "full", opt: zaplg.WithCallerPath(zaplg.CallerPathFull)},
+		{name: "module", opt: zaplg.WithCallerPath(zaplg.CallerPathModule), wantExact: "zaplg/caller_test.go"},
+		{name: "dirs_0", opt: zaplg.WithCallerDirs(0), wantExact: "caller_test.go"},
+		{name: "dirs_1", opt: zaplg.WithCallerDirs(1), wantExact: "zaplg/caller_test.go"},
+		{name: "dirs_2", opt: zaplg.WithCallerDirs(2)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			var opts []zaplg.Option
+			if tc.opt != nil {
+				opts = append(opts, tc.opt)
+			}
+
+			log := zaplg.NewWith(buf, "text", false, false, false, true, 0, opts...)
+			log.Debug("msg")
+
+			caller, _, ok := strings.Cut(buf.String(), "\t")
+			require.True(t, ok)
+			require.True(t, strings.HasSuffix(caller, ":TestCallerPath.func1"), caller)
+
+			gotPath := caller[:strings.IndexByte(caller, ':')]
+			switch {
+			case tc.wantExact != "":
+				require.Equal(t, tc.wantExact, gotPath)
+			case tc.name == "full":
+				require.True(t, strings.HasPrefix(gotPath, "/"), gotPath)
+				require.True(t, strings.HasSuffix(gotPath, "/zaplg/caller_test.go"), gotPath)
+			case tc.name == "dirs_2":
+				require.Equal(t, 2, strings.Count(gotPath, "/"), gotPath)
+				require.True(t, strings.HasSuffix(gotPath, "/zaplg/caller_test.go"), gotPath)
+			}
+		})
+	}
+}
+
+func TestCallerPath_Encoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, false, true, 0,
+		zaplg.WithEncoder(lgcore.JSONEncoder{}), zaplg.WithCallerPath(zaplg.CallerPathModule))
+	log.Debug("msg")
+	require.Regexp(t, `"caller":"zaplg/caller_test\.go:\d+"`, buf.String())
+}
+
+func TestCallerPath_FuncOnly(t *testing.T) {
+	opt := zaplg.WithCallerPath(zaplg.CallerPathFuncOnly)
+
+	buf := &bytes.Buffer{}
+	zaplg.NewWith(buf, "text", false, false, false, true, 0, opt).Debug("msg")
+	require.Equal(t, "zaplg_test.TestCallerPath_FuncOnly\tmsg\n", buf.String())
+
+	buf.Reset()
+	zaplg.NewWith(buf, "json", false, false, false, true, 0, opt).Debug("msg")
+	require.Contains(t, buf.String(), `"caller":"zaplg_test.TestCallerPath_FuncOnly"`)
+
+	buf.Reset()
+	zaplg.NewWith(buf, "json", false, false, false, true, 0, opt, zaplg.WithEncoder(lgcore.JSONEncoder{})).Debug("msg")
+	require.Contains(t, buf.String(), `"caller":"zaplg_test.TestCallerPath_FuncOnly"`)
+}
@@ -0,0 +1,48 @@
+package zaplg
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// WithElapsedTime returns an Option that renders the timestamp as the
+// duration elapsed since start, in seconds with millisecond precision,
+// e.g. "+0.042s", instead of the wall-clock time. This is useful in
+// test output, which is then easier to reason about, and to diff.
+// It has no effect with WithEncoder or WithTemplate.
+func WithElapsedTime(start time.Time) Option {
+	return func(o *options) {
+		o.elapsedStart = start
+	}
+}
+
+// elapsedTimeEncoder returns a zapcore.TimeEncoder that
+// renders the duration elapsed since start.
+func elapsedTimeEncoder(start time.Time) zapcore.TimeEncoder {
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(formatElapsed(t.Sub(start)))
+	}
+}
+
+// formatElapsed returns d in "+0.042s" format.
+func formatElapsed(d time.Duration) string {
+	s := strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+	if d >= 0 {
+		s = "+" + s
+	}
+	return s + "s"
+}
+
+// NewTestingFactoryFn returns a func that, like TestingFactoryFn,
+// can be passed to testlg.NewWith to use zap as the backing impl,
+// but which applies opts.
+func NewTestingFactoryFn(opts ...Option) func(w io.Writer) lg.Log {
+	return func(w io.Writer) lg.Log {
+		return NewWith(w, testingFormat, true, true, true, true, 1, opts...)
+	}
+}
@@ -0,0 +1,33 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestWithElapsedTime(t *testing.T) {
+	buf := &bytes.Buffer{}
+	start := time.Now().Add(-1500 * time.Millisecond)
+	log := zaplg.NewWith(buf, "text", true, false, true, false, 0, zaplg.WithElapsedTime(start))
+	log.Debug("hello")
+
+	require.Regexp(t, regexp.MustCompile(`^\+1\.5\d\ds\tDEBUG\thello\n$`), buf.String())
+
+	buf.Reset()
+	log = zaplg.NewWith(buf, "json", true, false, true, false, 0, zaplg.WithElapsedTime(start))
+	log.Debug("hello")
+	require.Regexp(t, regexp.MustCompile(`"timestamp":"\+1\.5\d\ds"`), buf.String())
+}
+
+func TestNewTestingFactoryFn(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fn := zaplg.NewTestingFactoryFn(zaplg.WithElapsedTime(time.Now()))
+	fn(buf).Warn("hello")
+	require.Regexp(t, regexp.MustCompile(`^\+0\.\d{3}s\tWARN\t`), buf.String())
+}
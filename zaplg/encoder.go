@@ -0,0 +1,152 @@
+package zaplg
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+// WithEncoder returns an Option that encodes each entry using enc.
+// This overrides the format arg of NewWith, and allows a custom
+// format implemented via lgcore.Encoder to be used with zaplg.
+// The entry's fields are in the order they were added, and its time
+// and caller are only populated if the timestamp and caller args of NewWith
+// are true. Note that zap's info level maps to lg.LevelDebug,
+// and levels above error map to lg.LevelError.
+func WithEncoder(enc lgcore.Encoder) Option {
+	return func(o *options) {
+		o.enc = enc
+	}
+}
+
+var encoderBufferPool = buffer.NewPool()
+
+// coreEncoder is a zapcore.Encoder that adapts an lgcore.Encoder.
+type coreEncoder struct {
+	*zapcore.MapObjectEncoder
+
+	// keys holds the keys of Fields in the order they were added.
+	// Fields added by zap's core (e.g. via With) are added directly
+	// to Fields: see orderedKeys.
+	keys []string
+
+	enc       lgcore.Encoder
+	timestamp bool
+	caller    bool
+	utc       bool
+
+	callerFormat lgcore.CallerFormat
+}
+
+func newCoreEncoder(enc lgcore.Encoder, timestamp, caller, utc bool, callerFormat lgcore.CallerFormat) *coreEncoder {
+	return &coreEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		enc:              enc,
+		timestamp:        timestamp,
+		caller:           caller,
+		utc:              utc,
+		callerFormat:     callerFormat,
+	}
+}
+
+func (e *coreEncoder) Clone() zapcore.Encoder {
+	clone := newCoreEncoder(e.enc, e.timestamp, e.caller, e.utc, e.callerFormat)
+	clone.keys = e.orderedKeys()
+	for k, v := range e.Fields {
+		clone.Fields[k] = v
+	}
+
+	return clone
+}
+
+// orderedKeys returns a new slice holding keys, followed by the
+// keys of any fields added directly to Fields. As lg adds a single
+// field per With, those are typically the key of the last With; if
+// there are several, they're sorted. It doesn't modify e, which may
+// be in concurrent use.
+func (e *coreEncoder) orderedKeys() []string {
+	keys := make([]string, len(e.keys), len(e.Fields))
+	copy(keys, e.keys)
+	if len(e.Fields) == len(e.keys) {
+		return keys
+	}
+
+	seen := make(map[string]struct{}, len(e.keys))
+	for _, k := range e.keys {
+		seen[k] = struct{}{}
+	}
+
+	n := len(keys)
+	for k := range e.Fields {
+		if _, ok := seen[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys[n:])
+	return keys
+}
+
+func (e *coreEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.Clone().(*coreEncoder) //nolint:errcheck // Clone always returns *coreEncoder
+	for _, f := range fields {
+		f.AddTo(enc)
+		if len(enc.Fields) > len(enc.keys) {
+			enc.keys = append(enc.keys, f.Key)
+		}
+	}
+
+	var t time.Time
+	if e.timestamp {
+		t = ent.Time
+		if e.utc {
+			t = t.UTC()
+		}
+	}
+
+	entry := lgcore.Entry{Time: t, Level: lgLevel(ent.Level), Msg: ent.Message, Fields: make([]lg.Field, len(enc.keys))}
+	for i, k := range enc.keys {
+		entry.Fields[i] = lg.Field{Key: k, Val: enc.Fields[k]}
+	}
+
+	if e.caller && ent.Caller.Defined {
+		entry.Caller = lgcore.Caller{
+			Defined:  true,
+			File:     ent.Caller.File,
+			Line:     ent.Caller.Line,
+			Function: ent.Caller.Function,
+			Format:   e.callerFormat,
+		}
+	}
+
+	// Encode into buf's backing array, rather than into a
+	// separately allocated buffer. If the encoding outgrows
+	// the array, b reallocates it.
+	buf := encoderBufferPool.Get()
+	b := bytes.NewBuffer(buf.Bytes()[:0])
+	if err := e.enc.Encode(b, entry); err != nil {
+		buf.Free()
+		return nil, err
+	}
+
+	buf.Reset()
+	_, _ = buf.Write(b.Bytes())
+	return buf, nil
+}
+
+// lgLevel returns the lg.Level corresponding to level.
+func lgLevel(level zapcore.Level) lg.Level {
+	switch {
+	case level >= zapcore.ErrorLevel:
+		return lg.LevelError
+	case level == zapcore.WarnLevel:
+		return lg.LevelWarn
+	default:
+		return lg.LevelDebug
+	}
+}
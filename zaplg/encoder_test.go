@@ -0,0 +1,33 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgcore"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestWithEncoder(t *testing.T) {
+	enc := lgcore.EncoderFunc(func(buf *bytes.Buffer, ent lgcore.Entry) error {
+		fmt.Fprintf(buf, "%s|%s|%s|%v|%v\n", ent.Level, ent.Caller, ent.Msg, ent.Fields, ent.Time.IsZero())
+		return nil
+	})
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, true, true, true, 0, zaplg.WithEncoder(enc))
+	log.With("b", 2).With("a", 1).Warnf("hello %d", 1)
+	log.Error("oops")
+
+	require.Equal(t, "warn|zaplg/encoder_test.go:22|hello 1|[{b 2} {a 1}]|true\n"+
+		"error|zaplg/encoder_test.go:23|oops|[]|true\n", buf.String())
+
+	buf.Reset()
+	log = zaplg.NewWith(buf, "text", true, true, true, true, 0, zaplg.WithEncoder(lgcore.JSONEncoder{}))
+	log.With("k", "v").Debug("json")
+	require.Regexp(t, `^\{"time":"[^"]+Z","level":"debug","caller":"zaplg/encoder_test.go:\d+","message":"json","k":"v"\}\n$`,
+		buf.String())
+}
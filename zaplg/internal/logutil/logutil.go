@@ -0,0 +1,15 @@
+// Package logutil is a logging helper package, used to
+// test zaplg.WithSkipPackages.
+package logutil
+
+import "github.com/neilotoole/lg/v2"
+
+// Warn logs msg at WARN level via a nested helper.
+func Warn(log lg.Log, msg string) {
+	warn(log, msg)
+}
+
+//go:noinline
+func warn(log lg.Log, msg string) {
+	log.Warn(msg)
+}
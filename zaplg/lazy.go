@@ -0,0 +1,47 @@
+package zaplg
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/neilotoole/lg/v2"
+)
+
+// withField returns a child of logger with field key. zap encodes
+// the fields added via With immediately, so a lg.LazyValue is instead
+// added by lazyCore when each entry is written.
+func withField(logger *zap.Logger, key string, val any) *zap.Logger {
+	lazy, ok := val.(lg.LazyValue)
+	if !ok {
+		return logger.With(zap.Any(key, val))
+	}
+
+	return logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return lazyCore{Core: c, key: key, lazy: lazy}
+	}))
+}
+
+// lazyCore wraps a zapcore.Core, adding a field whose
+// value is computed when each entry is written.
+type lazyCore struct {
+	zapcore.Core
+	key  string
+	lazy lg.LazyValue
+}
+
+func (c lazyCore) With(fields []zapcore.Field) zapcore.Core {
+	c.Core = c.Core.With(fields)
+	return c
+}
+
+func (c lazyCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c lazyCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields = append(fields[:len(fields):len(fields)], zap.Any(c.key, c.lazy.Value()))
+	return c.Core.Write(ent, fields)
+}
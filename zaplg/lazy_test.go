@@ -0,0 +1,37 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestLazy(t *testing.T) {
+	buf := &bytes.Buffer{}
+	depth := 0
+	log := zaplg.NewWith(buf, "json", false, false, false, true, 0, zaplg.WithLevel(lg.LevelWarn)).
+		With("queue_depth", lg.Lazy(func() any {
+			depth++
+			return depth
+		})).
+		With("k", "v")
+
+	log.Debug("disabled")
+	require.Zero(t, depth, "lazy value should not be evaluated for disabled level")
+
+	log.Warn("a")
+	log.Warn("b")
+	require.Equal(t, `{"caller":"zaplg/lazy_test.go:26:TestLazy","message":"a","k":"v","queue_depth":1}
+{"caller":"zaplg/lazy_test.go:27:TestLazy","message":"b","k":"v","queue_depth":2}
+`, buf.String())
+
+	// Replacing the lazy key via With rebuilds the logger.
+	buf.Reset()
+	log.With("queue_depth", lg.Lazy(func() any { return "replaced" })).Warn("c")
+	require.Contains(t, buf.String(), `"queue_depth":"replaced"`)
+	require.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("queue_depth")))
+}
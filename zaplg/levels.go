@@ -0,0 +1,148 @@
+package zaplg
+
+import (
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+// WithPackageLevels returns an Option that sets the minimum level
+// for entries logged from the specified packages, overriding the
+// level set via WithLevel. The map keys are package import paths;
+// a key also matches the package's sub-packages, and the longest
+// matching key wins. For example:
+//
+//	zaplg.WithLevel(lg.LevelWarn),
+//	zaplg.WithPackageLevels(map[string]lg.Level{
+//	  "github.com/me/app/db": lg.LevelDebug,
+//	})
+//
+// Note that the caller is resolved for each entry, even if the
+// caller is not printed.
+func WithPackageLevels(levels map[string]lg.Level) Option {
+	return func(o *options) {
+		if o.pkgLevels == nil {
+			o.pkgLevels = &levelTrie{}
+		}
+
+		for pkg, level := range levels {
+			o.pkgLevels.insert(pkg, level)
+		}
+	}
+}
+
+// levelTrie is a trie of package path segments, used to find the
+// level of the longest matching package path prefix.
+type levelTrie struct {
+	level    lg.Level
+	hasLevel bool
+	children map[string]*levelTrie
+}
+
+func (t *levelTrie) insert(pkg string, level lg.Level) {
+	node := t
+	for _, seg := range strings.Split(strings.Trim(pkg, "/"), "/") {
+		child, ok := node.children[seg]
+		if !ok {
+			if node.children == nil {
+				node.children = map[string]*levelTrie{}
+			}
+			child = &levelTrie{}
+			node.children[seg] = child
+		}
+		node = child
+	}
+
+	node.level = level
+	node.hasLevel = true
+}
+
+// lookup returns the level for the longest prefix of pkg
+// in the trie. If there's no match, ok is false.
+func (t *levelTrie) lookup(pkg string) (level lg.Level, ok bool) {
+	node := t
+	for _, seg := range strings.Split(pkg, "/") {
+		if node = node.children[seg]; node == nil {
+			break
+		}
+
+		if node.hasLevel {
+			level, ok = node.level, true
+		}
+	}
+
+	return level, ok
+}
+
+// minLevel returns the lowest level in the trie, or
+// dflt if that is lower.
+func (t *levelTrie) minLevel(dflt lg.Level) lg.Level {
+	min := dflt
+	if t.hasLevel && t.level < min {
+		min = t.level
+	}
+
+	for _, child := range t.children {
+		min = child.minLevel(min)
+	}
+
+	return min
+}
+
+// pkgLevelCore wraps a zapcore.Core, filtering entries by
+// the level of the calling package. The wrapped core must
+// be enabled for the lowest level in the trie.
+type pkgLevelCore struct {
+	zapcore.Core
+	levels *levelTrie
+	dflt   lg.Level
+
+	// cache maps caller PC to zapcore.Level.
+	cache *sync.Map
+}
+
+func (c pkgLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	c.Core = c.Core.With(fields)
+	return c
+}
+
+func (c pkgLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		// The caller isn't known until after Check, so
+		// filtering by package happens in Write.
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c pkgLevelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level < c.callerLevel(ent.Caller) {
+		return nil
+	}
+
+	return c.Core.Write(ent, fields)
+}
+
+// callerLevel returns the minimum level for caller's package.
+func (c pkgLevelCore) callerLevel(caller zapcore.EntryCaller) zapcore.Level {
+	if !caller.Defined {
+		return zapLevel(c.dflt)
+	}
+
+	if v, ok := c.cache.Load(caller.PC); ok {
+		return v.(zapcore.Level) //nolint:errcheck // only zapcore.Level is stored
+	}
+
+	level, ok := c.levels.lookup(lgcore.FuncPackage(caller.Function))
+	if !ok {
+		level = c.dflt
+	}
+
+	zl := zapLevel(level)
+	c.cache.Store(caller.PC, zl)
+	return zl
+}
@@ -0,0 +1,54 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestWithPackageLevels(t *testing.T) {
+	const thisPkg = "github.com/neilotoole/lg/v2/zaplg_test"
+
+	testCases := []struct {
+		name   string
+		dflt   lg.Level
+		levels map[string]lg.Level
+		want   int // number of lines logged by logItAll
+	}{
+		{name: "no_match", dflt: lg.LevelWarn, levels: map[string]lg.Level{"github.com/other": lg.LevelDebug}, want: 7},
+		{name: "exact", dflt: lg.LevelWarn, levels: map[string]lg.Level{thisPkg: lg.LevelDebug}, want: 9},
+		{name: "prefix", dflt: lg.LevelDebug, levels: map[string]lg.Level{"github.com/neilotoole": lg.LevelError}, want: 2},
+		{name: "longest_prefix", dflt: lg.LevelError, levels: map[string]lg.Level{
+			"github.com/neilotoole": lg.LevelError,
+			thisPkg:                 lg.LevelWarn,
+		}, want: 7},
+		{name: "partial_segment", dflt: lg.LevelWarn, levels: map[string]lg.Level{
+			"github.com/neilotoole/lg/v2/zap": lg.LevelDebug,
+		}, want: 7},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		for _, caller := range []bool{true, false} {
+			caller := caller
+
+			t.Run(tc.name, func(t *testing.T) {
+				buf := &bytes.Buffer{}
+				log := zaplg.NewWith(buf, "text", false, false, true, caller, 0,
+					zaplg.WithLevel(tc.dflt), zaplg.WithPackageLevels(tc.levels))
+
+				logItAll(log)
+				logItAll(log.With("k", "v"))
+				require.Equal(t, tc.want*2, bytes.Count(buf.Bytes(), []byte("\n")))
+				if !caller {
+					require.NotContains(t, buf.String(), "levels_test.go")
+				}
+			})
+		}
+	}
+}
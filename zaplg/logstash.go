@@ -0,0 +1,32 @@
+package zaplg
+
+import (
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+// WithLoggerName returns an Option that sets the value of the
+// "logger_name" field of the "logstash" format. The default is
+// the base name of the program, i.e. filepath.Base(os.Args[0]).
+//
+// The "logstash" format is JSON matching common Logstash and Filebeat
+// pipelines, with keys "@timestamp", "@version" (always "1"), "level"
+// (in upper case), "message", "logger_name" and "caller", followed
+// by the entry's fields.
+func WithLoggerName(name string) Option {
+	return func(o *options) {
+		o.loggerName = name
+	}
+}
+
+// logstashLogger returns logger with the name and
+// @version field of the "logstash" format.
+func logstashLogger(logger *zap.Logger, name string) *zap.Logger {
+	if name == "" && len(os.Args) > 0 {
+		name = filepath.Base(os.Args[0])
+	}
+
+	return logger.Named(name).With(zap.String("@version", "1"))
+}
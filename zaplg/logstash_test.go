@@ -0,0 +1,33 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestLogstashFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "logstash", true, true, true, true, 0, zaplg.WithLoggerName("app"))
+	log.With("k", "v").With("k", "v2").Warn("hello")
+
+	m := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Contains(t, m, "@timestamp")
+	require.Equal(t, "1", m["@version"])
+	require.Equal(t, "WARN", m["level"])
+	require.Equal(t, "hello", m["message"])
+	require.Equal(t, "app", m["logger_name"])
+	require.Equal(t, "v2", m["k"])
+	require.Contains(t, m["caller"], "zaplg/logstash_test.go:")
+
+	buf.Reset()
+	log = zaplg.NewWith(buf, "logstash", false, false, true, false, 0)
+	log.Debug("default name")
+	require.Regexp(t, `^\{"level":"DEBUG","logger_name":"[^"]+","message":"default name","@version":"1"\}\n$`,
+		buf.String())
+}
@@ -0,0 +1,30 @@
+package zaplg
+
+import (
+	"os"
+	"strconv"
+)
+
+// EnvPretty is the name of the environment variable that, if set
+// to a true value (as per strconv.ParseBool), has the same effect
+// as passing WithPretty to NewWith, unless WithEncoder or
+// WithTemplate is passed, or the format is "testing".
+const EnvPretty = "LG_PRETTY"
+
+// WithPretty returns an Option that renders entries for local
+// development via lgcore.PrettyEncoder: a header line, followed by
+// each field on its own indented line, with humanized durations.
+// The output is colorized if the writer is a terminal, and the
+// NO_COLOR env var is not set. This overrides the format arg of
+// NewWith, and WithEncoder. See also EnvPretty.
+func WithPretty() Option {
+	return func(o *options) {
+		o.pretty = true
+	}
+}
+
+// envPretty reports whether EnvPretty is set to a true value.
+func envPretty() bool {
+	ok, _ := strconv.ParseBool(os.Getenv(EnvPretty))
+	return ok
+}
@@ -0,0 +1,36 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/lgcore"
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestWithPretty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, false, 0, zaplg.WithPretty())
+	log.With("elapsed", 92*time.Second).With("attempt", 2).Warn("slow request")
+
+	require.Equal(t, "WARN  slow request\n    elapsed: 1m32s\n    attempt: 2\n", buf.String())
+}
+
+func TestEnvPretty(t *testing.T) {
+	t.Setenv(zaplg.EnvPretty, "1")
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, true, false, 0)
+	log.Debug("hello")
+
+	require.Equal(t, "DEBUG hello\n", buf.String())
+
+	// The env var doesn't override an explicitly chosen encoder.
+	buf.Reset()
+	log = zaplg.NewWith(buf, "json", false, false, true, false, 0, zaplg.WithEncoder(lgcore.JSONEncoder{}))
+	log.Debug("hello")
+	require.Equal(t, `{"level":"debug","message":"hello"}`+"\n", buf.String())
+}
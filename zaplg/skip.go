@@ -0,0 +1,91 @@
+package zaplg
+
+import (
+	"runtime"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/neilotoole/lg/v2/lgcore"
+)
+
+// WithSkipPackages returns an Option that skips stack frames belonging
+// to the named packages when determining the caller, for example:
+//
+//	log := zaplg.NewWith(w, "json", true, true, true, true, 0,
+//	  zaplg.WithSkipPackages("github.com/me/app/logutil"))
+//
+// That is, if the caller is in a skipped package (such as a team's
+// own logging helpers), the stack is walked outward to the first frame
+// that is not, which is reported as the caller. This avoids the need
+// for AddCallerSkip arithmetic in wrapper packages. Package paths
+// must match exactly: subpackages are not skipped. Note that the
+// stack is only walked when the caller is in a skipped package.
+func WithSkipPackages(pkgs ...string) Option {
+	return func(o *options) {
+		if o.skipPkgs == nil {
+			o.skipPkgs = make(map[string]struct{}, len(pkgs))
+		}
+
+		for _, pkg := range pkgs {
+			o.skipPkgs[pkg] = struct{}{}
+		}
+	}
+}
+
+// skipCore wraps a zapcore.Core, replacing an entry's caller
+// if it belongs to a skipped package. The caller isn't
+// known until after Check, so this happens in Write.
+type skipCore struct {
+	zapcore.Core
+	pkgs map[string]struct{}
+}
+
+func (c skipCore) With(fields []zapcore.Field) zapcore.Core {
+	c.Core = c.Core.With(fields)
+	return c
+}
+
+func (c skipCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c skipCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Caller.Defined {
+		if _, ok := c.pkgs[lgcore.FuncPackage(ent.Caller.Function)]; ok {
+			ent.Caller = c.skip(ent.Caller)
+		}
+	}
+
+	return c.Core.Write(ent, fields)
+}
+
+// skip walks the stack outward from caller, returning the first
+// frame that does not belong to a skipped package. If caller is
+// not found on the stack, it is returned unchanged.
+func (c skipCore) skip(caller zapcore.EntryCaller) zapcore.EntryCaller {
+	var pcs [64]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+
+	found := false
+	for {
+		frame, more := frames.Next()
+		if !found {
+			found = frame.Function == caller.Function && frame.File == caller.File && frame.Line == caller.Line
+		} else if _, ok := c.pkgs[lgcore.FuncPackage(frame.Function)]; !ok {
+			return zapcore.EntryCaller{
+				Defined:  true,
+				PC:       frame.PC,
+				File:     frame.File,
+				Line:     frame.Line,
+				Function: frame.Function,
+			}
+		}
+
+		if !more {
+			return caller
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/zaplg"
+	"github.com/neilotoole/lg/v2/zaplg/internal/logutil"
+)
+
+func TestWithSkipPackages(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "text", false, false, false, true, 0,
+		zaplg.WithSkipPackages("github.com/neilotoole/lg/v2/zaplg/internal/logutil"),
+		zaplg.WithPackageLevels(map[string]lg.Level{"github.com/neilotoole/lg/v2/zaplg/internal/logutil": lg.LevelError}))
+
+	logutil.Warn(log.With("k", "v"), "via helper")
+	log.Warn("direct")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Regexp(t, `^zaplg/skip_test.go:\d+:TestWithSkipPackages\tvia helper`, lines[0])
+	require.Regexp(t, `^zaplg/skip_test.go:\d+:TestWithSkipPackages\tdirect`, lines[1])
+
+	buf.Reset()
+	log = zaplg.NewWith(buf, "text", false, false, false, true, 0)
+	logutil.Warn(log, "not skipped")
+	require.Regexp(t, `^logutil/logutil.go:\d+:warn\tnot skipped`, buf.String())
+}
@@ -0,0 +1,51 @@
+package zaplg
+
+import (
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithSystemdPriority returns an Option that prefixes each entry with
+// its systemd priority: "<7>" for debug, "<4>" for warn, and "<3>" for
+// error. When a service logs to stdout or stderr under systemd, the
+// journal strips the prefix, and records the entry with the correct
+// priority. Note that only the first line of a multi-line entry
+// is prefixed; systemd treats subsequent lines as separate entries.
+func WithSystemdPriority() Option {
+	return func(o *options) {
+		o.systemd = true
+	}
+}
+
+var systemdBufferPool = buffer.NewPool()
+
+// systemdEncoder is a zapcore.Encoder that prefixes each
+// entry with its systemd priority.
+type systemdEncoder struct {
+	zapcore.Encoder
+}
+
+func (e systemdEncoder) Clone() zapcore.Encoder {
+	return systemdEncoder{Encoder: e.Encoder.Clone()}
+}
+
+func (e systemdEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	b, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Free()
+
+	buf := systemdBufferPool.Get()
+	switch {
+	case ent.Level >= zapcore.ErrorLevel:
+		buf.AppendString("<3>")
+	case ent.Level == zapcore.WarnLevel:
+		buf.AppendString("<4>")
+	default:
+		buf.AppendString("<7>")
+	}
+
+	_, _ = buf.Write(b.Bytes())
+	return buf, nil
+}
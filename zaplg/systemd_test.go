@@ -0,0 +1,21 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestWithSystemdPriority(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "text", false, false, true, false, 0, zaplg.WithSystemdPriority())
+
+	log.With("k", "v").Debug("debug")
+	log.Warn("warn")
+	log.Error("error")
+
+	require.Equal(t, "<7>DEBUG\tdebug\t{\"k\": \"v\"}\n<4>WARN\twarn\n<3>ERROR\terror\n", buf.String())
+}
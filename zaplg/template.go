@@ -0,0 +1,159 @@
+package zaplg
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithTemplate returns an Option that renders each entry using tmpl,
+// which is executed with a TemplateEntry. This overrides the format
+// arg of NewWith, and allows matching a legacy log line format
+// exactly. For example:
+//
+//	tmpl := template.Must(template.New("").Parse(
+//	  "{{.Time}} {{.Level}} [{{.Caller}}] {{.Msg}} {{.Fields}}"))
+//	log := zaplg.NewWith(w, "text", true, false, true, true, 0, zaplg.WithTemplate(tmpl))
+//
+// A newline is appended to each entry if tmpl doesn't end with one.
+// Note that the caller is only available if the caller arg of
+// NewWith is true.
+func WithTemplate(tmpl *template.Template) Option {
+	return func(o *options) {
+		o.tmpl = tmpl
+	}
+}
+
+// TemplateEntry is the data passed to the template
+// set via WithTemplate.
+type TemplateEntry struct {
+	// Time is the entry's time.
+	Time TemplateTime
+
+	// Level is the entry's level in upper case, e.g. "WARN".
+	Level string
+
+	// Caller is the entry's caller, in path:line:func format (or
+	// func format, with CallerPathFuncOnly), or empty if the caller
+	// is not available.
+	Caller string
+
+	// Msg is the entry's message.
+	Msg string
+
+	// Fields is the entry's fields, as space-separated key=value
+	// pairs, sorted by key. Values that contain spaces, '=' or '"'
+	// are quoted.
+	Fields string
+
+	// FieldMap holds the entry's field values by key,
+	// e.g. {{.FieldMap.request_id}}.
+	FieldMap map[string]any
+}
+
+// TemplateTime is a time.Time whose String method
+// uses RFC3339 format with millisecond precision.
+type TemplateTime struct {
+	time.Time
+}
+
+// String implements fmt.Stringer.
+func (t TemplateTime) String() string {
+	return t.Format(rfc3339Milli)
+}
+
+var templateBufferPool = buffer.NewPool()
+
+// templateEncoder is a zapcore.Encoder that renders
+// entries using a text/template.
+type templateEncoder struct {
+	*zapcore.MapObjectEncoder
+	tmpl     *template.Template
+	callerFn func(caller zapcore.EntryCaller) string
+	utc      bool
+}
+
+func newTemplateEncoder(tmpl *template.Template, callerFn func(caller zapcore.EntryCaller) string,
+	utc bool,
+) *templateEncoder {
+	return &templateEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder(), tmpl: tmpl, callerFn: callerFn, utc: utc}
+}
+
+func (e *templateEncoder) Clone() zapcore.Encoder {
+	clone := newTemplateEncoder(e.tmpl, e.callerFn, e.utc)
+	for k, v := range e.Fields {
+		clone.Fields[k] = v
+	}
+
+	return clone
+}
+
+func (e *templateEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.Clone().(*templateEncoder) //nolint:errcheck // Clone always returns *templateEncoder
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	t := ent.Time
+	if e.utc {
+		t = t.UTC()
+	}
+
+	data := TemplateEntry{
+		Time:     TemplateTime{Time: t},
+		Level:    ent.Level.CapitalString(),
+		Msg:      ent.Message,
+		Fields:   formatFields(enc.Fields),
+		FieldMap: enc.Fields,
+	}
+
+	if ent.Caller.Defined {
+		data.Caller = e.callerFn(ent.Caller)
+	}
+
+	buf := templateBufferPool.Get()
+	if err := e.tmpl.Execute(buf, data); err != nil {
+		buf.Free()
+		return nil, err
+	}
+
+	if b := buf.Bytes(); len(b) == 0 || b[len(b)-1] != '\n' {
+		buf.AppendByte('\n')
+	}
+
+	return buf, nil
+}
+
+// formatFields returns fields as space-separated
+// key=value pairs, sorted by key.
+func formatFields(fields map[string]any) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+
+		v := fmt.Sprint(fields[k])
+		if v == "" || strings.ContainsAny(v, ` ="`) {
+			v = strconv.Quote(v)
+		}
+
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(v)
+	}
+
+	return sb.String()
+}
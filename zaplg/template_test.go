@@ -0,0 +1,36 @@
+package zaplg_test
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/neilotoole/lg/v2/zaplg"
+)
+
+func TestWithTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(
+		"{{.Level}} [{{.Caller}}] {{.Msg}} {{.Fields}} user={{.FieldMap.user}}"))
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "text", true, true, true, true, 0, zaplg.WithTemplate(tmpl))
+
+	log.With("user", "alice").With("note", "a b").Warnf("hello %d", 1)
+	log.Debug("plain")
+
+	require.Equal(t, `WARN [zaplg/template_test.go:20:TestWithTemplate] hello 1 note="a b" user=alice user=alice
+DEBUG [zaplg/template_test.go:21:TestWithTemplate] plain  user=<no value>
+`, buf.String())
+}
+
+func TestWithTemplate_Time(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse("{{.Time}}|{{.Time.Format \"2006\"}}\n"))
+
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "text", true, true, true, false, 0, zaplg.WithTemplate(tmpl))
+	log.Debug("x")
+
+	require.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}Z\|\d{4}\n$`, buf.String())
+}
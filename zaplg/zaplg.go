@@ -6,20 +6,26 @@ import (
 	"io"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/neilotoole/lg/v2"
+	"github.com/neilotoole/lg/v2/internal/goid"
+	"github.com/neilotoole/lg/v2/lgcore"
 )
 
 const (
-	jsonFormat    = "json"
-	textFormat    = "text"
-	testingFormat = "testing"
+	jsonFormat     = "json"
+	textFormat     = "text"
+	testingFormat  = "testing"
+	logstashFormat = "logstash"
+	autoFormat     = "auto"
 )
 
 // rfc3339Milli is an RFC3339 format with millisecond precision.
@@ -44,12 +50,31 @@ func timeEncoderOfLayout(layout string, utc bool) zapcore.TimeEncoder {
 }
 
 // NewWith returns a Log that writes to w. Format should be one
-// of "json", "text", or "testing"; defaults to "text". The timestamp, level
+// of "json", "text", "testing", "logstash" (see WithLoggerName), or
+// "auto" (colorized text if w is a terminal, and json otherwise);
+// defaults to "text". The timestamp, level
 // and caller params determine if those fields are reported. If timestamp is
 // true and utc is also true, the timestamp is displayed in UTC time.
 // The addCallerSkip param is used to adjust the frame
-// reported as the caller.
-func NewWith(w io.Writer, format string, timestamp, utc, level, caller bool, addCallerSkip int) *Log {
+// reported as the caller. Additional behavior can be configured
+// via opts.
+func NewWith(w io.Writer, format string, timestamp, utc, level, caller bool, addCallerSkip int,
+	opts ...Option,
+) *Log {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.pretty || (o.enc == nil && o.tmpl == nil && format != testingFormat && envPretty()) {
+		o.enc = lgcore.PrettyEncoder{Color: useColor(w)}
+	}
+
+	var color bool
+	if format == autoFormat {
+		format, color = resolveAutoFormat(w)
+	}
+
 	encoderCfg := zapcore.EncoderConfig{
 		MessageKey:     "message",
 		EncodeDuration: zapcore.StringDurationEncoder,
@@ -60,13 +85,23 @@ func NewWith(w io.Writer, format string, timestamp, utc, level, caller bool, add
 		if format == testingFormat {
 			encoderCfg.EncodeCaller = testingCallerEncoder
 		} else {
-			encoderCfg.EncodeCaller = funcCallerEncoder
+			encoderCfg.EncodeCaller = newCallerEncoder(o.callerFn())
 		}
 	}
 
 	if timestamp {
 		encoderCfg.TimeKey = "timestamp"
 		encoderCfg.EncodeTime = timeEncoderOfLayout(rfc3339Milli, utc)
+		if !o.elapsedStart.IsZero() {
+			encoderCfg.EncodeTime = elapsedTimeEncoder(o.elapsedStart)
+		}
+	}
+
+	if format == logstashFormat {
+		encoderCfg.NameKey = "logger_name"
+		if timestamp {
+			encoderCfg.TimeKey = "@timestamp"
+		}
 	}
 
 	if level {
@@ -74,32 +109,141 @@ func NewWith(w io.Writer, format string, timestamp, utc, level, caller bool, add
 	}
 
 	switch {
-	case format == textFormat, format == testingFormat:
+	case color:
+		encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	case format == textFormat, format == testingFormat, format == logstashFormat:
 		encoderCfg.EncodeLevel = zapcore.CapitalLevelEncoder
 	default:
 		encoderCfg.EncodeLevel = zapcore.LowercaseLevelEncoder
 	}
 
 	writeSyncer := zapcore.AddSync(w)
-	zLevel := zap.NewAtomicLevelAt(zap.DebugLevel)
-	var core zapcore.Core
+	minLevel := o.level
+	if o.pkgLevels != nil {
+		minLevel = o.pkgLevels.minLevel(o.level)
+	}
+	zLevel := zap.NewAtomicLevelAt(zapLevel(minLevel))
+	var enc zapcore.Encoder
 
-	switch format {
-	case jsonFormat:
-		core = zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), writeSyncer, zLevel)
+	switch {
+	case o.enc != nil:
+		enc = newCoreEncoder(o.enc, timestamp, caller, utc, o.callerFormat())
+	case o.tmpl != nil:
+		enc = newTemplateEncoder(o.tmpl, o.callerFn(), utc)
+	case format == jsonFormat, format == logstashFormat:
+		enc = zapcore.NewJSONEncoder(encoderCfg)
 	default: // case text
-		core = zapcore.NewCore(zapcore.NewConsoleEncoder(encoderCfg), writeSyncer, zLevel)
+		enc = zapcore.NewConsoleEncoder(encoderCfg)
+	}
+
+	if o.systemd {
+		enc = systemdEncoder{Encoder: enc}
+	}
+
+	core := zapcore.NewCore(enc, writeSyncer, zLevel)
+	if o.goroutineID {
+		core = goroutineCore{Core: core}
+	}
+
+	if o.pkgLevels != nil {
+		core = pkgLevelCore{Core: core, levels: o.pkgLevels, dflt: o.level, cache: &sync.Map{}}
+	}
+
+	if len(o.skipPkgs) > 0 {
+		// Outermost, so that the inner cores see the adjusted caller.
+		core = skipCore{Core: core, pkgs: o.skipPkgs}
 	}
 
 	logger := zap.New(core)
-	if caller {
+	if caller || o.pkgLevels != nil {
+		// Note that when pkgLevels is set, the caller is always
+		// determined, but it is only printed if arg caller is true.
 		logger = logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(addCallerSkip))
 	}
 
+	if format == logstashFormat {
+		logger = logstashLogger(logger, o.loggerName)
+	}
+
 	sugarLogger := logger.Sugar()
 	return &Log{SugaredLogger: sugarLogger, proto: logger}
 }
 
+// Option is a functional option for NewWith.
+type Option func(o *options)
+
+// options holds the values set by Option funcs.
+type options struct {
+	goroutineID bool
+	level       lg.Level
+	pkgLevels   *levelTrie
+	callerPath  CallerPath
+	callerDirs  int
+	tmpl        *template.Template
+	enc         lgcore.Encoder
+	systemd     bool
+	loggerName  string
+	skipPkgs    map[string]struct{}
+
+	elapsedStart time.Time
+	pretty       bool
+}
+
+// WithLevel returns an Option that sets the minimum level
+// of entries that are logged. The default is lg.LevelDebug.
+func WithLevel(level lg.Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// zapLevel returns the zapcore.Level corresponding to level.
+func zapLevel(level lg.Level) zapcore.Level {
+	switch level {
+	case lg.LevelWarn:
+		return zapcore.WarnLevel
+	case lg.LevelError:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+// WithGoroutineID returns an Option that adds a "goroutine" field,
+// holding the ID of the logging goroutine, to each log entry. This
+// is useful when tracing interleaved concurrent operations. Note
+// that determining the goroutine ID is relatively expensive.
+func WithGoroutineID() Option {
+	return func(o *options) {
+		o.goroutineID = true
+	}
+}
+
+// goroutineCore wraps a zapcore.Core, adding the goroutine ID
+// field to each entry at write time.
+type goroutineCore struct {
+	zapcore.Core
+}
+
+func (c goroutineCore) With(fields []zapcore.Field) zapcore.Core {
+	return goroutineCore{Core: c.Core.With(fields)}
+}
+
+func (c goroutineCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c goroutineCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	// Don't append to fields directly: we don't own its backing array.
+	all := make([]zapcore.Field, len(fields), len(fields)+1)
+	copy(all, fields)
+	all = append(all, zap.Uint64("goroutine", goid.ID()))
+	return c.Core.Write(ent, all)
+}
+
 // Log wraps zap's logger, adding the WarnIf_ functions.
 type Log struct {
 	*zap.SugaredLogger
@@ -129,19 +273,42 @@ func (l *Log) WarnIfError(err error) {
 		return
 	}
 
-	logger := l.Desugar().WithOptions(zap.AddCallerSkip(1))
-	logger.Warn(err.Error())
+	l.warnError(err)
+}
+
+// warnError logs err at WARN level, along with any fields returned
+// by lg.ErrorFields. It must be invoked directly by the WarnIf
+// methods, so that the caller skip is correct.
+func (l *Log) warnError(err error) {
+	errFields := lg.ErrorFields(err)
+	fields := make([]zap.Field, len(errFields))
+	for i, f := range errFields {
+		fields[i] = zap.Any(f.Key, f.Val)
+	}
+
+	logger := l.Desugar().WithOptions(zap.AddCallerSkip(2))
+	logger.Warn(err.Error(), fields...)
+}
+
+// Enabled reports whether level is enabled.
+func (l *Log) Enabled(level lg.Level) bool {
+	return l.Desugar().Core().Enabled(zapLevel(level))
 }
 
 // AddCallerSkip adds additional caller skip.
 func (l *Log) AddCallerSkip(skip int) lg.Log {
-	return &Log{
-		SugaredLogger: l.Desugar().WithOptions(zap.AddCallerSkip(skip)).Sugar(),
-		proto:         l.proto,
-		kvs:           l.kvs,
-		callerSkip:    l.callerSkip + skip,
-	}
+	return l.child(l.Desugar().WithOptions(zap.AddCallerSkip(skip)), l.kvs, l.callerSkip+skip)
 }
+
+// child returns a child of l with impl logger, fields kvs, and
+// the given total caller skip. Child logs must always be constructed
+// via child, so that the state of l (such as proto, which holds the
+// constructor options) survives arbitrary chains of With and
+// AddCallerSkip.
+func (l *Log) child(logger *zap.Logger, kvs []keyVal, callerSkip int) *Log {
+	return &Log{SugaredLogger: logger.Sugar(), proto: l.proto, kvs: kvs, callerSkip: callerSkip}
+}
+
 func (l *Log) WarnIfFuncError(fn func() error) {
 	if fn == nil {
 		return
@@ -152,8 +319,7 @@ func (l *Log) WarnIfFuncError(fn func() error) {
 		return
 	}
 
-	logger := l.Desugar().WithOptions(zap.AddCallerSkip(1))
-	logger.Warn(err.Error())
+	l.warnError(err)
 }
 
 func (l *Log) WarnIfCloseError(c io.Closer) {
@@ -166,11 +332,12 @@ func (l *Log) WarnIfCloseError(c io.Closer) {
 		return
 	}
 
-	logger := l.Desugar().WithOptions(zap.AddCallerSkip(1))
-	logger.Warn(err.Error())
+	l.warnError(err)
 }
 
 func (l *Log) With(key string, val any) lg.Log {
+	key = lg.ValidKey(l, key)
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -180,7 +347,6 @@ func (l *Log) With(key string, val any) lg.Log {
 	// below works around that.
 
 	var kvs []keyVal
-	var impl *zap.SugaredLogger
 	keyIndex := -1
 
 	for i, kv := range l.kvs {
@@ -192,13 +358,11 @@ func (l *Log) With(key string, val any) lg.Log {
 
 	if keyIndex == -1 {
 		// Key does not exist.
-		impl = l.SugaredLogger.With(key, val)
-
 		kvs = make([]keyVal, len(l.kvs)+1)
 		copy(kvs, l.kvs)
 		kvs[len(kvs)-1] = keyVal{k: key, v: val}
 
-		return &Log{proto: l.proto, kvs: kvs, SugaredLogger: impl, callerSkip: l.callerSkip}
+		return l.child(withField(l.Desugar(), key, val), kvs, l.callerSkip)
 	}
 
 	// Key does exists. We make a copy of l.kvs and set
@@ -207,17 +371,13 @@ func (l *Log) With(key string, val any) lg.Log {
 	copy(kvs, l.kvs)
 	kvs[keyIndex].v = val
 
-	// Builds args slice for kvs
-	args := make([]any, len(kvs)*2)
-	for i := 0; i < len(kvs); i++ {
-		args[i*2] = kvs[i].k
-		args[(i*2)+1] = kvs[i].v
-	}
-
 	// Use the proto to build the new logger.
-	impl = l.proto.WithOptions(zap.AddCallerSkip(l.callerSkip)).Sugar().With(args...)
+	logger := l.proto.WithOptions(zap.AddCallerSkip(l.callerSkip))
+	for _, kv := range kvs {
+		logger = withField(logger, kv.k, kv.v)
+	}
 
-	return &Log{proto: l.proto, kvs: kvs, SugaredLogger: impl, callerSkip: l.callerSkip}
+	return l.child(logger, kvs, l.callerSkip)
 }
 
 // TestingFactoryFn can be passed to testlg.NewWith to
@@ -228,23 +388,30 @@ var TestingFactoryFn = func(w io.Writer) lg.Log {
 	return NewWith(w, testingFormat, true, true, true, true, 1)
 }
 
-// funcCallerEncoder extends the behavior of zapcore.ShortCallerEncoder
-// to also include the calling function name. That is, it
-// serializes the caller in package/file:line:func format,
-// trimming all but the final directory from the full path.
+// newCallerEncoder returns an encoder that serializes the caller
+// as rendered by callerFn (see options.callerFn). By default, this
+// extends the behavior of zapcore.ShortCallerEncoder to also include
+// the calling function name, in path/file:line:func format.
 // This implementation is probably not very efficient, so
 // use with caution.
-func funcCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
-	if !caller.Defined {
-		return
+func newCallerEncoder(callerFn func(caller zapcore.EntryCaller) string) zapcore.CallerEncoder {
+	return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+		if !caller.Defined {
+			return
+		}
+
+		enc.AppendString(callerFn(caller))
 	}
+}
 
-	frame, _ := runtime.CallersFrames([]uintptr{caller.PC}).Next()
+// funcCallerString returns caller in path:line:func format.
+func funcCallerString(pathFn func(caller zapcore.EntryCaller) string, caller zapcore.EntryCaller) string {
+	fn := callerFunction(caller)
 	// ditch the path
-	s := frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+	s := fn[strings.LastIndex(fn, "/")+1:]
 	// and ditch the package
 	s = s[strings.IndexRune(s, '.')+1:]
-	enc.AppendString(caller.TrimmedPath() + ":" + s)
+	return pathFn(caller) + ":" + strconv.Itoa(caller.Line) + ":" + s
 }
 
 // funcCallerEncoder serializes the caller in package.func format.
@@ -257,8 +424,25 @@ func testingCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArray
 		return
 	}
 
-	frame, _ := runtime.CallersFrames([]uintptr{caller.PC}).Next()
+	enc.AppendString("[" + callerPkgFunc(caller) + "]")
+}
+
+// callerPkgFunc returns caller in package.func format,
+// e.g. "server.(*Server).handle".
+func callerPkgFunc(caller zapcore.EntryCaller) string {
+	fn := callerFunction(caller)
 	// ditch the path
-	s := "[" + frame.Function[strings.LastIndex(frame.Function, "/")+1:] + "]"
-	enc.AppendString(s)
+	return fn[strings.LastIndex(fn, "/")+1:]
+}
+
+// callerFunction returns the fully qualified func name of caller.
+// The name is determined from the PC only if caller.Function is
+// empty, because the PC of an inlined call is ambiguous.
+func callerFunction(caller zapcore.EntryCaller) string {
+	if caller.Function != "" {
+		return caller.Function
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{caller.PC}).Next()
+	return frame.Function
 }
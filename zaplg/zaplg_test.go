@@ -1,11 +1,14 @@
 package zaplg_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 
@@ -69,6 +72,56 @@ func TestTestingFactoryFn(t *testing.T) {
 	logItAll(log)
 }
 
+func TestWithLevel(t *testing.T) {
+	testCases := []struct {
+		level lg.Level
+		want  int
+	}{
+		{level: lg.LevelDebug, want: 9},
+		{level: lg.LevelWarn, want: 7},
+		{level: lg.LevelError, want: 2},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.level.String(), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			log := zaplg.NewWith(buf, "text", false, false, true, false, 0, zaplg.WithLevel(tc.level))
+			logItAll(log)
+			require.Equal(t, tc.want, bytes.Count(buf.Bytes(), []byte("\n")))
+		})
+	}
+}
+
+func TestWithGoroutineID(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := zaplg.NewWith(buf, "json", false, false, false, false, 0, zaplg.WithGoroutineID())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		log.With("k", "v").Debug("from goroutine")
+	}()
+	<-done
+	log.Debug("from test")
+
+	dec := json.NewDecoder(buf)
+	var ids []float64
+	for dec.More() {
+		m := map[string]any{}
+		require.NoError(t, dec.Decode(&m))
+		require.Contains(t, m, "goroutine")
+		id, ok := m["goroutine"].(float64)
+		require.True(t, ok)
+		require.NotZero(t, id)
+		ids = append(ids, id)
+	}
+
+	require.Len(t, ids, 2)
+	require.NotEqual(t, ids[0], ids[1])
+}
+
 // TestZapTestVsTestLg demonstrates the incorrect
 // caller info reported by the testing framework when
 // using zaptest as opposed to testlg.
@@ -116,3 +169,83 @@ type errCloser struct {
 func (errCloser) Close() error {
 	return errors.New("error: WarnIfCloseError msg")
 }
+
+// disabledLogs returns Logs for which DEBUG and WARN levels are disabled.
+func disabledLogs() map[string]*zaplg.Log {
+	return map[string]*zaplg.Log{
+		"default": zaplg.NewWith(io.Discard, "json", true, false, true, true, 0,
+			zaplg.WithLevel(lg.LevelError)),
+		"goroutine_id": zaplg.NewWith(io.Discard, "json", true, false, true, true, 0,
+			zaplg.WithLevel(lg.LevelError), zaplg.WithGoroutineID()),
+		"with": zaplg.NewWith(io.Discard, "json", true, false, true, true, 0,
+			zaplg.WithLevel(lg.LevelError)).With("k", "v").(*zaplg.Log),
+	}
+}
+
+func TestDisabled_NoAllocs(t *testing.T) {
+	for name, log := range disabledLogs() {
+		log := log
+
+		t.Run(name, func(t *testing.T) {
+			require.Zero(t, testing.AllocsPerRun(100, func() {
+				log.Debug("msg")
+				log.Debugf("msg %s %d", "x", 1)
+				log.Warnf("msg %s", "x")
+				log.WarnIfError(nil)
+				_ = log.Enabled(lg.LevelDebug)
+			}))
+		})
+	}
+}
+
+func BenchmarkDisabled(b *testing.B) {
+	for name, log := range disabledLogs() {
+		log := log
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				log.Debugf("msg %s", "x")
+			}
+		})
+	}
+}
+
+// logVia logs via a helper func, for caller skip tests.
+func logVia(log lg.Log, msg string) {
+	log.Warn(msg)
+}
+
+func TestWith_AddCallerSkip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	base := zaplg.NewWith(buf, "logstash", false, false, true, true, 0,
+		zaplg.WithLevel(lg.LevelWarn), zaplg.WithGoroutineID(), zaplg.WithLoggerName("app"))
+
+	// Caller skip, fields, level, and constructor options must
+	// survive arbitrary chains, including when With rebuilds the
+	// logger for an existing key.
+	chains := map[string]lg.Log{
+		"with_skip":          lg.AddCallerSkip(base.With("a", 1), 1),
+		"skip_with_existing": lg.AddCallerSkip(base.With("a", 0), 1).With("b", 2).With("a", 1),
+		"existing_skip_with": lg.AddCallerSkip(base.With("a", 0).With("a", 1), 1).With("b", 2),
+		"skip_zero_existing": lg.AddCallerSkip(lg.AddCallerSkip(base, 2).With("a", 0), -1).With("a", 1),
+	}
+
+	for name, log := range chains {
+		log := log
+		t.Run(name, func(t *testing.T) {
+			buf.Reset()
+			log.Debug("disabled")
+			logVia(log, "hello")
+
+			m := map[string]any{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &m), buf.String())
+			require.Equal(t, "hello", m["message"])
+			require.Equal(t, float64(1), m["a"])
+			require.Equal(t, "1", m["@version"])
+			require.Equal(t, "app", m["logger_name"])
+			require.Contains(t, m, "goroutine")
+			require.Regexp(t, `^zaplg/zaplg_test.go:\d+:TestWith_AddCallerSkip`, m["caller"])
+		})
+	}
+}